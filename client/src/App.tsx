@@ -1,11 +1,16 @@
 import { useEffect } from 'react';
-import { BrowserRouter as Router, Routes, Route } from 'react-router-dom';
+import { BrowserRouter as Router, Routes, Route, useNavigate } from 'react-router-dom';
 import { MotorcycleList } from './pages/MotorcycleList';
 import { MotorcycleDetail } from './pages/MotorcycleDetail';
 import { initializeTelegramWebApp } from './utils/telegram';
 import { analytics } from './utils/analytics';
 
+// Префикс параметра запуска из уведомлений бота, за ним следует ID мотоцикла
+const MOTORCYCLE_START_PREFIX = 'moto_';
+
 function AppContent() {
+  const navigate = useNavigate();
+
   // Инициализация Telegram WebApp и аналитики
   useEffect(() => {
     // Инициализируем Telegram WebApp
@@ -13,7 +18,13 @@ function AppContent() {
     
     // Записываем заход пользователя
     analytics.recordVisit();
-  }, []);
+
+    // Открываем мотоцикл, если приложение запущено кнопкой из уведомления
+    const startParam = window.Telegram?.WebApp?.initDataUnsafe?.start_param;
+    if (startParam?.startsWith(MOTORCYCLE_START_PREFIX)) {
+      navigate(`/motorcycle/${startParam.slice(MOTORCYCLE_START_PREFIX.length)}`, { replace: true });
+    }
+  }, [navigate]);
 
   return (
     <Routes>
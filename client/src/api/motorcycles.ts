@@ -1,21 +1,30 @@
 export interface MotorcycleData {
-  mileage?: number;
-  mileage_unit?: string;
-  volume?: number;
-  volume_unit?: string;
   frame_number?: string;
   arrival_date?: string;
 }
 
+// Меняются только переданные поля, null удаляет поле
+export type MotorcycleDataPatch = { [K in keyof MotorcycleData]?: string | null };
+
 export interface Motorcycle {
   id: string;
   title: string;
   price: number;
   currency: string;
+  year?: number;
+  brand?: string;
+  model?: string;
+  mileageKm?: number;
+  engineCc?: number;
   data?: MotorcycleData;
   status: 'available' | 'reserved' | 'sold' | 'draft';
   sourceUrl: string;
   photos?: MotorcyclePhoto[];
+  archivedAt?: string;
+  // Сколько пользователей добавили мотоцикл в избранное, приходит только админам
+  favoriteCount?: number;
+  // Растет при каждом изменении, передается в If-Match при сохранении
+  version: number;
   createdAt: string;
   updatedAt: string;
 }
@@ -25,14 +34,58 @@ export interface MotorcyclePhoto {
   motorcycleId: string;
   s3Url: string;
   order: number;
+  variants?: PhotoVariant[];
   createdAt: string;
 }
 
+export interface PhotoVariant {
+  name: 'thumb' | 'medium' | 'full';
+  format: 'jpeg' | 'webp';
+  url: string;
+  width: number;
+  height: number;
+}
+
+// Возвращает URL нужного размера и формата, иначе исходное фото
+export const photoUrl = (
+  photo: Pick<MotorcyclePhoto, 's3Url' | 'variants'>,
+  name: PhotoVariant['name'],
+  format: PhotoVariant['format'] = 'jpeg',
+): string | undefined => {
+  const variant = photo.variants?.find((v) => v.name === name && v.format === format);
+  if (variant) return variant.url;
+  return format === 'jpeg' ? photo.s3Url : undefined;
+};
+
 export interface FilterMotorcycle {
   status?: 'available' | 'reserved' | 'sold' | 'draft';
   title?: string;
   minPrice?: number;
   maxPrice?: number;
+  q?: string;
+  sort?: 'newest' | 'price' | 'mileage' | 'volume' | 'year';
+  order?: 'asc' | 'desc';
+  limit?: number;
+  cursor?: string;
+}
+
+export interface FacetCount {
+  value: string;
+  count: number;
+}
+
+export interface MotorcycleFacets {
+  brands: FacetCount[];
+  volumes: FacetCount[];
+  mileages: FacetCount[];
+  years: FacetCount[];
+}
+
+export interface MotorcycleList {
+  items: Motorcycle[];
+  // Фасеты приходят только на первой странице
+  facets?: MotorcycleFacets;
+  nextCursor?: string;
 }
 
 import { getTelegramInitData, getTelegramUser } from '../utils/telegram';
@@ -51,7 +104,12 @@ export interface PatchMotorcycle {
   title?: string;
   price?: number;
   currency?: string;
-  data?: MotorcycleData;
+  year?: number;
+  brand?: string;
+  model?: string;
+  mileageKm?: number;
+  engineCc?: number;
+  data?: MotorcycleDataPatch;
   status?: Motorcycle['status'];
 }
 
@@ -83,7 +141,7 @@ const createApiHeaders = (): HeadersInit => {
   return headers;
 };
 
-export const getMotorcycles = async (filters?: FilterMotorcycle): Promise<Motorcycle[]> => {
+export const getMotorcycles = async (filters?: FilterMotorcycle): Promise<MotorcycleList> => {
   try {
     // Строим URL с параметрами
     let url = `${getApiBaseUrl()}/motorcycles`;
@@ -101,6 +159,21 @@ export const getMotorcycles = async (filters?: FilterMotorcycle): Promise<Motorc
     if (filters?.maxPrice && filters.maxPrice > 0) {
       searchParams.append('maxPrice', filters.maxPrice.toString());
     }
+    if (filters?.q && filters.q.trim()) {
+      searchParams.append('q', filters.q.trim());
+    }
+    if (filters?.sort) {
+      searchParams.append('sort', filters.sort);
+    }
+    if (filters?.order) {
+      searchParams.append('order', filters.order);
+    }
+    if (filters?.limit) {
+      searchParams.append('limit', filters.limit.toString());
+    }
+    if (filters?.cursor) {
+      searchParams.append('cursor', filters.cursor);
+    }
     
     const queryString = searchParams.toString();
     if (queryString) {
@@ -120,18 +193,14 @@ export const getMotorcycles = async (filters?: FilterMotorcycle): Promise<Motorc
       throw new Error(`HTTP ${response.status}: ${response.statusText}`);
     }
     
-    const data = await response.json();
+    const data: MotorcycleList = await response.json();
     
-    // Обрабатываем ответ - Huma возвращает { body: [...] }
-    if (data && typeof data === 'object' && 'body' in data && Array.isArray(data.body)) {
-      return data.body;
-    }
-    
-    // Fallback: если данные приходят напрямую как массив
-    if (Array.isArray(data)) {
-      return data;
-    }
-    return [];
+    // Ответ содержит страницу, фасеты и курсор следующей страницы
+    return {
+      items: Array.isArray(data?.items) ? data.items : [],
+      facets: data?.facets,
+      nextCursor: data?.nextCursor,
+    };
     
   } catch (error) {
     console.error('Error fetching motorcycles:', error);
@@ -267,13 +336,19 @@ export const getCurrentUserOrCreate = async (): Promise<User> => {
   }
 };
 
-export const updateMotorcycle = async (id: string, updates: PatchMotorcycle): Promise<Motorcycle> => {
+export const updateMotorcycle = async (id: string, updates: PatchMotorcycle, version?: number): Promise<Motorcycle> => {
   try {
     const url = `${getApiBaseUrl()}/admin/motorcycle/${id}`;
     
+    // С версией сервер не применит изменения поверх чужих
+    const headers = new Headers(createApiHeaders());
+    if (version !== undefined) {
+      headers.set('If-Match', `"${version}"`);
+    }
+
     const response = await fetch(url, {
       method: 'PATCH',
-      headers: createApiHeaders(),
+      headers,
       body: JSON.stringify(updates),
     });
     
@@ -284,6 +359,9 @@ export const updateMotorcycle = async (id: string, updates: PatchMotorcycle): Pr
       if (response.status === 403) {
         throw new Error('Недостаточно прав для выполнения операции');
       }
+      if (response.status === 412) {
+        throw new Error('Мотоцикл уже изменил другой админ. Обновите страницу и повторите');
+      }
       throw new Error(`HTTP ${response.status}: ${response.statusText}`);
     }
     
@@ -320,6 +398,15 @@ export const updateMotorcycleStatus = async (id: string, status: Motorcycle['sta
       if (response.status === 403) {
         throw new Error('Недостаточно прав для выполнения операции');
       }
+      if (response.status === 409) {
+        throw new Error('Из текущего статуса нельзя перейти в выбранный');
+      }
+      if (response.status === 422) {
+        // Черновику не хватает полей для публикации, сервер перечисляет их в errors
+        const problem = await response.json().catch(() => null);
+        const messages: string[] = (problem?.errors ?? []).map((e: { message: string }) => e.message);
+        throw new Error(`Нельзя опубликовать: ${messages.join(', ') || 'заполнены не все поля'}`);
+      }
       throw new Error(`HTTP ${response.status}: ${response.statusText}`);
     }
     
@@ -337,4 +424,355 @@ export const updateMotorcycleStatus = async (id: string, status: Motorcycle['sta
     console.error('Error updating motorcycle status:', error);
     throw error;
   }
-};
\ No newline at end of file
+};
+export interface Favorite {
+  motorcycleId: string;
+  motorcycle?: Motorcycle;
+  createdAt: string;
+}
+
+export const getFavorites = async (): Promise<Favorite[]> => {
+  try {
+    const url = `${getApiBaseUrl()}/users/me/favorites`;
+
+    const response = await fetch(url, {
+      method: 'GET',
+      headers: createApiHeaders(),
+    });
+
+    if (!response.ok) {
+      if (response.status === 401) {
+        throw new Error('Требуется авторизация через Telegram');
+      }
+      throw new Error(`HTTP ${response.status}: ${response.statusText}`);
+    }
+
+    const data = await response.json();
+
+    // Обрабатываем ответ - Huma возвращает { body: [...] }
+    if (data && typeof data === 'object' && 'body' in data) {
+      return data.body;
+    }
+
+    // Fallback: если данные приходят напрямую
+    return data;
+
+  } catch (error) {
+    console.error('Error fetching favorites:', error);
+    throw error;
+  }
+};
+
+// setFavorite добавляет мотоцикл в избранное или убирает из него
+export const setFavorite = async (motorcycleId: string, favorite: boolean): Promise<void> => {
+  try {
+    const url = `${getApiBaseUrl()}/users/me/favorites/${motorcycleId}`;
+
+    const response = await fetch(url, {
+      method: favorite ? 'PUT' : 'DELETE',
+      headers: createApiHeaders(),
+    });
+
+    if (!response.ok) {
+      if (response.status === 401) {
+        throw new Error('Требуется авторизация через Telegram');
+      }
+      if (response.status === 404) {
+        throw new Error('Мотоцикл не найден');
+      }
+      throw new Error(`HTTP ${response.status}: ${response.statusText}`);
+    }
+  } catch (error) {
+    console.error('Error updating favorite:', error);
+    throw error;
+  }
+};
+
+export interface Reservation {
+  id: string;
+  motorcycleId: string;
+  userId: string;
+  status: 'pending' | 'approved' | 'declined' | 'expired';
+  comment?: string;
+  decidedBy?: string;
+  decidedAt?: string;
+  expiresAt?: string;
+  createdAt: string;
+}
+
+// requestReservation отправляет админам заявку на бронь мотоцикла
+export const requestReservation = async (motorcycleId: string, comment?: string): Promise<Reservation> => {
+  try {
+    const url = `${getApiBaseUrl()}/motorcycles/${motorcycleId}/reservations`;
+
+    const response = await fetch(url, {
+      method: 'POST',
+      headers: createApiHeaders(),
+      body: JSON.stringify({ comment }),
+    });
+
+    if (!response.ok) {
+      if (response.status === 401) {
+        throw new Error('Требуется авторизация через Telegram');
+      }
+      if (response.status === 404) {
+        throw new Error('Мотоцикл не найден');
+      }
+      if (response.status === 409) {
+        throw new Error('Мотоцикл уже забронирован или ваша заявка ждет решения');
+      }
+      throw new Error(`HTTP ${response.status}: ${response.statusText}`);
+    }
+
+    const data = await response.json();
+
+    // Обрабатываем ответ - Huma возвращает { body: {...} }
+    if (data && typeof data === 'object' && 'body' in data) {
+      return data.body;
+    }
+
+    return data;
+
+  } catch (error) {
+    console.error('Error requesting reservation:', error);
+    throw error;
+  }
+};
+
+export interface Inquiry {
+  id: string;
+  motorcycleId: string;
+  userId: string;
+  text: string;
+  answer?: string;
+  answeredBy?: string;
+  answeredAt?: string;
+  createdAt: string;
+}
+
+// askAboutMotorcycle отправляет вопрос менеджерам, ответ придет сообщением от бота
+export const askAboutMotorcycle = async (motorcycleId: string, text: string): Promise<Inquiry> => {
+  try {
+    const url = `${getApiBaseUrl()}/motorcycles/${motorcycleId}/inquiries`;
+
+    const response = await fetch(url, {
+      method: 'POST',
+      headers: createApiHeaders(),
+      body: JSON.stringify({ text }),
+    });
+
+    if (!response.ok) {
+      if (response.status === 401) {
+        throw new Error('Требуется авторизация через Telegram');
+      }
+      if (response.status === 404) {
+        throw new Error('Мотоцикл не найден');
+      }
+      throw new Error(`HTTP ${response.status}: ${response.statusText}`);
+    }
+
+    const data = await response.json();
+
+    // Обрабатываем ответ - Huma возвращает { body: {...} }
+    if (data && typeof data === 'object' && 'body' in data) {
+      return data.body;
+    }
+
+    return data;
+
+  } catch (error) {
+    console.error('Error sending inquiry:', error);
+    throw error;
+  }
+};
+
+export interface Subscription {
+  userId: string;
+  brand?: string;
+  maxPrice?: number;
+  createdAt: string;
+  updatedAt: string;
+}
+
+export interface SetSubscription {
+  brand?: string;
+  maxPrice?: number;
+}
+
+// getSubscription возвращает подписку на уведомления или null, если ее нет
+export const getSubscription = async (): Promise<Subscription | null> => {
+  try {
+    const url = `${getApiBaseUrl()}/users/me/subscription`;
+
+    const response = await fetch(url, {
+      method: 'GET',
+      headers: createApiHeaders(),
+    });
+
+    if (response.status === 404) {
+      return null;
+    }
+    if (!response.ok) {
+      if (response.status === 401) {
+        throw new Error('Требуется авторизация через Telegram');
+      }
+      throw new Error(`HTTP ${response.status}: ${response.statusText}`);
+    }
+
+    const data = await response.json();
+
+    // Обрабатываем ответ - Huma возвращает { body: {...} }
+    if (data && typeof data === 'object' && 'body' in data) {
+      return data.body;
+    }
+
+    return data;
+
+  } catch (error) {
+    console.error('Error fetching subscription:', error);
+    throw error;
+  }
+};
+
+// setSubscription включает уведомления о новых мотоциклах и снижении цен или отключает их (null)
+export const setSubscription = async (subscription: SetSubscription | null): Promise<void> => {
+  try {
+    const url = `${getApiBaseUrl()}/users/me/subscription`;
+
+    const response = await fetch(url, {
+      method: subscription ? 'PUT' : 'DELETE',
+      headers: createApiHeaders(),
+      body: subscription ? JSON.stringify(subscription) : undefined,
+    });
+
+    if (!response.ok) {
+      if (response.status === 401) {
+        throw new Error('Требуется авторизация через Telegram');
+      }
+      throw new Error(`HTTP ${response.status}: ${response.statusText}`);
+    }
+  } catch (error) {
+    console.error('Error updating subscription:', error);
+    throw error;
+  }
+};
+
+export interface SavedSearchFilter {
+  status?: Motorcycle['status'];
+  title?: string;
+  minPrice?: number;
+  maxPrice?: number;
+  brand?: string;
+  minVolume?: number;
+  maxVolume?: number;
+  maxMileage?: number;
+}
+
+export interface SavedSearch {
+  id: string;
+  userId: string;
+  name: string;
+  filter: SavedSearchFilter;
+  createdAt: string;
+  updatedAt: string;
+}
+
+export const getSavedSearches = async (): Promise<SavedSearch[]> => {
+  try {
+    const url = `${getApiBaseUrl()}/users/me/saved-searches`;
+
+    const response = await fetch(url, {
+      method: 'GET',
+      headers: createApiHeaders(),
+    });
+
+    if (!response.ok) {
+      if (response.status === 401) {
+        throw new Error('Требуется авторизация через Telegram');
+      }
+      throw new Error(`HTTP ${response.status}: ${response.statusText}`);
+    }
+
+    const data = await response.json();
+
+    // Обрабатываем ответ - Huma возвращает { body: [...] }
+    if (data && typeof data === 'object' && 'body' in data) {
+      return data.body;
+    }
+
+    return data;
+
+  } catch (error) {
+    console.error('Error fetching saved searches:', error);
+    throw error;
+  }
+};
+
+// saveSearch сохраняет новый поиск или, если передан id, заменяет существующий.
+// Бот присылает новые мотоциклы, подходящие под сохраненные поиски
+export const saveSearch = async (name: string, filter: SavedSearchFilter, id?: string): Promise<SavedSearch> => {
+  try {
+    const url = id
+      ? `${getApiBaseUrl()}/users/me/saved-searches/${id}`
+      : `${getApiBaseUrl()}/users/me/saved-searches`;
+
+    const response = await fetch(url, {
+      method: id ? 'PUT' : 'POST',
+      headers: createApiHeaders(),
+      body: JSON.stringify({ name, filter }),
+    });
+
+    if (!response.ok) {
+      if (response.status === 401) {
+        throw new Error('Требуется авторизация через Telegram');
+      }
+      if (response.status === 404) {
+        throw new Error('Сохраненный поиск не найден');
+      }
+      if (response.status === 409) {
+        throw new Error('Поиск с таким названием уже сохранен');
+      }
+      if (response.status === 400) {
+        throw new Error('Проверьте название и диапазоны фильтра');
+      }
+      throw new Error(`HTTP ${response.status}: ${response.statusText}`);
+    }
+
+    const data = await response.json();
+
+    // Обрабатываем ответ - Huma возвращает { body: {...} }
+    if (data && typeof data === 'object' && 'body' in data) {
+      return data.body;
+    }
+
+    return data;
+
+  } catch (error) {
+    console.error('Error saving search:', error);
+    throw error;
+  }
+};
+
+export const deleteSavedSearch = async (id: string): Promise<void> => {
+  try {
+    const url = `${getApiBaseUrl()}/users/me/saved-searches/${id}`;
+
+    const response = await fetch(url, {
+      method: 'DELETE',
+      headers: createApiHeaders(),
+    });
+
+    if (!response.ok) {
+      if (response.status === 401) {
+        throw new Error('Требуется авторизация через Telegram');
+      }
+      if (response.status === 404) {
+        throw new Error('Сохраненный поиск не найден');
+      }
+      throw new Error(`HTTP ${response.status}: ${response.statusText}`);
+    }
+  } catch (error) {
+    console.error('Error deleting saved search:', error);
+    throw error;
+  }
+};
@@ -1,4 +1,4 @@
-import { Motorcycle } from '../api/motorcycles';
+import { Motorcycle, photoUrl } from '../api/motorcycles';
 
 interface MotorcycleCardProps {
   motorcycle: Motorcycle;
@@ -6,7 +6,9 @@ interface MotorcycleCardProps {
 }
 
 export const MotorcycleCard = ({ motorcycle, onClick }: MotorcycleCardProps) => {
-  const mainPhoto = motorcycle.photos?.[0]?.s3Url;
+  const firstPhoto = motorcycle.photos?.[0];
+  const mainPhoto = firstPhoto && photoUrl(firstPhoto, 'medium');
+  const mainPhotoWebp = firstPhoto && photoUrl(firstPhoto, 'medium', 'webp');
   const statusLabels = {
     available: 'В продаже',
     reserved: 'Бронь',
@@ -36,14 +38,17 @@ export const MotorcycleCard = ({ motorcycle, onClick }: MotorcycleCardProps) =>
     >
       <div className="aspect-[4/3] relative bg-gray-100">
         {mainPhoto ? (
-          <img
-            src={mainPhoto}
-            alt={motorcycle.title}
-            className="w-full h-full object-cover"
-            onError={(e) => {
-              (e.target as HTMLImageElement).src = 'data:image/svg+xml,%3Csvg xmlns="http://www.w3.org/2000/svg" width="400" height="300"%3E%3Crect fill="%23e5e7eb" width="400" height="300"/%3E%3Ctext x="50%25" y="50%25" text-anchor="middle" dy=".3em" fill="%239ca3af" font-family="sans-serif" font-size="16"%3EНет фото%3C/text%3E%3C/svg%3E';
-            }}
-          />
+          <picture className="block w-full h-full">
+            {mainPhotoWebp && <source srcSet={mainPhotoWebp} type="image/webp" />}
+            <img
+              src={mainPhoto}
+              alt={motorcycle.title}
+              className="w-full h-full object-cover"
+              onError={(e) => {
+                (e.target as HTMLImageElement).src = 'data:image/svg+xml,%3Csvg xmlns="http://www.w3.org/2000/svg" width="400" height="300"%3E%3Crect fill="%23e5e7eb" width="400" height="300"/%3E%3Ctext x="50%25" y="50%25" text-anchor="middle" dy=".3em" fill="%239ca3af" font-family="sans-serif" font-size="16"%3EНет фото%3C/text%3E%3C/svg%3E';
+              }}
+            />
+          </picture>
         ) : (
           <div className="w-full h-full flex items-center justify-center text-gray-400 bg-gray-100">
             Нет фото
@@ -1,9 +1,11 @@
 import { useState } from 'react';
+import { PhotoVariant, photoUrl } from '../api/motorcycles';
 
 interface Photo {
   id: string;
   s3Url: string;
   order: number;
+  variants?: PhotoVariant[];
 }
 
 interface PhotoCarouselProps {
@@ -50,12 +52,17 @@ export const PhotoCarousel: React.FC<PhotoCarouselProps> = ({ photos, title }) =
     <div className="relative w-full">
       {/* Основное изображение */}
       <div className="relative aspect-video bg-gray-800 rounded-lg overflow-hidden group">
-        <img
-          src={sortedPhotos[currentIndex].s3Url}
-          alt={`${title} - фото ${currentIndex + 1}`}
-          className="w-full h-full object-cover transition-opacity duration-300"
-          loading="lazy"
-        />
+        <picture className="block w-full h-full">
+          {photoUrl(sortedPhotos[currentIndex], 'medium', 'webp') && (
+            <source srcSet={photoUrl(sortedPhotos[currentIndex], 'medium', 'webp')} type="image/webp" />
+          )}
+          <img
+            src={photoUrl(sortedPhotos[currentIndex], 'medium')}
+            alt={`${title} - фото ${currentIndex + 1}`}
+            className="w-full h-full object-cover transition-opacity duration-300"
+            loading="lazy"
+          />
+        </picture>
         
         {/* Градиент для лучшей видимости кнопок */}
         <div className="absolute inset-0 bg-gradient-to-r from-black/20 via-transparent to-black/20 opacity-0 group-hover:opacity-100 transition-opacity duration-300" />
@@ -108,12 +115,17 @@ export const PhotoCarousel: React.FC<PhotoCarouselProps> = ({ photos, title }) =
                   : 'border-gray-600 hover:border-gray-400'
               }`}
             >
-              <img
-                src={photo.s3Url}
-                alt={`${title} - миниатюра ${index + 1}`}
-                className="w-full h-full object-cover"
-                loading="lazy"
-              />
+              <picture className="block w-full h-full">
+                {photoUrl(photo, 'thumb', 'webp') && (
+                  <source srcSet={photoUrl(photo, 'thumb', 'webp')} type="image/webp" />
+                )}
+                <img
+                  src={photoUrl(photo, 'thumb')}
+                  alt={`${title} - миниатюра ${index + 1}`}
+                  className="w-full h-full object-cover"
+                  loading="lazy"
+                />
+              </picture>
             </button>
           ))}
         </div>
@@ -80,11 +80,11 @@ export const MotorcycleDetail: React.FC = () => {
       } else if (field === 'price' && editValues.price !== undefined) {
         updates.price = editValues.price;
       } else if (field === 'arrival_date' && editValues.data?.arrival_date !== undefined) {
-        updates.data = editValues.data;
+        updates.data = { arrival_date: editValues.data.arrival_date };
       }
 
       // Отправляем запрос на сервер
-      const updatedMotorcycle = await updateMotorcycle(motorcycle.id, updates);
+      const updatedMotorcycle = await updateMotorcycle(motorcycle.id, updates, motorcycle.version);
       
       // Обновляем состояние
       setMotorcycle(updatedMotorcycle);
@@ -319,27 +319,33 @@ export const MotorcycleDetail: React.FC = () => {
           </div>
 
           {/* Технические характеристики */}
-          {motorcycle.data && (
+          {(motorcycle.year || motorcycle.mileageKm || motorcycle.engineCc || motorcycle.data?.frame_number) && (
             <div className="mb-4">
               <label className="block text-sm font-medium text-gray-400 mb-3">Технические характеристики</label>
               <div className="space-y-2">
-                {motorcycle.data.mileage && (
+                {motorcycle.year && (
+                  <div className="flex justify-between items-center">
+                    <span className="text-gray-400">Год выпуска:</span>
+                    <span className="text-white font-medium">{motorcycle.year}</span>
+                  </div>
+                )}
+                {motorcycle.mileageKm && (
                   <div className="flex justify-between items-center">
                     <span className="text-gray-400">Пробег:</span>
                     <span className="text-white font-medium">
-                      {motorcycle.data.mileage.toLocaleString()} {motorcycle.data.mileage_unit || 'км'}
+                      {motorcycle.mileageKm.toLocaleString()} км
                     </span>
                   </div>
                 )}
-                {motorcycle.data.volume && (
+                {motorcycle.engineCc && (
                   <div className="flex justify-between items-center">
                     <span className="text-gray-400">Объем двигателя:</span>
                     <span className="text-white font-medium">
-                      {motorcycle.data.volume} {motorcycle.data.volume_unit || 'сс'}
+                      {motorcycle.engineCc} сс
                     </span>
                   </div>
                 )}
-                {motorcycle.data.frame_number && (
+                {motorcycle.data?.frame_number && (
                   <div className="flex justify-between items-center">
                     <span className="text-gray-400">Номер рамы:</span>
                     <span className="text-white font-medium font-mono">{motorcycle.data.frame_number}</span>
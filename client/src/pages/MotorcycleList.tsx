@@ -12,6 +12,8 @@ export const MotorcycleList: React.FC = () => {
   const [loading, setLoading] = useState(true);
   const [error, setError] = useState<string | null>(null);
   const [filters, setFilters] = useState<FilterMotorcycle>({});
+  const [nextCursor, setNextCursor] = useState<string | undefined>();
+  const [loadingMore, setLoadingMore] = useState(false);
 
   // Загрузка мотоциклов
   useEffect(() => {
@@ -30,7 +32,8 @@ export const MotorcycleList: React.FC = () => {
         const data = await getMotorcycles(filters);
         
         if (!isCancelled) {
-          setMotorcycles(data || []);
+          setMotorcycles(data.items);
+          setNextCursor(data.nextCursor);
         }
       } catch (err) {
         if (!isCancelled) {
@@ -61,6 +64,23 @@ export const MotorcycleList: React.FC = () => {
     };
   }, [filters, user, userLoading, isRegistering]);
 
+  // Подгрузка следующей страницы по курсору
+  const loadMore = async () => {
+    if (!nextCursor || loadingMore) {
+      return;
+    }
+    try {
+      setLoadingMore(true);
+      const data = await getMotorcycles({ ...filters, cursor: nextCursor });
+      setMotorcycles((prev) => [...prev, ...data.items]);
+      setNextCursor(data.nextCursor);
+    } catch (err) {
+      console.error('Failed to load more motorcycles:', err);
+    } finally {
+      setLoadingMore(false);
+    }
+  };
+
   const handleFilterChange = (newFilters: FilterMotorcycle) => {
     setFilters(newFilters);
   };
@@ -69,22 +89,6 @@ export const MotorcycleList: React.FC = () => {
     navigate(`/motorcycle/${motorcycle.id}`);
   };
 
-  // Функция сортировки мотоциклов по статусу
-  const sortMotorcyclesByStatus = (motorcycles: Motorcycle[]) => {
-    const statusPriority = {
-      'available': 1,  // В продаже - наивысший приоритет
-      'reserved': 2,   // Бронь - средний приоритет  
-      'sold': 3,       // Продано - низкий приоритет
-      'draft': 4       // Черновик - самый низкий приоритет
-    };
-
-    return [...motorcycles].sort((a, b) => {
-      const priorityA = statusPriority[a.status] || 999;
-      const priorityB = statusPriority[b.status] || 999;
-      return priorityA - priorityB;
-    });
-  };
-
   return (
     <div className="min-h-screen bg-[#0E0E0E] text-white">
       <div className="max-w-6xl mx-auto px-4 py-6">
@@ -126,10 +130,10 @@ export const MotorcycleList: React.FC = () => {
         ) : (
           <>
             <div className="mb-6 text-sm text-gray-400">
-              Найдено мотоциклов: <span className="text-white font-medium">{motorcycles.length}</span>
+              Показано мотоциклов: <span className="text-white font-medium">{motorcycles.length}</span>
             </div>
             <div className="grid grid-cols-1 sm:grid-cols-2 lg:grid-cols-3 gap-6">
-              {sortMotorcyclesByStatus(motorcycles).map((motorcycle) => (
+              {motorcycles.map((motorcycle) => (
                 <MotorcycleCard 
                   key={motorcycle.id} 
                   motorcycle={motorcycle}
@@ -137,6 +141,17 @@ export const MotorcycleList: React.FC = () => {
                 />
               ))}
             </div>
+            {nextCursor && (
+              <div className="text-center mt-6">
+                <button
+                  onClick={loadMore}
+                  disabled={loadingMore}
+                  className="px-4 py-2 bg-gray-800 text-white rounded hover:bg-gray-700 transition-colors disabled:opacity-50"
+                >
+                  {loadingMore ? 'Загрузка...' : 'Показать еще'}
+                </button>
+              </div>
+            )}
           </>
         )}
       </div>
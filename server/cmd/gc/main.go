@@ -0,0 +1,60 @@
+// gc удаляет из хранилища изображений файлы, на которые не ссылается БД:
+// фото удаленных мотоциклов, остатки неудачных импортов и старые аватары.
+//
+// С флагом -dry-run только выводит список лишних файлов.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shampsdev/go-telegram-template/pkg/config"
+	"github.com/shampsdev/go-telegram-template/pkg/usecase"
+	"github.com/shampsdev/go-telegram-template/pkg/utils/slogx"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report orphaned objects without deleting them")
+	minAge := flag.Duration("min-age", 0, "skip objects younger than this, defaults to GC_MIN_AGE")
+	flag.Parse()
+
+	cfg := config.Load(".env")
+	log := cfg.Logger()
+	if *minAge > 0 {
+		cfg.GC.MinAge = *minAge
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+	ctx = slogx.NewCtx(ctx, log)
+
+	pool, err := pgxpool.NewWithConfig(ctx, cfg.PGXConfig())
+	if err != nil {
+		slogx.Fatal(log, "failed to connect to database", slogx.Err(err))
+	}
+	defer pool.Close()
+
+	gc, err := usecase.SetupGC(ctx, cfg, pool)
+	if err != nil {
+		slogx.Fatal(log, "failed to setup gc", slogx.Err(err))
+	}
+
+	report, err := gc.Run(ctx, *dryRun)
+	if err != nil {
+		slogx.Fatal(log, "gc failed", slogx.Err(err))
+	}
+
+	for _, obj := range report.Orphaned {
+		fmt.Printf("%s\t%d\t%s\n", obj.Key, obj.Size, obj.ModifiedAt.Format("2006-01-02 15:04"))
+	}
+	action := "deleted"
+	if report.DryRun {
+		action = "would delete"
+	}
+	fmt.Printf("scanned %d, referenced %d, too young %d, %s %d objects (%d bytes)\n",
+		report.Scanned, report.Referenced, report.SkippedYoung, action, len(report.Orphaned), report.OrphanedSize)
+}
@@ -36,7 +36,19 @@ func main() {
 	}
 	defer pool.Close()
 
-	s := rest.NewServer(ctx, cfg, usecase.Setup(ctx, cfg, pool))
+	cases, err := usecase.Setup(ctx, cfg, pool)
+	if err != nil {
+		slogx.Fatal(log, "failed to setup usecases", slogx.Err(err))
+	}
+
+	// Фоновые задачи, которые должны идти в одном процессе, запускает только сервер
+	go cases.GC.RunScheduler(ctx)
+	go cases.Reservation.RunExpiry(ctx)
+	go cases.Subscription.RunMatcher(ctx)
+	go cases.Import.RunWorkers(ctx)
+	go cases.Brand.RunRefresher(ctx)
+
+	s := rest.NewServer(ctx, cfg, cases)
 	if err := s.Run(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		slogx.Fatal(log, "failed to run app", slogx.Err(err))
 	}
@@ -15,7 +15,6 @@ require (
 
 require (
 	github.com/KyleBanks/depth v1.2.1 // indirect
-	github.com/PuerkitoBio/goquery v1.11.0 // indirect
 	github.com/PuerkitoBio/purell v1.1.1 // indirect
 	github.com/PuerkitoBio/urlesc v0.0.0-20170810143723-de5bf2ad4578 // indirect
 	github.com/andybalholm/cascadia v1.3.3 // indirect
@@ -41,11 +40,14 @@ require (
 
 require (
 	github.com/Masterminds/squirrel v1.5.4
+	github.com/PuerkitoBio/goquery v1.11.0
+	github.com/disintegration/imaging v1.6.2
 	github.com/go-chi/chi/v5 v5.2.3
 	github.com/go-chi/cors v1.2.2
 	github.com/go-telegram/bot v1.15.0
 	github.com/swaggo/swag v1.16.6
 	github.com/telegram-mini-apps/init-data-golang v1.5.0
 	github.com/tj/go-spin v1.1.0
+	golang.org/x/image v0.25.0
 	golang.org/x/net v0.47.0
 )
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_motorcycle_mileage;
+DROP INDEX IF EXISTS idx_motorcycle_volume;
+DROP INDEX IF EXISTS idx_motorcycle_price;
+DROP INDEX IF EXISTS idx_motorcycle_search_vector;
+
+ALTER TABLE "motorcycle" DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,18 @@
+-- Поисковый вектор по названию и структурированным данным мотоцикла.
+-- Конфигурация simple: названия моделей (CB400SF, XJR1300) не нужно стеммить
+ALTER TABLE "motorcycle" ADD COLUMN IF NOT EXISTS search_vector TSVECTOR
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('simple',
+            coalesce(data->>'frame_number', '') || ' ' ||
+            coalesce(data->>'volume', '') || ' ' ||
+            coalesce(data->>'mileage', '')
+        ), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_motorcycle_search_vector ON "motorcycle" USING GIN (search_vector);
+
+-- Индексы для фильтров по цене и данным
+CREATE INDEX IF NOT EXISTS idx_motorcycle_price ON "motorcycle"(price);
+CREATE INDEX IF NOT EXISTS idx_motorcycle_volume ON "motorcycle"(((data->>'volume')::int));
+CREATE INDEX IF NOT EXISTS idx_motorcycle_mileage ON "motorcycle"(((data->>'mileage')::int));
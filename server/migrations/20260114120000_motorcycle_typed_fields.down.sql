@@ -0,0 +1,36 @@
+DROP INDEX IF EXISTS idx_motorcycle_engine_cc;
+DROP INDEX IF EXISTS idx_motorcycle_mileage_km;
+DROP INDEX IF EXISTS idx_motorcycle_brand;
+DROP INDEX IF EXISTS idx_motorcycle_year;
+DROP INDEX IF EXISTS idx_motorcycle_search_vector;
+ALTER TABLE "motorcycle" DROP COLUMN IF EXISTS search_vector;
+
+-- Возвращаем характеристики в data и год в название
+UPDATE "motorcycle" SET data = COALESCE(data, '{}'::jsonb)
+    || CASE WHEN mileage_km IS NOT NULL
+        THEN jsonb_build_object('mileage', mileage_km, 'mileage_unit', 'км') ELSE '{}'::jsonb END
+    || CASE WHEN engine_cc IS NOT NULL
+        THEN jsonb_build_object('volume', engine_cc, 'volume_unit', 'сс') ELSE '{}'::jsonb END;
+
+UPDATE "motorcycle" SET title = title || ' ' || year WHERE year IS NOT NULL;
+
+ALTER TABLE "motorcycle"
+    DROP COLUMN IF EXISTS engine_cc,
+    DROP COLUMN IF EXISTS mileage_km,
+    DROP COLUMN IF EXISTS model,
+    DROP COLUMN IF EXISTS brand,
+    DROP COLUMN IF EXISTS year;
+
+ALTER TABLE "motorcycle" ADD COLUMN search_vector TSVECTOR
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('simple',
+            coalesce(data->>'frame_number', '') || ' ' ||
+            coalesce(data->>'volume', '') || ' ' ||
+            coalesce(data->>'mileage', '')
+        ), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_motorcycle_search_vector ON "motorcycle" USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_motorcycle_volume ON "motorcycle"(((data->>'volume')::int));
+CREATE INDEX IF NOT EXISTS idx_motorcycle_mileage ON "motorcycle"(((data->>'mileage')::int));
@@ -0,0 +1,52 @@
+-- Технические характеристики переезжают из data в отдельные колонки
+ALTER TABLE "motorcycle"
+    ADD COLUMN IF NOT EXISTS year INTEGER,
+    ADD COLUMN IF NOT EXISTS brand VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS model VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS mileage_km INTEGER,
+    ADD COLUMN IF NOT EXISTS engine_cc INTEGER;
+
+-- Пробег приводим к километрам, объем переносим как есть
+UPDATE "motorcycle" SET
+    mileage_km = CASE
+        WHEN LOWER(COALESCE(data->>'mileage_unit', '')) IN ('mi', 'mile', 'miles', 'миль', 'мили')
+            THEN ROUND((data->>'mileage')::numeric * 1.609344)::integer
+        ELSE (data->>'mileage')::integer
+    END,
+    engine_cc = (data->>'volume')::integer
+WHERE data IS NOT NULL;
+
+-- Год раньше дописывался в конец названия: "Honda CB400SF 2005"
+UPDATE "motorcycle" SET
+    year = substring(title from '\s((?:19|20)\d{2})$')::integer,
+    title = TRIM(regexp_replace(title, '\s(19|20)\d{2}$', ''))
+WHERE title ~ '\s(19|20)\d{2}$';
+
+-- Марку и модель заполняет миграция справочника марок по самому справочнику
+
+UPDATE "motorcycle"
+SET data = data - 'mileage' - 'mileage_unit' - 'volume' - 'volume_unit'
+WHERE data IS NOT NULL;
+
+-- Поисковый вектор и индексы пересоздаем поверх новых колонок
+DROP INDEX IF EXISTS idx_motorcycle_mileage;
+DROP INDEX IF EXISTS idx_motorcycle_volume;
+DROP INDEX IF EXISTS idx_motorcycle_search_vector;
+ALTER TABLE "motorcycle" DROP COLUMN IF EXISTS search_vector;
+
+ALTER TABLE "motorcycle" ADD COLUMN search_vector TSVECTOR
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(brand, '') || ' ' || coalesce(model, '')), 'A') ||
+        setweight(to_tsvector('simple',
+            coalesce(year::text, '') || ' ' ||
+            coalesce(engine_cc::text, '') || ' ' ||
+            coalesce(data->>'frame_number', '')
+        ), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_motorcycle_search_vector ON "motorcycle" USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_motorcycle_year ON "motorcycle"(year);
+CREATE INDEX IF NOT EXISTS idx_motorcycle_brand ON "motorcycle"(LOWER(brand));
+CREATE INDEX IF NOT EXISTS idx_motorcycle_mileage_km ON "motorcycle"(mileage_km);
+CREATE INDEX IF NOT EXISTS idx_motorcycle_engine_cc ON "motorcycle"(engine_cc);
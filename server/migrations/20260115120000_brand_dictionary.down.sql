@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_motorcycle_model_id;
+DROP INDEX IF EXISTS idx_motorcycle_brand_id;
+
+ALTER TABLE "motorcycle"
+    DROP COLUMN IF EXISTS model_id,
+    DROP COLUMN IF EXISTS brand_id;
+
+DROP TABLE IF EXISTS "motorcycle_model";
+DROP TABLE IF EXISTS "motorcycle_brand";
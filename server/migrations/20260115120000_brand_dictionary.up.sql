@@ -0,0 +1,63 @@
+-- Справочник марок и моделей с синонимами для нормализации названий
+CREATE TABLE IF NOT EXISTS "motorcycle_brand" (
+    id VARCHAR(255) PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    aliases TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_motorcycle_brand_name ON "motorcycle_brand"(LOWER(name));
+
+CREATE TABLE IF NOT EXISTS "motorcycle_model" (
+    id VARCHAR(255) PRIMARY KEY DEFAULT gen_random_uuid(),
+    brand_id VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    aliases TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (brand_id) REFERENCES "motorcycle_brand"(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_motorcycle_model_name ON "motorcycle_model"(brand_id, LOWER(name));
+
+-- Мотоцикл ссылается на марку и модель из справочника, если они распознаны
+ALTER TABLE "motorcycle"
+    ADD COLUMN IF NOT EXISTS brand_id VARCHAR(255) REFERENCES "motorcycle_brand"(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS model_id VARCHAR(255) REFERENCES "motorcycle_model"(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_motorcycle_brand_id ON "motorcycle"(brand_id);
+CREATE INDEX IF NOT EXISTS idx_motorcycle_model_id ON "motorcycle"(model_id);
+
+-- Начальный справочник - марки, которые раньше были зашиты в парсер
+INSERT INTO "motorcycle_brand" (name, aliases) VALUES
+    ('Suzuki', '{}'),
+    ('Yamaha', '{}'),
+    ('Honda', '{}'),
+    ('Kawasaki', '{}'),
+    ('Ducati', '{}'),
+    ('BMW', '{}'),
+    ('Triumph', '{}'),
+    ('Harley-Davidson', '{"Harley Davidson", "Harley"}')
+ON CONFLICT DO NOTHING;
+
+-- Марка старых мотоциклов - марка справочника, с названия или синонима которой
+-- начинается название мотоцикла, модель - остаток названия. Из нескольких
+-- подходящих синонимов берется самый длинный: "Harley Davidson", а не "Harley"
+UPDATE "motorcycle" m SET
+    brand = matched.name,
+    model = TRIM(substring(m.title from length(matched.alias) + 1))
+FROM (
+    SELECT DISTINCT ON (t.id) t.id, b.name, a.alias
+    FROM "motorcycle" t
+    CROSS JOIN "motorcycle_brand" b
+    CROSS JOIN LATERAL unnest(array_append(b.aliases, b.name)) AS a(alias)
+    WHERE t.brand = ''
+        AND LOWER(left(t.title, length(a.alias) + 1)) = LOWER(a.alias) || ' '
+    ORDER BY t.id, length(a.alias) DESC
+) matched
+WHERE m.id = matched.id;
+
+UPDATE "motorcycle" m SET brand_id = b.id
+FROM "motorcycle_brand" b
+WHERE LOWER(m.brand) = LOWER(b.name);
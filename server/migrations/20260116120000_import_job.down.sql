@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_import_job_created_at;
+DROP INDEX IF EXISTS idx_import_job_pending;
+DROP TABLE IF EXISTS "import_job";
@@ -0,0 +1,25 @@
+-- Очередь задач импорта мотоциклов по ссылке
+CREATE TABLE IF NOT EXISTS "import_job" (
+    id VARCHAR(255) PRIMARY KEY DEFAULT gen_random_uuid(),
+    source_url VARCHAR(500) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'queued' CHECK (status IN ('queued', 'running', 'succeeded', 'failed')),
+    stage VARCHAR(20) NOT NULL DEFAULT '',
+    photos_total INTEGER NOT NULL DEFAULT 0,
+    photos_done INTEGER NOT NULL DEFAULT 0,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 3,
+    last_error TEXT NOT NULL DEFAULT '',
+    motorcycle_id VARCHAR(255) REFERENCES "motorcycle"(id) ON DELETE SET NULL,
+    created_by VARCHAR(255) REFERENCES "user"(id) ON DELETE SET NULL,
+    -- Задача берется в работу не раньше run_at; locked_until - аренда воркера,
+    -- после ее истечения зависшую задачу подберет другой воркер
+    run_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    locked_until TIMESTAMP,
+    started_at TIMESTAMP,
+    finished_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_import_job_pending ON "import_job"(run_at) WHERE status IN ('queued', 'running');
+CREATE INDEX IF NOT EXISTS idx_import_job_created_at ON "import_job"(created_at);
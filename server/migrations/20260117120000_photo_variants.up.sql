@@ -0,0 +1,2 @@
+-- Варианты размеров фотографии: [{"name": "thumb", "format": "webp", "url": ..., "width": ..., "height": ...}]
+ALTER TABLE "motorcycle_photo" ADD COLUMN IF NOT EXISTS variants JSONB NOT NULL DEFAULT '[]';
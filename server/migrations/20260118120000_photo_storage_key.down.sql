@@ -0,0 +1 @@
+ALTER TABLE "motorcycle_photo" DROP COLUMN IF EXISTS storage_key;
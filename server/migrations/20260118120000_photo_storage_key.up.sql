@@ -0,0 +1,8 @@
+-- Префикс файлов фотографии в хранилище, чтобы удалять их вместе с фотографией
+ALTER TABLE "motorcycle_photo" ADD COLUMN IF NOT EXISTS storage_key VARCHAR(255);
+
+-- Восстанавливаем ключи уже загруженных фотографий из URL:
+-- motorcycles/<id>/<n>/ для фото с вариантами, motorcycles/<id>/<n>.<ext> для оригиналов
+UPDATE "motorcycle_photo"
+SET storage_key = substring(s3_url from '(motorcycles/[^/]+/[^/]+/?)')
+WHERE storage_key IS NULL;
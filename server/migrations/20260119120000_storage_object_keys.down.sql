@@ -0,0 +1,10 @@
+-- Ссылки из ключей не восстановить: они зависят от настроек хранилища.
+-- Возвращаем только имена колонок, ключи останутся в s3_url, url и avatar
+UPDATE "motorcycle_photo"
+SET variants = (
+    SELECT jsonb_agg((v - 'key') || jsonb_build_object('url', v->>'key') ORDER BY ord)
+    FROM jsonb_array_elements(variants) WITH ORDINALITY AS t(v, ord)
+)
+WHERE jsonb_array_length(variants) > 0;
+
+ALTER TABLE "motorcycle_photo" RENAME COLUMN object_key TO s3_url;
@@ -0,0 +1,22 @@
+-- В БД храним только ключи объектов, ссылки строятся при чтении по STORAGE_URL_STRATEGY.
+-- Ключ - часть URL начиная с motorcycles/ или user/; ссылки, где ее нет, остаются как есть
+ALTER TABLE "motorcycle_photo" RENAME COLUMN s3_url TO object_key;
+
+UPDATE "motorcycle_photo"
+SET object_key = substring(object_key from '((?:motorcycles|user)/.*)$')
+WHERE object_key ~ '^https?://' AND object_key ~ '(motorcycles|user)/';
+
+-- Варианты: [{"name": ..., "format": ..., "key": ..., "width": ..., "height": ...}]
+UPDATE "motorcycle_photo"
+SET variants = (
+    SELECT jsonb_agg(
+        (v - 'url') || jsonb_build_object('key', COALESCE(substring(v->>'url' from '((?:motorcycles|user)/.*)$'), v->>'url'))
+        ORDER BY ord
+    )
+    FROM jsonb_array_elements(variants) WITH ORDINALITY AS t(v, ord)
+)
+WHERE jsonb_array_length(variants) > 0;
+
+UPDATE "user"
+SET avatar = substring(avatar from '((?:motorcycles|user)/.*)$')
+WHERE avatar ~ '^https?://' AND avatar ~ '(motorcycles|user)/';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_motorcycle_not_archived;
+ALTER TABLE "motorcycle" DROP COLUMN IF EXISTS archived_at;
@@ -0,0 +1,5 @@
+-- Архив: мотоцикл скрыт из каталога, но его можно восстановить
+ALTER TABLE "motorcycle" ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP;
+
+-- Каталог почти всегда смотрит только на неархивные записи
+CREATE INDEX IF NOT EXISTS idx_motorcycle_not_archived ON "motorcycle"(created_at) WHERE archived_at IS NULL;
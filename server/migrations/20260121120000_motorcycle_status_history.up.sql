@@ -0,0 +1,15 @@
+-- История статусов мотоцикла: каждый переход с временем и автором
+CREATE TABLE IF NOT EXISTS "motorcycle_status_history" (
+    id VARCHAR(255) PRIMARY KEY DEFAULT gen_random_uuid(),
+    motorcycle_id VARCHAR(255) NOT NULL REFERENCES "motorcycle"(id) ON DELETE CASCADE,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    changed_by VARCHAR(255) REFERENCES "user"(id) ON DELETE SET NULL,
+    changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_motorcycle_status_history_motorcycle_id ON "motorcycle_status_history"(motorcycle_id, changed_at);
+
+-- У существующих мотоциклов история начинается с текущего статуса
+INSERT INTO "motorcycle_status_history" (motorcycle_id, to_status, changed_at)
+SELECT id, status, COALESCE(created_at, CURRENT_TIMESTAMP) FROM "motorcycle";
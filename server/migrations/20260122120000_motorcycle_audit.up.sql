@@ -0,0 +1,12 @@
+-- Журнал изменений мотоциклов админами: кто, что и какие поля поменял.
+-- Без внешнего ключа на motorcycle, чтобы записи оставались после удаления
+CREATE TABLE IF NOT EXISTS "motorcycle_audit" (
+    id VARCHAR(255) PRIMARY KEY DEFAULT gen_random_uuid(),
+    motorcycle_id VARCHAR(255) NOT NULL,
+    actor_id VARCHAR(255) REFERENCES "user"(id) ON DELETE SET NULL,
+    action VARCHAR(50) NOT NULL,
+    changes JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_motorcycle_audit_motorcycle_id ON "motorcycle_audit"(motorcycle_id, created_at);
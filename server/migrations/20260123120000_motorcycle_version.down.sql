@@ -0,0 +1 @@
+ALTER TABLE "motorcycle" DROP COLUMN IF EXISTS version;
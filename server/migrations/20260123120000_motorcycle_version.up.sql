@@ -0,0 +1,2 @@
+-- Версия для оптимистичной блокировки: растет при каждом изменении мотоцикла и его галереи
+ALTER TABLE "motorcycle" ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,10 @@
+-- Избранное покупателей: мотоциклы, за которыми пользователь следит
+CREATE TABLE IF NOT EXISTS "favorite" (
+    user_id VARCHAR(255) NOT NULL REFERENCES "user"(id) ON DELETE CASCADE,
+    motorcycle_id VARCHAR(255) NOT NULL REFERENCES "motorcycle"(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, motorcycle_id)
+);
+
+-- Для подсчета, сколько пользователей добавили мотоцикл
+CREATE INDEX IF NOT EXISTS idx_favorite_motorcycle_id ON "favorite"(motorcycle_id);
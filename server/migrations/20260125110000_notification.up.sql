@@ -0,0 +1,15 @@
+-- Очередь сообщений в Telegram: их пишут API и бот, а доставляет бот
+CREATE TABLE IF NOT EXISTS "notification" (
+    id VARCHAR(255) PRIMARY KEY DEFAULT gen_random_uuid(),
+    chat_id BIGINT NOT NULL,
+    text TEXT NOT NULL,
+    buttons JSONB NOT NULL DEFAULT '[]',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    send_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    sent_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_pending ON "notification"(send_at) WHERE status = 'pending';
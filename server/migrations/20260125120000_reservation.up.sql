@@ -0,0 +1,18 @@
+-- Заявки покупателей на бронь мотоцикла. Подтвержденная заявка переводит
+-- мотоцикл в reserved до expires_at
+CREATE TABLE IF NOT EXISTS "reservation" (
+    id VARCHAR(255) PRIMARY KEY DEFAULT gen_random_uuid(),
+    motorcycle_id VARCHAR(255) NOT NULL REFERENCES "motorcycle"(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL REFERENCES "user"(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    comment TEXT NOT NULL DEFAULT '',
+    decided_by VARCHAR(255) REFERENCES "user"(id) ON DELETE SET NULL,
+    decided_at TIMESTAMP,
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Одна ожидающая заявка покупателя на мотоцикл и одна действующая бронь на мотоцикл
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reservation_pending ON "reservation"(motorcycle_id, user_id) WHERE status = 'pending';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reservation_approved ON "reservation"(motorcycle_id) WHERE status = 'approved';
+CREATE INDEX IF NOT EXISTS idx_reservation_expires_at ON "reservation"(expires_at) WHERE status = 'approved';
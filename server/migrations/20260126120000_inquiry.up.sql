@@ -0,0 +1,16 @@
+-- Вопросы покупателей о мотоциклах. Бот пересылает вопрос админам,
+-- а ответ админа отправляет покупателю
+CREATE TABLE IF NOT EXISTS "inquiry" (
+    id VARCHAR(255) PRIMARY KEY DEFAULT gen_random_uuid(),
+    motorcycle_id VARCHAR(255) NOT NULL REFERENCES "motorcycle"(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL REFERENCES "user"(id) ON DELETE CASCADE,
+    text TEXT NOT NULL,
+    -- Последний ответ админа
+    answer TEXT,
+    answered_by VARCHAR(255) REFERENCES "user"(id) ON DELETE SET NULL,
+    answered_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_inquiry_motorcycle_id ON "inquiry"(motorcycle_id);
+CREATE INDEX IF NOT EXISTS idx_inquiry_user_id ON "inquiry"(user_id);
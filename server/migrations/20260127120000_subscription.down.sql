@@ -0,0 +1,3 @@
+ALTER TABLE "notification" DROP COLUMN IF EXISTS photo_url;
+DROP TABLE IF EXISTS "motorcycle_event";
+DROP TABLE IF EXISTS "subscription";
@@ -0,0 +1,27 @@
+-- Подписка покупателя на новые мотоциклы и снижения цен. Пустой фильтр - все мотоциклы
+CREATE TABLE IF NOT EXISTS "subscription" (
+    user_id VARCHAR(255) PRIMARY KEY REFERENCES "user"(id) ON DELETE CASCADE,
+    brand VARCHAR(255),
+    max_price DECIMAL(15, 2),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- События каталога, по которым рассылаются уведомления подписчикам.
+-- Событие обрабатывается один раз: после рассылки заполняется processed_at
+CREATE TABLE IF NOT EXISTS "motorcycle_event" (
+    id VARCHAR(255) PRIMARY KEY DEFAULT gen_random_uuid(),
+    motorcycle_id VARCHAR(255) NOT NULL REFERENCES "motorcycle"(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL,
+    old_price DECIMAL(15, 2),
+    price DECIMAL(15, 2) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    locked_until TIMESTAMP,
+    processed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_motorcycle_event_pending ON "motorcycle_event"(created_at) WHERE processed_at IS NULL;
+
+-- Уведомления подписчикам отправляются с фотографией мотоцикла
+ALTER TABLE "notification" ADD COLUMN IF NOT EXISTS photo_url TEXT NOT NULL DEFAULT '';
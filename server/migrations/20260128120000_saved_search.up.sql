@@ -0,0 +1,11 @@
+-- Сохраненные поиски покупателей. Матчер сравнивает с ними опубликованные
+-- и переоцененные мотоциклы и присылает совпадения в бот
+CREATE TABLE IF NOT EXISTS "saved_search" (
+    id VARCHAR(255) PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id VARCHAR(255) NOT NULL REFERENCES "user"(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    filter JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, name)
+);
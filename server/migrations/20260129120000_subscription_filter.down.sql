@@ -0,0 +1,3 @@
+ALTER TABLE "subscription" ADD COLUMN IF NOT EXISTS brand VARCHAR(255), ADD COLUMN IF NOT EXISTS max_price DECIMAL(15, 2);
+UPDATE "subscription" SET brand = filter->>'brand', max_price = (filter->>'maxPrice')::DECIMAL(15, 2);
+ALTER TABLE "subscription" DROP COLUMN IF EXISTS filter;
@@ -0,0 +1,5 @@
+-- Подписка хранит тот же фильтр, что и сохраненный поиск
+ALTER TABLE "subscription" ADD COLUMN IF NOT EXISTS filter JSONB NOT NULL DEFAULT '{}';
+UPDATE "subscription" SET filter = jsonb_strip_nulls(jsonb_build_object('brand', brand, 'maxPrice', max_price));
+ALTER TABLE "subscription" DROP COLUMN IF EXISTS brand, DROP COLUMN IF EXISTS max_price;
+
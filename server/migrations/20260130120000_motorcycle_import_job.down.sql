@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_motorcycle_import_job;
+ALTER TABLE "motorcycle" DROP COLUMN IF EXISTS import_job_id;
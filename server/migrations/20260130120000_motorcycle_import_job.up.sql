@@ -0,0 +1,4 @@
+-- Мотоцикл помнит задачу импорта, которая его создала: повтор задачи после
+-- сбоя находит его и не создает дубль
+ALTER TABLE "motorcycle" ADD COLUMN IF NOT EXISTS import_job_id VARCHAR(255) REFERENCES "import_job"(id) ON DELETE SET NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_motorcycle_import_job ON "motorcycle"(import_job_id) WHERE import_job_id IS NOT NULL;
@@ -31,14 +31,120 @@ type Config struct {
 		BotToken   string `envconfig:"TG_BOT_TOKEN"`
 		WebAppName string `envconfig:"WEBAPP_NAME"`
 	}
-	Storage struct {
-		ImagesPath string `envconfig:"STORAGE_IMAGES_PATH" default:"images"`
-	}
 	Log struct {
 		Handler string `envconfig:"LOG_HANDLER" default:"tint"`
 	}
 
-	S3 S3Config
+	Storage StorageConfig
+	S3      S3Config
+	Import  ImportConfig
+	Photos  PhotosConfig
+	Ingest  IngestConfig
+	Images  ImagesConfig
+	GC      GCConfig
+
+	Reservation  ReservationConfig
+	Notification NotificationConfig
+	Subscription SubscriptionConfig
+}
+
+// ReservationConfig - брони мотоциклов покупателями
+type ReservationConfig struct {
+	// TTL - сколько держится подтвержденная бронь, потом мотоцикл снова available
+	TTL time.Duration `envconfig:"RESERVATION_TTL" default:"72h"`
+	// ExpiryInterval - как часто сервер снимает истекшие брони, 0 отключает проверку
+	ExpiryInterval time.Duration `envconfig:"RESERVATION_EXPIRY_INTERVAL" default:"1m"`
+}
+
+// NotificationConfig - доставка сообщений из очереди notification ботом
+type NotificationConfig struct {
+	PollInterval time.Duration `envconfig:"NOTIFICATION_POLL_INTERVAL" default:"2s"`
+	MaxAttempts  int           `envconfig:"NOTIFICATION_MAX_ATTEMPTS" default:"5"`
+	// RetryBackoff - задержка перед второй попыткой, дальше растет линейно
+	RetryBackoff time.Duration `envconfig:"NOTIFICATION_RETRY_BACKOFF" default:"30s"`
+	// RatePerSecond - сколько сообщений в секунду бот отправляет всего. Telegram допускает около 30
+	RatePerSecond int `envconfig:"NOTIFICATION_RATE_PER_SECOND" default:"25"`
+	// ChatInterval - пауза между сообщениями в один чат
+	ChatInterval time.Duration `envconfig:"NOTIFICATION_CHAT_INTERVAL" default:"1s"`
+}
+
+// SubscriptionConfig - рассылка подписчикам о новых мотоциклах и снижении цен
+type SubscriptionConfig struct {
+	// MatchInterval - как часто сервер разбирает события каталога, 0 отключает рассылку
+	MatchInterval time.Duration `envconfig:"SUBSCRIPTION_MATCH_INTERVAL" default:"10s"`
+}
+
+// GCConfig - сборщик файлов хранилища, на которые не ссылается БД
+type GCConfig struct {
+	// Interval - период запуска в REST-сервере, 0 отключает сборку по расписанию (остается cmd/gc)
+	Interval time.Duration `envconfig:"GC_INTERVAL" default:"0"`
+	// MinAge - файлы моложе этого возраста не трогаем: их может загружать идущий импорт
+	MinAge time.Duration `envconfig:"GC_MIN_AGE" default:"24h"`
+	DryRun bool          `envconfig:"GC_DRY_RUN" default:"false"`
+}
+
+const (
+	StorageBackendS3   = "s3"
+	StorageBackendDisk = "disk"
+
+	// StorageURLPublic - прямые ссылки: S3_ENDPOINT_URL/S3_BUCKET или STORAGE_PUBLIC_URL для disk
+	StorageURLPublic = "public"
+	// StorageURLCDN - ссылки на STORAGE_CDN_URL, за которым стоит бакет или папка
+	StorageURLCDN = "cdn"
+	// StorageURLPresigned - подписанные ссылки на время STORAGE_PRESIGN_TTL, только для s3
+	StorageURLPresigned = "presigned"
+)
+
+// StorageConfig - где хранятся изображения: в S3 или в локальной папке.
+// В БД хранятся только ключи объектов, ссылки строятся при чтении по URLStrategy
+type StorageConfig struct {
+	Backend string `envconfig:"STORAGE_BACKEND" default:"s3"`
+	// ImagesPath - папка с изображениями для бэкенда disk
+	ImagesPath string `envconfig:"STORAGE_IMAGES_PATH" default:"images"`
+	// PublicURL - адрес, по которому REST-сервер раздает ImagesPath (маршрут /images)
+	PublicURL   string `envconfig:"STORAGE_PUBLIC_URL" default:"http://localhost:8000/images"`
+	URLStrategy string `envconfig:"STORAGE_URL_STRATEGY" default:"public"`
+	// CDNURL смотрит в корень бакета (ключи идут вместе с S3_ROOT_DIRECTORY) или в ImagesPath
+	CDNURL     string        `envconfig:"STORAGE_CDN_URL"`
+	PresignTTL time.Duration `envconfig:"STORAGE_PRESIGN_TTL" default:"24h"`
+}
+
+// ImagesConfig - обработка фотографий мотоциклов при загрузке
+type ImagesConfig struct {
+	// CWebPPath - кодировщик WebP (пакет libwebp-tools); без него сохраняются только JPEG
+	CWebPPath   string `envconfig:"IMAGES_CWEBP_PATH" default:"cwebp"`
+	JPEGQuality int    `envconfig:"IMAGES_JPEG_QUALITY" default:"85"`
+	WebPQuality int    `envconfig:"IMAGES_WEBP_QUALITY" default:"80"`
+}
+
+// IngestConfig - загрузка изображений по ссылкам (фото мотоциклов, аватары)
+type IngestConfig struct {
+	// Workers - сколько изображений процесс скачивает одновременно, на все загрузки сразу
+	Workers int `envconfig:"INGEST_WORKERS" default:"4"`
+	// Timeout - таймаут одного запроса, 0 - без таймаута
+	Timeout  time.Duration `envconfig:"INGEST_TIMEOUT" default:"30s"`
+	MaxBytes int64         `envconfig:"INGEST_MAX_BYTES" default:"20971520"`
+	// Retries - сколько раз повторять запрос при сетевой ошибке, 429 или 5xx
+	Retries      int           `envconfig:"INGEST_RETRIES" default:"2"`
+	RetryBackoff time.Duration `envconfig:"INGEST_RETRY_BACKOFF" default:"500ms"`
+}
+
+// PhotosConfig - политика загрузки фотографий при создании мотоцикла
+type PhotosConfig struct {
+	// MaxFailed - сколько фотографий можно потерять, не отменяя создание.
+	// Если не загрузилась ни одна фотография, создание отменяется всегда
+	MaxFailed int `envconfig:"PHOTOS_MAX_FAILED" default:"0"`
+	// MinPublish - сколько фотографий нужно, чтобы опубликовать черновик
+	MinPublish int `envconfig:"PHOTOS_MIN_PUBLISH" default:"1"`
+}
+
+// ImportConfig - настройки фоновых воркеров импорта по ссылке, их запускает только сервер
+type ImportConfig struct {
+	Workers      int           `envconfig:"IMPORT_WORKERS" default:"1"`
+	MaxAttempts  int           `envconfig:"IMPORT_MAX_ATTEMPTS" default:"3"`
+	PollInterval time.Duration `envconfig:"IMPORT_POLL_INTERVAL" default:"2s"`
+	// RetryBackoff - задержка перед второй попыткой, дальше удваивается
+	RetryBackoff time.Duration `envconfig:"IMPORT_RETRY_BACKOFF" default:"30s"`
 }
 
 type S3Config struct {
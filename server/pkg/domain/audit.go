@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+// AuditAction - что админ сделал с мотоциклом
+type AuditAction string
+
+const (
+	AuditActionCreate       AuditAction = "create"
+	AuditActionPatch        AuditAction = "patch"
+	AuditActionStatus       AuditAction = "status"
+	AuditActionArchive      AuditAction = "archive"
+	AuditActionRestore      AuditAction = "restore"
+	AuditActionDelete       AuditAction = "delete"
+	AuditActionPhotoAdd     AuditAction = "photo_add"
+	AuditActionPhotoReplace AuditAction = "photo_replace"
+	AuditActionPhotoDelete  AuditAction = "photo_delete"
+	AuditActionPhotoReorder AuditAction = "photo_reorder"
+)
+
+// AuditChange - значение поля до и после изменения, nil - поля не было
+type AuditChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// AuditEntry - запись журнала изменений мотоцикла. Журнал только дополняется
+// и переживает удаление мотоцикла
+type AuditEntry struct {
+	ID           string `json:"id"`
+	MotorcycleID string `json:"motorcycleId"`
+	// ActorID - ID админа, пустой для изменений без автора
+	ActorID *string     `json:"actorId,omitempty"`
+	Action  AuditAction `json:"action"`
+	// Changes - измененные поля в JSON-именах Motorcycle, фотографии - под ключом photos
+	Changes   map[string]AuditChange `json:"changes"`
+	CreatedAt time.Time              `json:"createdAt"`
+}
+
+type CreateAuditEntry struct {
+	MotorcycleID string
+	ActorID      *string
+	Action       AuditAction
+	Changes      map[string]AuditChange
+}
+
+type FilterAuditEntry struct {
+	MotorcycleID *string
+}
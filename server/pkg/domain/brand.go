@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+// Brand - марка из справочника. Aliases - другие написания марки,
+// которые встречаются в объявлениях ("Harley", "Harley Davidson")
+type Brand struct {
+	ID        string        `json:"id"`
+	Name      string        `json:"name"`
+	Aliases   []string      `json:"aliases"`
+	Models    []*BrandModel `json:"models"`
+	CreatedAt time.Time     `json:"createdAt"`
+	UpdatedAt time.Time     `json:"updatedAt"`
+}
+
+// BrandModel - модель марки из справочника
+type BrandModel struct {
+	ID        string    `json:"id"`
+	BrandID   string    `json:"brandId"`
+	Name      string    `json:"name"`
+	Aliases   []string  `json:"aliases"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type CreateBrand struct {
+	Name    string   `json:"name" minLength:"1" maxLength:"100"`
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+type PatchBrand struct {
+	Name    *string   `json:"name,omitempty" minLength:"1" maxLength:"100"`
+	Aliases *[]string `json:"aliases,omitempty"`
+}
+
+type CreateBrandModel struct {
+	Name    string   `json:"name" minLength:"1" maxLength:"255"`
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+type PatchBrandModel struct {
+	Name    *string   `json:"name,omitempty" minLength:"1" maxLength:"255"`
+	Aliases *[]string `json:"aliases,omitempty"`
+}
+
+type FilterBrand struct {
+	ID *string
+}
@@ -0,0 +1,10 @@
+package domain
+
+import "time"
+
+// Favorite - мотоцикл в избранном пользователя
+type Favorite struct {
+	MotorcycleID string      `json:"motorcycleId"`
+	Motorcycle   *Motorcycle `json:"motorcycle,omitempty"`
+	CreatedAt    time.Time   `json:"createdAt"`
+}
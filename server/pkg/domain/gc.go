@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// GCReport - результат прохода сборщика неиспользуемых файлов хранилища
+type GCReport struct {
+	DryRun bool `json:"dryRun"`
+	// Scanned - сколько файлов найдено в хранилище
+	Scanned    int `json:"scanned"`
+	Referenced int `json:"referenced"`
+	// SkippedYoung - файлы без ссылок, но моложе GC_MIN_AGE
+	SkippedYoung int           `json:"skippedYoung"`
+	Orphaned     []GCObject    `json:"orphaned"`
+	OrphanedSize int64         `json:"orphanedSize"`
+	Deleted      int           `json:"deleted"`
+	StartedAt    time.Time     `json:"startedAt"`
+	Duration     time.Duration `json:"duration"`
+}
+
+type GCObject struct {
+	Key        string    `json:"key"`
+	Size       int64     `json:"size"`
+	ModifiedAt time.Time `json:"modifiedAt"`
+}
@@ -0,0 +1,59 @@
+package domain
+
+import "time"
+
+type ImportJobStatus string
+
+const (
+	ImportJobStatusQueued    ImportJobStatus = "queued"
+	ImportJobStatusRunning   ImportJobStatus = "running"
+	ImportJobStatusSucceeded ImportJobStatus = "succeeded"
+	ImportJobStatusFailed    ImportJobStatus = "failed"
+)
+
+// ImportJobStage - текущий шаг выполнения задачи импорта
+type ImportJobStage string
+
+const (
+	ImportJobStageParse  ImportJobStage = "parse"
+	ImportJobStagePhotos ImportJobStage = "photos"
+)
+
+// ImportJob - задача импорта мотоцикла по ссылке. Выполняется фоновым
+// воркером, при ошибке перезапускается с экспоненциальной задержкой
+type ImportJob struct {
+	ID          string          `json:"id"`
+	SourceURL   string          `json:"sourceUrl"`
+	Status      ImportJobStatus `json:"status"`
+	Stage       ImportJobStage  `json:"stage,omitempty"`
+	PhotosTotal int             `json:"photosTotal"`
+	PhotosDone  int             `json:"photosDone"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"maxAttempts"`
+	// LastError - ошибка последней попытки
+	LastError    string     `json:"lastError,omitempty"`
+	MotorcycleID *string    `json:"motorcycleId,omitempty"`
+	CreatedBy    *string    `json:"createdBy,omitempty"`
+	RunAt        time.Time  `json:"runAt"`
+	StartedAt    *time.Time `json:"startedAt,omitempty"`
+	FinishedAt   *time.Time `json:"finishedAt,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+}
+
+// Finished сообщает, что задача больше не будет выполняться
+func (j *ImportJob) Finished() bool {
+	return j.Status == ImportJobStatusSucceeded || j.Status == ImportJobStatusFailed
+}
+
+type CreateImportJob struct {
+	SourceURL   string
+	MaxAttempts int
+	CreatedBy   *string
+}
+
+type FilterImportJob struct {
+	ID     *string          `json:"id,omitempty"`
+	Status *ImportJobStatus `json:"status,omitempty"`
+	Limit  int              `json:"limit,omitempty"`
+}
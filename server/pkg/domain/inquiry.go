@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+// Inquiry - вопрос покупателя о мотоцикле
+type Inquiry struct {
+	ID           string `json:"id"`
+	MotorcycleID string `json:"motorcycleId"`
+	UserID       string `json:"userId"`
+	Text         string `json:"text"`
+	// Answer - последний ответ админа, пока ответа нет - nil
+	Answer     *string    `json:"answer,omitempty"`
+	AnsweredBy *string    `json:"answeredBy,omitempty"`
+	AnsweredAt *time.Time `json:"answeredAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+type CreateInquiry struct {
+	MotorcycleID string
+	UserID       string
+	Text         string
+}
+
+type FilterInquiry struct {
+	ID           *string `json:"id,omitempty"`
+	MotorcycleID *string `json:"motorcycleId,omitempty"`
+	UserID       *string `json:"userId,omitempty"`
+}
@@ -1,6 +1,10 @@
 package domain
 
-import "time"
+import (
+	"math"
+	"strings"
+	"time"
+)
 
 type MotorcycleStatus string
 
@@ -11,11 +15,29 @@ const (
 	MotorcycleStatusSold      MotorcycleStatus = "sold"
 )
 
+// MotorcycleSort - ключ сортировки каталога
+type MotorcycleSort string
+
+const (
+	// MotorcycleSortDefault - по статусу (available -> reserved -> sold), затем новые
+	MotorcycleSortDefault MotorcycleSort = ""
+	MotorcycleSortNewest  MotorcycleSort = "newest"
+	MotorcycleSortPrice   MotorcycleSort = "price"
+	MotorcycleSortMileage MotorcycleSort = "mileage"
+	MotorcycleSortVolume  MotorcycleSort = "volume"
+	MotorcycleSortYear    MotorcycleSort = "year"
+)
+
+type SortOrder string
+
+const (
+	SortOrderAsc  SortOrder = "asc"
+	SortOrderDesc SortOrder = "desc"
+)
+
+// MotorcycleData - свободные данные объявления. Пробег, объем и год
+// хранятся отдельными полями Motorcycle, чтобы по ним можно было фильтровать
 type MotorcycleData struct {
-	Mileage      *int    `json:"mileage,omitempty"`
-	MileageUnit  string  `json:"mileage_unit,omitempty"`
-	Volume       *int    `json:"volume,omitempty"`
-	VolumeUnit   string  `json:"volume_unit,omitempty"`
 	FrameNumber  string  `json:"frame_number,omitempty"`
 	ArrivalDate  string  `json:"arrival_date,omitempty"`
 }
@@ -25,10 +47,23 @@ type Motorcycle struct {
 	Title       string            `json:"title"`
 	Price       float64           `json:"price"`
 	Currency    string            `json:"currency"`
+	Year        *int              `json:"year,omitempty"`
+	Brand       string            `json:"brand,omitempty"`
+	Model       string            `json:"model,omitempty"`
+	BrandID     *string           `json:"brandId,omitempty"`
+	ModelID     *string           `json:"modelId,omitempty"`
+	MileageKm   *int              `json:"mileageKm,omitempty"`
+	EngineCC    *int              `json:"engineCc,omitempty"`
 	Data        *MotorcycleData   `json:"data,omitempty"`
 	Status      MotorcycleStatus  `json:"status"`
 	SourceURL   string            `json:"sourceUrl"`
 	Photos      []*MotorcyclePhoto `json:"photos,omitempty"`
+	// ArchivedAt задан у мотоциклов в архиве: их нет в каталоге, но их можно восстановить
+	ArchivedAt  *time.Time        `json:"archivedAt,omitempty"`
+	// FavoriteCount - у скольких пользователей мотоцикл в избранном, заполняется только для админов
+	FavoriteCount *int            `json:"favoriteCount,omitempty"`
+	// Version растет при каждом изменении, REST отдает ее как ETag
+	Version     int               `json:"version"`
 	CreatedAt   time.Time         `json:"createdAt"`
 	UpdatedAt   time.Time         `json:"updatedAt"`
 }
@@ -36,49 +71,164 @@ type Motorcycle struct {
 type MotorcyclePhoto struct {
 	ID          string    `json:"id"`
 	MotorcycleID string   `json:"motorcycleId"`
+	// S3URL - ссылка на основной файл, строится из ObjectKey при чтении
 	S3URL       string    `json:"s3Url"`
+	// Variants - уменьшенные копии в JPEG и WebP; у фото, загруженных
+	// до появления вариантов, список пуст и используется S3URL
+	Variants    []PhotoVariant `json:"variants"`
+	// ObjectKey - ключ основного файла в хранилище
+	ObjectKey   string    `json:"-"`
+	// StorageKey - префикс всех файлов фотографии в хранилище
+	StorageKey  string    `json:"-"`
 	Order       int       `json:"order"`
 	CreatedAt   time.Time `json:"createdAt"`
 }
 
+const (
+	PhotoVariantThumb  = "thumb"
+	PhotoVariantMedium = "medium"
+	PhotoVariantFull   = "full"
+
+	PhotoFormatJPEG = "jpeg"
+	PhotoFormatWebP = "webp"
+)
+
+// PhotoVariant - один размер фотографии в одном формате
+type PhotoVariant struct {
+	Name   string `json:"name" enum:"thumb,medium,full"`
+	Format string `json:"format" enum:"jpeg,webp"`
+	URL    string `json:"url"`
+	// Key - ключ файла в хранилище, URL строится из него при чтении
+	Key    string `json:"-"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// CreateMotorcyclePhoto - загруженная фотография: ключ основного файла, варианты
+// и префикс ключей в хранилище, по которому файлы удаляются вместе с фотографией
+type CreateMotorcyclePhoto struct {
+	ObjectKey  string
+	StorageKey string
+	Variants   []PhotoVariant
+}
+
 type CreateMotorcycle struct {
 	Title       string           `json:"title"`
 	Price       float64          `json:"price"`
 	Currency    string           `json:"currency"`
+	Year        *int             `json:"year,omitempty"`
+	Brand       string           `json:"brand,omitempty"`
+	Model       string           `json:"model,omitempty"`
+	BrandID     *string          `json:"brandId,omitempty"`
+	ModelID     *string          `json:"modelId,omitempty"`
+	MileageKm   *int             `json:"mileageKm,omitempty"`
+	EngineCC    *int             `json:"engineCc,omitempty"`
 	Data        *MotorcycleData  `json:"data,omitempty"`
 	Status      MotorcycleStatus `json:"status"`
 	SourceURL   string           `json:"sourceUrl"`
 	PhotoURLs   []string         `json:"photoUrls"`
+	// ImportJobID - задача импорта, которая создает мотоцикл; у одной задачи один мотоцикл
+	ImportJobID *string          `json:"-"`
 }
 
 type PatchMotorcycle struct {
 	Title       *string           `json:"title,omitempty"`
 	Price       *float64          `json:"price,omitempty"`
 	Currency    *string           `json:"currency,omitempty"`
-	Data        *MotorcycleData   `json:"data,omitempty"`
+	Year        *int              `json:"year,omitempty"`
+	Brand       *string           `json:"brand,omitempty"`
+	Model       *string           `json:"model,omitempty"`
+	BrandID     *string           `json:"brandId,omitempty"`
+	ModelID     *string           `json:"modelId,omitempty"`
+	MileageKm   *int              `json:"mileageKm,omitempty"`
+	EngineCC    *int              `json:"engineCc,omitempty"`
+	// Data меняет только перечисленные поля, null удаляет поле
+	Data        MotorcycleDataPatch `json:"data,omitempty"`
 	Status      *MotorcycleStatus `json:"status,omitempty"`
+	// Version - ожидаемая версия (If-Match). Если мотоцикл уже изменился,
+	// изменения не применяются; nil - применить к текущей версии
+	Version     *int              `json:"-"`
 }
 
 type FilterMotorcycle struct {
 	ID     *string           `json:"id,omitempty"`
+	IDs    []string          `json:"ids,omitempty"`
 	Status *MotorcycleStatus `json:"status,omitempty"`
 	Title  *string           `json:"title,omitempty"`
 	MinPrice *float64        `json:"minPrice,omitempty"`
 	MaxPrice *float64        `json:"maxPrice,omitempty"`
+
+	// Query - полнотекстовый поиск по названию и данным
+	Query      *string `json:"query,omitempty"`
+	Brand      *string `json:"brand,omitempty"`
+	MinVolume  *int    `json:"minVolume,omitempty"`
+	MaxVolume  *int    `json:"maxVolume,omitempty"`
+	MaxMileage *int    `json:"maxMileage,omitempty"`
+	MinYear    *int    `json:"minYear,omitempty"`
+	MaxYear    *int    `json:"maxYear,omitempty"`
+	// Archived: true - только архив, false - без архива, nil - все
+	Archived *bool `json:"archived,omitempty"`
+	// ImportJobID - мотоцикл, созданный задачей импорта
+	ImportJobID *string `json:"-"`
+
+	Sort  MotorcycleSort `json:"sort,omitempty"`
+	Order SortOrder      `json:"order,omitempty"`
+	// Limit ограничивает размер страницы, 0 - без ограничения
+	Limit int `json:"limit,omitempty"`
+	// Cursor - непрозрачный курсор из MotorcycleList.NextCursor
+	Cursor string `json:"cursor,omitempty"`
 	
 	IncludePhotos bool `json:"includePhotos"`
 }
 
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// MotorcycleFacets - количество мотоциклов по группам для текущего фильтра
+type MotorcycleFacets struct {
+	Brands   []FacetCount `json:"brands"`
+	Volumes  []FacetCount `json:"volumes"`
+	Mileages []FacetCount `json:"mileages"`
+	Years    []FacetCount `json:"years"`
+}
+
+type MotorcycleList struct {
+	Items []*Motorcycle `json:"items"`
+	// Facets есть только на первой странице, запрос с курсором их не считает
+	Facets *MotorcycleFacets `json:"facets,omitempty"`
+	// NextCursor пустой на последней странице
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
 type CreateMotorcycleFromURL struct {
 	URL string `json:"url"`
 }
 
 type ParsedMotorcycleData struct {
-	Name     string
-	Year     int
-	Mileage  int
-	Volume   int
-	FrameNum string
-	Images   []string
+	Name  string `json:"name"`
+	Brand string `json:"brand"`
+	Model string `json:"model"`
+	// BrandID и ModelID заполняются, если марка и модель нашлись в справочнике
+	BrandID string `json:"brandId,omitempty"`
+	ModelID string `json:"modelId,omitempty"`
+	Year    int    `json:"year"`
+	// Mileage - пробег в километрах
+	Mileage  int      `json:"mileage"`
+	Volume   int      `json:"volume"`
+	FrameNum string   `json:"frame_num"`
+	Images   []string `json:"images"`
+}
+
+const kmPerMile = 1.609344
+
+// MileageToKm переводит пробег в километры, неизвестные единицы считаются километрами
+func MileageToKm(value int, unit string) int {
+	switch strings.ToLower(strings.TrimSpace(unit)) {
+	case "mi", "mile", "miles", "миль", "мили":
+		return int(math.Round(float64(value) * kmPerMile))
+	}
+	return value
 }
 
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// MotorcycleDataPatch - изменение MotorcycleData в духе JSON Merge Patch (RFC 7396):
+// строка задает поле, null удаляет его, поля без ключа не меняются
+type MotorcycleDataPatch map[string]*string
+
+// motorcycleDataFields - JSON-имена полей MotorcycleData
+var motorcycleDataFields = jsonFieldNames(reflect.TypeOf(MotorcycleData{}))
+
+// Validate проверяет, что патч меняет только известные поля MotorcycleData
+func (p MotorcycleDataPatch) Validate() error {
+	for field := range p {
+		if !motorcycleDataFields[field] {
+			return fmt.Errorf("unknown data field %q", field)
+		}
+	}
+	return nil
+}
+
+// Split делит патч на задаваемые поля и удаляемые ключи
+func (p MotorcycleDataPatch) Split() (set map[string]string, removed []string) {
+	set = map[string]string{}
+	removed = []string{}
+	for field, value := range p {
+		if value == nil {
+			removed = append(removed, field)
+			continue
+		}
+		set[field] = *value
+	}
+	return set, removed
+}
+
+// Apply возвращает копию data с примененным патчем, так же как merge patch в БД
+func (p MotorcycleDataPatch) Apply(data *MotorcycleData) (*MotorcycleData, error) {
+	if len(p) == 0 {
+		return data, nil
+	}
+
+	fields := map[string]any{}
+	if data != nil {
+		raw, err := json.Marshal(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal data: %w", err)
+		}
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal data: %w", err)
+		}
+	}
+	set, removed := p.Split()
+	for field, value := range set {
+		fields[field] = value
+	}
+	for _, field := range removed {
+		delete(fields, field)
+	}
+
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal data: %w", err)
+	}
+	patched := &MotorcycleData{}
+	if err := json.Unmarshal(raw, patched); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal data: %w", err)
+	}
+	return patched, nil
+}
+
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+	return names
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestMotorcycleDataPatchValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		patch   MotorcycleDataPatch
+		wantErr bool
+	}{
+		{name: "empty", patch: nil},
+		{name: "known fields", patch: MotorcycleDataPatch{"frame_number": strPtr("NC39"), "arrival_date": nil}},
+		{name: "go field name", patch: MotorcycleDataPatch{"FrameNumber": strPtr("NC39")}, wantErr: true},
+		{name: "unknown field", patch: MotorcycleDataPatch{"color": strPtr("red")}, wantErr: true},
+		{name: "unknown removal", patch: MotorcycleDataPatch{"arrival_date": nil, "color": nil}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.patch.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMotorcycleDataPatchSplit(t *testing.T) {
+	tests := []struct {
+		name        string
+		patch       MotorcycleDataPatch
+		wantSet     map[string]string
+		wantRemoved []string
+	}{
+		{name: "empty", patch: nil, wantSet: map[string]string{}, wantRemoved: []string{}},
+		{
+			name:        "set only",
+			patch:       MotorcycleDataPatch{"frame_number": strPtr("NC39")},
+			wantSet:     map[string]string{"frame_number": "NC39"},
+			wantRemoved: []string{},
+		},
+		{
+			name:        "mixed",
+			patch:       MotorcycleDataPatch{"frame_number": strPtr(""), "arrival_date": nil},
+			wantSet:     map[string]string{"frame_number": ""},
+			wantRemoved: []string{"arrival_date"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set, removed := tt.patch.Split()
+			sort.Strings(removed)
+			if !reflect.DeepEqual(set, tt.wantSet) || !reflect.DeepEqual(removed, tt.wantRemoved) {
+				t.Errorf("Split() = %v, %v, want %v, %v", set, removed, tt.wantSet, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestMotorcycleDataPatchApply(t *testing.T) {
+	data := &MotorcycleData{FrameNumber: "NC39", ArrivalDate: "2026-01-10"}
+	tests := []struct {
+		name  string
+		data  *MotorcycleData
+		patch MotorcycleDataPatch
+		want  *MotorcycleData
+	}{
+		{name: "empty patch", data: data, patch: nil, want: data},
+		{name: "nil data", data: nil, patch: MotorcycleDataPatch{"arrival_date": strPtr("2026-02-01")}, want: &MotorcycleData{ArrivalDate: "2026-02-01"}},
+		{name: "set", data: data, patch: MotorcycleDataPatch{"arrival_date": strPtr("2026-02-01")}, want: &MotorcycleData{FrameNumber: "NC39", ArrivalDate: "2026-02-01"}},
+		{name: "remove", data: data, patch: MotorcycleDataPatch{"frame_number": nil}, want: &MotorcycleData{ArrivalDate: "2026-01-10"}},
+		{name: "remove missing", data: nil, patch: MotorcycleDataPatch{"frame_number": nil}, want: &MotorcycleData{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.patch.Apply(tt.data)
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+	if *data != (MotorcycleData{FrameNumber: "NC39", ArrivalDate: "2026-01-10"}) {
+		t.Errorf("Apply modified its argument: %+v", *data)
+	}
+}
@@ -0,0 +1,13 @@
+package domain
+
+// PublishProblem - чего не хватает черновику для публикации. Message готов к показу админу
+type PublishProblem struct {
+	Field   string `json:"field" enum:"title,price,photos,arrivalDate"`
+	Message string `json:"message"`
+}
+
+// PublishCheck - готовность черновика к публикации
+type PublishCheck struct {
+	Ready    bool             `json:"ready"`
+	Problems []PublishProblem `json:"problems"`
+}
@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+// motorcycleStatusTransitions - разрешенные переходы статуса. Черновик после импорта
+// публикуется, бронь можно снять, продажу - отменить, если сделка сорвалась
+var motorcycleStatusTransitions = map[MotorcycleStatus][]MotorcycleStatus{
+	MotorcycleStatusDraft:     {MotorcycleStatusAvailable},
+	MotorcycleStatusAvailable: {MotorcycleStatusReserved, MotorcycleStatusSold, MotorcycleStatusDraft},
+	MotorcycleStatusReserved:  {MotorcycleStatusAvailable, MotorcycleStatusSold},
+	MotorcycleStatusSold:      {MotorcycleStatusAvailable},
+}
+
+// Valid сообщает, что статус известен
+func (s MotorcycleStatus) Valid() bool {
+	_, ok := motorcycleStatusTransitions[s]
+	return ok
+}
+
+// NextStatuses возвращает статусы, в которые можно перейти из s
+func (s MotorcycleStatus) NextStatuses() []MotorcycleStatus {
+	return motorcycleStatusTransitions[s]
+}
+
+// CanTransitionTo сообщает, разрешен ли переход из s в to
+func (s MotorcycleStatus) CanTransitionTo(to MotorcycleStatus) bool {
+	for _, next := range motorcycleStatusTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// MotorcycleStatusChange - запись истории статусов. From пустой у первой записи,
+// сделанной при создании мотоцикла
+type MotorcycleStatusChange struct {
+	ID           string            `json:"id"`
+	MotorcycleID string            `json:"motorcycleId"`
+	From         *MotorcycleStatus `json:"from,omitempty"`
+	To           MotorcycleStatus  `json:"to"`
+	// ChangedBy - ID админа, пустой для автоматических изменений
+	ChangedBy *string   `json:"changedBy,omitempty"`
+	ChangedAt time.Time `json:"changedAt"`
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMotorcycleStatusNextStatuses(t *testing.T) {
+	tests := []struct {
+		status MotorcycleStatus
+		want   []MotorcycleStatus
+	}{
+		{status: MotorcycleStatusDraft, want: []MotorcycleStatus{MotorcycleStatusAvailable}},
+		{status: MotorcycleStatusAvailable, want: []MotorcycleStatus{MotorcycleStatusReserved, MotorcycleStatusSold, MotorcycleStatusDraft}},
+		{status: MotorcycleStatusReserved, want: []MotorcycleStatus{MotorcycleStatusAvailable, MotorcycleStatusSold}},
+		{status: MotorcycleStatusSold, want: []MotorcycleStatus{MotorcycleStatusAvailable}},
+		{status: "unknown", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			got := tt.status.NextStatuses()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s.NextStatuses() = %v, want %v", tt.status, got, tt.want)
+			}
+			// CanTransitionTo должен соглашаться с NextStatuses
+			for _, next := range got {
+				if !tt.status.CanTransitionTo(next) {
+					t.Errorf("%s.CanTransitionTo(%s) = false, want true", tt.status, next)
+				}
+			}
+			if tt.status.CanTransitionTo(tt.status) {
+				t.Errorf("%s.CanTransitionTo(itself) = true, want false", tt.status)
+			}
+		})
+	}
+}
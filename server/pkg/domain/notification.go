@@ -0,0 +1,27 @@
+package domain
+
+// NotificationButton - inline-кнопка под сообщением. Нажатие приходит боту с Data,
+// а кнопка со StartApp открывает мини-приложение с этим параметром запуска
+type NotificationButton struct {
+	Text     string `json:"text"`
+	Data     string `json:"data,omitempty"`
+	StartApp string `json:"startApp,omitempty"`
+}
+
+// Notification - сообщение в Telegram из очереди, которую разбирает бот
+type Notification struct {
+	ID     string
+	ChatID int64
+	Text   string
+	// PhotoURL - фотография, к которой Text идет подписью
+	PhotoURL string
+	Buttons  []NotificationButton
+	Attempts int
+}
+
+type CreateNotification struct {
+	ChatID   int64
+	Text     string
+	PhotoURL string
+	Buttons  []NotificationButton
+}
@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+type ReservationStatus string
+
+const (
+	// ReservationStatusPending - заявка ждет решения админа
+	ReservationStatusPending  ReservationStatus = "pending"
+	ReservationStatusApproved ReservationStatus = "approved"
+	ReservationStatusDeclined ReservationStatus = "declined"
+	// ReservationStatusExpired - срок брони вышел, мотоцикл вернулся в каталог
+	ReservationStatusExpired ReservationStatus = "expired"
+	// ReservationStatusClosed - админ сам вывел мотоцикл из reserved: продал или вернул в каталог
+	ReservationStatusClosed ReservationStatus = "closed"
+)
+
+// Reservation - заявка покупателя на бронь мотоцикла
+type Reservation struct {
+	ID           string            `json:"id"`
+	MotorcycleID string            `json:"motorcycleId"`
+	UserID       string            `json:"userId"`
+	Status       ReservationStatus `json:"status"`
+	Comment      string            `json:"comment,omitempty"`
+	// DecidedBy - админ, который подтвердил или отклонил заявку
+	DecidedBy *string    `json:"decidedBy,omitempty"`
+	DecidedAt *time.Time `json:"decidedAt,omitempty"`
+	// ExpiresAt - до какого времени держится подтвержденная бронь
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+type CreateReservation struct {
+	MotorcycleID string
+	UserID       string
+	Comment      string
+}
+
+type FilterReservation struct {
+	ID           *string            `json:"id,omitempty"`
+	MotorcycleID *string            `json:"motorcycleId,omitempty"`
+	UserID       *string            `json:"userId,omitempty"`
+	Status       *ReservationStatus `json:"status,omitempty"`
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// SavedSearchFilter - условия сохраненного поиска и подписки, те же, что в FilterMotorcycle.
+// Пустые поля не ограничивают выборку
+type SavedSearchFilter struct {
+	Status     *MotorcycleStatus `json:"status,omitempty"`
+	Title      *string           `json:"title,omitempty"`
+	MinPrice   *float64          `json:"minPrice,omitempty"`
+	MaxPrice   *float64          `json:"maxPrice,omitempty"`
+	Brand      *string           `json:"brand,omitempty"`
+	MinVolume  *int              `json:"minVolume,omitempty"`
+	MaxVolume  *int              `json:"maxVolume,omitempty"`
+	MaxMileage *int              `json:"maxMileage,omitempty"`
+}
+
+// Matches проверяет мотоцикл так же, как фильтр каталога в БД: название ищется
+// по подстроке без учета регистра, а мотоцикл без объема или пробега не проходит
+// ограничения по ним
+func (f *SavedSearchFilter) Matches(motorcycle *Motorcycle) bool {
+	if f.Status != nil && *f.Status != motorcycle.Status {
+		return false
+	}
+	if f.Title != nil && !strings.Contains(strings.ToLower(motorcycle.Title), strings.ToLower(*f.Title)) {
+		return false
+	}
+	if f.Brand != nil && !strings.EqualFold(*f.Brand, motorcycle.Brand) {
+		return false
+	}
+	if f.MinPrice != nil && motorcycle.Price < *f.MinPrice {
+		return false
+	}
+	if f.MaxPrice != nil && motorcycle.Price > *f.MaxPrice {
+		return false
+	}
+	if f.MinVolume != nil && (motorcycle.EngineCC == nil || *motorcycle.EngineCC < *f.MinVolume) {
+		return false
+	}
+	if f.MaxVolume != nil && (motorcycle.EngineCC == nil || *motorcycle.EngineCC > *f.MaxVolume) {
+		return false
+	}
+	if f.MaxMileage != nil && (motorcycle.MileageKm == nil || *motorcycle.MileageKm > *f.MaxMileage) {
+		return false
+	}
+	return true
+}
+
+// SavedSearch - именованный фильтр каталога покупателя
+type SavedSearch struct {
+	ID     string            `json:"id"`
+	UserID string            `json:"userId"`
+	Name   string            `json:"name"`
+	Filter SavedSearchFilter `json:"filter"`
+	// TelegramID - чат владельца, куда бот присылает совпадения
+	TelegramID int64     `json:"-"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+type CreateSavedSearch struct {
+	UserID string
+	Name   string
+	Filter SavedSearchFilter
+}
+
+// UpdateSavedSearch заменяет название и фильтр целиком
+type UpdateSavedSearch struct {
+	Name   string
+	Filter SavedSearchFilter
+}
+
+type FilterSavedSearch struct {
+	ID     *string `json:"id,omitempty"`
+	UserID *string `json:"userId,omitempty"`
+}
@@ -0,0 +1,53 @@
+package domain
+
+import "testing"
+
+func TestSavedSearchFilterMatches(t *testing.T) {
+	str := func(v string) *string { return &v }
+	num := func(v int) *int { return &v }
+	price := func(v float64) *float64 { return &v }
+	available, sold := MotorcycleStatusAvailable, MotorcycleStatusSold
+	motorcycle := &Motorcycle{
+		Status:    MotorcycleStatusAvailable,
+		Title:     "Honda CB 400 Super Four",
+		Brand:     "Honda",
+		Price:     500000,
+		EngineCC:  num(400),
+		MileageKm: num(12000),
+	}
+	unknown := &Motorcycle{Title: "Honda CB 400", Brand: "Honda", Price: 500000}
+
+	tests := []struct {
+		name       string
+		filter     SavedSearchFilter
+		motorcycle *Motorcycle
+		want       bool
+	}{
+		{name: "empty", motorcycle: motorcycle, want: true},
+		{name: "same status", filter: SavedSearchFilter{Status: &available}, motorcycle: motorcycle, want: true},
+		{name: "other status", filter: SavedSearchFilter{Status: &sold}, motorcycle: motorcycle},
+		{name: "brand ignores case", filter: SavedSearchFilter{Brand: str("honda")}, motorcycle: motorcycle, want: true},
+		{name: "other brand", filter: SavedSearchFilter{Brand: str("Yamaha")}, motorcycle: motorcycle},
+		{name: "title substring", filter: SavedSearchFilter{Title: str("super four")}, motorcycle: motorcycle, want: true},
+		{name: "title mismatch", filter: SavedSearchFilter{Title: str("CBR")}, motorcycle: motorcycle},
+		{name: "price in range", filter: SavedSearchFilter{MinPrice: price(500000), MaxPrice: price(500000)}, motorcycle: motorcycle, want: true},
+		{name: "price below min", filter: SavedSearchFilter{MinPrice: price(600000)}, motorcycle: motorcycle},
+		{name: "price above max", filter: SavedSearchFilter{MaxPrice: price(400000)}, motorcycle: motorcycle},
+		{name: "volume in range", filter: SavedSearchFilter{MinVolume: num(400), MaxVolume: num(600)}, motorcycle: motorcycle, want: true},
+		{name: "volume below min", filter: SavedSearchFilter{MinVolume: num(600)}, motorcycle: motorcycle},
+		{name: "volume above max", filter: SavedSearchFilter{MaxVolume: num(250)}, motorcycle: motorcycle},
+		{name: "mileage within max", filter: SavedSearchFilter{MaxMileage: num(12000)}, motorcycle: motorcycle, want: true},
+		{name: "mileage above max", filter: SavedSearchFilter{MaxMileage: num(10000)}, motorcycle: motorcycle},
+		{name: "unknown volume fails volume limit", filter: SavedSearchFilter{MinVolume: num(100)}, motorcycle: unknown},
+		{name: "unknown mileage fails mileage limit", filter: SavedSearchFilter{MaxMileage: num(100000)}, motorcycle: unknown},
+		{name: "unknown fields pass other limits", filter: SavedSearchFilter{Brand: str("Honda"), MaxPrice: price(600000)}, motorcycle: unknown, want: true},
+		{name: "all conditions must match", filter: SavedSearchFilter{Brand: str("Honda"), MaxMileage: num(10000)}, motorcycle: motorcycle},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(tt.motorcycle); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package domain
+
+import "time"
+
+// Subscription - подписка покупателя на уведомления о новых мотоциклах
+// и снижении цен. Поля фильтра лежат на верхнем уровне JSON, как brand
+// и maxPrice в первой версии API
+type Subscription struct {
+	UserID string `json:"userId"`
+	SavedSearchFilter
+	// TelegramID - чат подписчика, куда бот отправляет уведомления
+	TelegramID int64     `json:"-"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+type SetSubscription struct {
+	SavedSearchFilter
+}
+
+type FilterSubscription struct {
+	UserID *string `json:"userId,omitempty"`
+}
+
+type MotorcycleEventKind string
+
+const (
+	// MotorcycleEventPublished - черновик опубликован в каталоге
+	MotorcycleEventPublished MotorcycleEventKind = "published"
+	// MotorcycleEventPriceDrop - снижена цена мотоцикла в каталоге
+	MotorcycleEventPriceDrop MotorcycleEventKind = "price_drop"
+	// MotorcycleEventPriceRise - повышена цена; подписчикам о нем не сообщается, но мотоцикл
+	// мог начать подходить под сохраненный поиск с минимальной ценой
+	MotorcycleEventPriceRise MotorcycleEventKind = "price_rise"
+)
+
+// MotorcycleEvent - изменение каталога, о котором нужно сообщить подписчикам
+type MotorcycleEvent struct {
+	ID           string
+	MotorcycleID string
+	Kind         MotorcycleEventKind
+	// OldPrice - цена до изменения, для MotorcycleEventPriceDrop и MotorcycleEventPriceRise
+	OldPrice  *float64
+	Price     float64
+	Attempts  int
+	CreatedAt time.Time
+}
+
+type CreateMotorcycleEvent struct {
+	MotorcycleID string
+	Kind         MotorcycleEventKind
+	OldPrice     *float64
+	Price        float64
+}
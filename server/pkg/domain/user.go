@@ -28,4 +28,5 @@ type PatchUser struct {
 type FilterUser struct {
 	ID         *string `json:"id"`
 	TelegramID *int64  `json:"telegramId"`
+	IsAdmin    *bool   `json:"isAdmin"`
 }
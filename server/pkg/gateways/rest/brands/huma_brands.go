@@ -0,0 +1,296 @@
+package brands
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/gateways/rest/auth"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+	"github.com/shampsdev/go-telegram-template/pkg/usecase"
+)
+
+// brandError переводит ошибки справочника в HTTP-статусы
+func brandError(msg string, err error) error {
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		return huma.Error404NotFound("brand or model not found", err)
+	case errors.Is(err, repo.ErrAlreadyExists):
+		return huma.Error409Conflict("brand or model with this name already exists", err)
+	default:
+		return huma.Error500InternalServerError(msg, err)
+	}
+}
+
+type ListBrandsInput struct {
+	XAPIToken string `header:"X-API-Token" required:"true" doc:"Telegram init data"`
+}
+
+type ListBrandsOutput struct {
+	Body []*domain.Brand `json:"body"`
+}
+
+func ListBrandsHandler(brandCase *usecase.Brand, userCase *usecase.User) func(ctx context.Context, input *ListBrandsInput) (*ListBrandsOutput, error) {
+	return func(ctx context.Context, input *ListBrandsInput) (*ListBrandsOutput, error) {
+		_, err := auth.RequireAdminFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error403Forbidden("admin access required", err)
+		}
+
+		brands, err := brandCase.ListBrands(ctx)
+		if err != nil {
+			return nil, brandError("failed to list brands", err)
+		}
+
+		return &ListBrandsOutput{Body: brands}, nil
+	}
+}
+
+type GetBrandInput struct {
+	XAPIToken string `header:"X-API-Token" required:"true" doc:"Telegram init data"`
+	ID        string `path:"id" doc:"Brand ID"`
+}
+
+type BrandOutput struct {
+	Body domain.Brand `json:"body"`
+}
+
+func GetBrandHandler(brandCase *usecase.Brand, userCase *usecase.User) func(ctx context.Context, input *GetBrandInput) (*BrandOutput, error) {
+	return func(ctx context.Context, input *GetBrandInput) (*BrandOutput, error) {
+		_, err := auth.RequireAdminFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error403Forbidden("admin access required", err)
+		}
+
+		brand, err := brandCase.GetBrand(ctx, input.ID)
+		if err != nil {
+			return nil, brandError("failed to get brand", err)
+		}
+
+		return &BrandOutput{Body: *brand}, nil
+	}
+}
+
+type CreateBrandInput struct {
+	XAPIToken string             `header:"X-API-Token" required:"true" doc:"Telegram init data"`
+	Body      domain.CreateBrand `json:"body"`
+}
+
+func CreateBrandHandler(brandCase *usecase.Brand, userCase *usecase.User) func(ctx context.Context, input *CreateBrandInput) (*BrandOutput, error) {
+	return func(ctx context.Context, input *CreateBrandInput) (*BrandOutput, error) {
+		user, err := auth.RequireAdminFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error403Forbidden("admin access required", err)
+		}
+
+		brand, err := brandCase.CreateBrand(usecase.NewContext(ctx, user), &input.Body)
+		if err != nil {
+			return nil, brandError("failed to create brand", err)
+		}
+
+		return &BrandOutput{Body: *brand}, nil
+	}
+}
+
+type PatchBrandInput struct {
+	XAPIToken string            `header:"X-API-Token" required:"true" doc:"Telegram init data"`
+	ID        string            `path:"id" doc:"Brand ID"`
+	Body      domain.PatchBrand `json:"body"`
+}
+
+func PatchBrandHandler(brandCase *usecase.Brand, userCase *usecase.User) func(ctx context.Context, input *PatchBrandInput) (*BrandOutput, error) {
+	return func(ctx context.Context, input *PatchBrandInput) (*BrandOutput, error) {
+		user, err := auth.RequireAdminFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error403Forbidden("admin access required", err)
+		}
+
+		brand, err := brandCase.PatchBrand(usecase.NewContext(ctx, user), input.ID, &input.Body)
+		if err != nil {
+			return nil, brandError("failed to update brand", err)
+		}
+
+		return &BrandOutput{Body: *brand}, nil
+	}
+}
+
+type DeleteBrandInput struct {
+	XAPIToken string `header:"X-API-Token" required:"true" doc:"Telegram init data"`
+	ID        string `path:"id" doc:"Brand ID"`
+}
+
+func DeleteBrandHandler(brandCase *usecase.Brand, userCase *usecase.User) func(ctx context.Context, input *DeleteBrandInput) (*struct{}, error) {
+	return func(ctx context.Context, input *DeleteBrandInput) (*struct{}, error) {
+		user, err := auth.RequireAdminFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error403Forbidden("admin access required", err)
+		}
+
+		if err := brandCase.DeleteBrand(usecase.NewContext(ctx, user), input.ID); err != nil {
+			return nil, brandError("failed to delete brand", err)
+		}
+
+		return nil, nil
+	}
+}
+
+type CreateModelInput struct {
+	XAPIToken string                  `header:"X-API-Token" required:"true" doc:"Telegram init data"`
+	BrandID   string                  `path:"id" doc:"Brand ID"`
+	Body      domain.CreateBrandModel `json:"body"`
+}
+
+func CreateModelHandler(brandCase *usecase.Brand, userCase *usecase.User) func(ctx context.Context, input *CreateModelInput) (*BrandOutput, error) {
+	return func(ctx context.Context, input *CreateModelInput) (*BrandOutput, error) {
+		user, err := auth.RequireAdminFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error403Forbidden("admin access required", err)
+		}
+
+		brand, err := brandCase.CreateModel(usecase.NewContext(ctx, user), input.BrandID, &input.Body)
+		if err != nil {
+			return nil, brandError("failed to create model", err)
+		}
+
+		return &BrandOutput{Body: *brand}, nil
+	}
+}
+
+type PatchModelInput struct {
+	XAPIToken string                 `header:"X-API-Token" required:"true" doc:"Telegram init data"`
+	BrandID   string                 `path:"id" doc:"Brand ID"`
+	ModelID   string                 `path:"modelId" doc:"Model ID"`
+	Body      domain.PatchBrandModel `json:"body"`
+}
+
+func PatchModelHandler(brandCase *usecase.Brand, userCase *usecase.User) func(ctx context.Context, input *PatchModelInput) (*BrandOutput, error) {
+	return func(ctx context.Context, input *PatchModelInput) (*BrandOutput, error) {
+		user, err := auth.RequireAdminFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error403Forbidden("admin access required", err)
+		}
+
+		brand, err := brandCase.PatchModel(usecase.NewContext(ctx, user), input.BrandID, input.ModelID, &input.Body)
+		if err != nil {
+			return nil, brandError("failed to update model", err)
+		}
+
+		return &BrandOutput{Body: *brand}, nil
+	}
+}
+
+type DeleteModelInput struct {
+	XAPIToken string `header:"X-API-Token" required:"true" doc:"Telegram init data"`
+	BrandID   string `path:"id" doc:"Brand ID"`
+	ModelID   string `path:"modelId" doc:"Model ID"`
+}
+
+func DeleteModelHandler(brandCase *usecase.Brand, userCase *usecase.User) func(ctx context.Context, input *DeleteModelInput) (*struct{}, error) {
+	return func(ctx context.Context, input *DeleteModelInput) (*struct{}, error) {
+		user, err := auth.RequireAdminFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error403Forbidden("admin access required", err)
+		}
+
+		if err := brandCase.DeleteModel(usecase.NewContext(ctx, user), input.BrandID, input.ModelID); err != nil {
+			return nil, brandError("failed to delete model", err)
+		}
+
+		return nil, nil
+	}
+}
+
+func SetupHuma(api huma.API, cases usecase.Cases) {
+	// Справочник марок и моделей (только для админов)
+	huma.Register(api, huma.Operation{
+		OperationID: "list-brands",
+		Method:      http.MethodGet,
+		Path:        "/admin/brands",
+		Summary:     "List brands with models and aliases (admin only)",
+		Tags:        []string{"admin", "brands"},
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, ListBrandsHandler(cases.Brand, cases.User))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-brand",
+		Method:      http.MethodGet,
+		Path:        "/admin/brands/{id}",
+		Summary:     "Get brand with models (admin only)",
+		Tags:        []string{"admin", "brands"},
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, GetBrandHandler(cases.Brand, cases.User))
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "create-brand",
+		Method:        http.MethodPost,
+		Path:          "/admin/brands",
+		Summary:       "Create brand (admin only)",
+		Tags:          []string{"admin", "brands"},
+		DefaultStatus: http.StatusCreated,
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, CreateBrandHandler(cases.Brand, cases.User))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "patch-brand",
+		Method:      http.MethodPatch,
+		Path:        "/admin/brands/{id}",
+		Summary:     "Update brand name or aliases (admin only)",
+		Tags:        []string{"admin", "brands"},
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, PatchBrandHandler(cases.Brand, cases.User))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "delete-brand",
+		Method:      http.MethodDelete,
+		Path:        "/admin/brands/{id}",
+		Summary:     "Delete brand with its models (admin only)",
+		Tags:        []string{"admin", "brands"},
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, DeleteBrandHandler(cases.Brand, cases.User))
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "create-brand-model",
+		Method:        http.MethodPost,
+		Path:          "/admin/brands/{id}/models",
+		Summary:       "Add model to brand (admin only)",
+		Tags:          []string{"admin", "brands"},
+		DefaultStatus: http.StatusCreated,
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, CreateModelHandler(cases.Brand, cases.User))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "patch-brand-model",
+		Method:      http.MethodPatch,
+		Path:        "/admin/brands/{id}/models/{modelId}",
+		Summary:     "Update model name or aliases (admin only)",
+		Tags:        []string{"admin", "brands"},
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, PatchModelHandler(cases.Brand, cases.User))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "delete-brand-model",
+		Method:      http.MethodDelete,
+		Path:        "/admin/brands/{id}/models/{modelId}",
+		Summary:     "Delete model (admin only)",
+		Tags:        []string{"admin", "brands"},
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, DeleteModelHandler(cases.Brand, cases.User))
+}
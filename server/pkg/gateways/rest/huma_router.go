@@ -13,7 +13,11 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 	"github.com/shampsdev/go-telegram-template/pkg/gateways/rest/analytics"
+	"github.com/shampsdev/go-telegram-template/pkg/gateways/rest/brands"
+	"github.com/shampsdev/go-telegram-template/pkg/gateways/rest/imports"
+	"github.com/shampsdev/go-telegram-template/pkg/gateways/rest/inquiries"
 	"github.com/shampsdev/go-telegram-template/pkg/gateways/rest/motorcycles"
+	"github.com/shampsdev/go-telegram-template/pkg/gateways/rest/reservations"
 	"github.com/shampsdev/go-telegram-template/pkg/gateways/rest/user"
 	"github.com/shampsdev/go-telegram-template/pkg/usecase"
 	"github.com/shampsdev/go-telegram-template/pkg/utils/slogx"
@@ -53,7 +57,11 @@ func extractUserInfoFromToken(token string) string {
 func setupHumaRouter(api huma.API, useCases usecase.Cases) {
 	user.SetupHuma(api, useCases)
 	motorcycles.SetupHuma(api, useCases)
+	brands.SetupHuma(api, useCases)
+	imports.SetupHuma(api, useCases)
 	analytics.SetupHuma(api, useCases)
+	reservations.SetupHuma(api, useCases)
+	inquiries.SetupHuma(api, useCases)
 }
 
 func NewHumaAPI(ctx context.Context, useCases usecase.Cases) (huma.API, *chi.Mux) {
@@ -92,8 +100,8 @@ func NewHumaAPI(ctx context.Context, useCases usecase.Cases) (huma.API, *chi.Mux
 	router.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Api-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Api-Token", "If-Match"},
+		ExposedHeaders:   []string{"Link", "ETag"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
@@ -0,0 +1,98 @@
+package imports
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/gateways/rest/auth"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+	"github.com/shampsdev/go-telegram-template/pkg/usecase"
+)
+
+type ListImportsInput struct {
+	XAPIToken string `header:"X-API-Token" required:"true" doc:"Telegram init data"`
+	Status    string `query:"status" enum:"queued,running,succeeded,failed" doc:"Filter by job status"`
+	Limit     int    `query:"limit" minimum:"1" maximum:"100" default:"20" doc:"Page size"`
+}
+
+type ListImportsOutput struct {
+	Body []*domain.ImportJob `json:"body"`
+}
+
+func ListImportsHandler(importCase *usecase.Import, userCase *usecase.User) func(ctx context.Context, input *ListImportsInput) (*ListImportsOutput, error) {
+	return func(ctx context.Context, input *ListImportsInput) (*ListImportsOutput, error) {
+		_, err := auth.RequireAdminFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error403Forbidden("admin access required", err)
+		}
+
+		filter := &domain.FilterImportJob{Limit: input.Limit}
+		if input.Status != "" {
+			status := domain.ImportJobStatus(input.Status)
+			filter.Status = &status
+		}
+
+		jobs, err := importCase.ListJobs(ctx, filter)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("failed to list import jobs", err)
+		}
+
+		return &ListImportsOutput{Body: jobs}, nil
+	}
+}
+
+type GetImportInput struct {
+	XAPIToken string `header:"X-API-Token" required:"true" doc:"Telegram init data"`
+	ID        string `path:"id" doc:"Import job ID"`
+}
+
+type GetImportOutput struct {
+	Body domain.ImportJob `json:"body"`
+}
+
+func GetImportHandler(importCase *usecase.Import, userCase *usecase.User) func(ctx context.Context, input *GetImportInput) (*GetImportOutput, error) {
+	return func(ctx context.Context, input *GetImportInput) (*GetImportOutput, error) {
+		_, err := auth.RequireAdminFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error403Forbidden("admin access required", err)
+		}
+
+		job, err := importCase.GetJob(ctx, input.ID)
+		if errors.Is(err, repo.ErrNotFound) {
+			return nil, huma.Error404NotFound("import job not found", err)
+		}
+		if err != nil {
+			return nil, huma.Error500InternalServerError("failed to get import job", err)
+		}
+
+		return &GetImportOutput{Body: *job}, nil
+	}
+}
+
+func SetupHuma(api huma.API, cases usecase.Cases) {
+	// Очередь импорта по ссылке (только для админов)
+	huma.Register(api, huma.Operation{
+		OperationID: "list-imports",
+		Method:      http.MethodGet,
+		Path:        "/admin/imports",
+		Summary:     "List import jobs, newest first (admin only)",
+		Tags:        []string{"admin", "imports"},
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, ListImportsHandler(cases.Import, cases.User))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-import",
+		Method:      http.MethodGet,
+		Path:        "/admin/imports/{id}",
+		Summary:     "Get import job status and progress (admin only)",
+		Tags:        []string{"admin", "imports"},
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, GetImportHandler(cases.Import, cases.User))
+}
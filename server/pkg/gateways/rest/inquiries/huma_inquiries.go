@@ -0,0 +1,63 @@
+package inquiries
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/gateways/rest/auth"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+	"github.com/shampsdev/go-telegram-template/pkg/usecase"
+)
+
+type AskInput struct {
+	XAPIToken string `header:"X-API-Token" required:"true" doc:"Telegram init data"`
+	ID        string `path:"id" doc:"Motorcycle ID"`
+	Body      struct {
+		Text string `json:"text" minLength:"1" maxLength:"1000" doc:"Question for the manager"`
+	} `json:"body"`
+}
+
+type AskOutput struct {
+	Body domain.Inquiry `json:"body"`
+}
+
+func AskHandler(inquiryCase *usecase.Inquiry, userCase *usecase.User) func(ctx context.Context, input *AskInput) (*AskOutput, error) {
+	return func(ctx context.Context, input *AskInput) (*AskOutput, error) {
+		user, err := auth.AuthenticateFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error401Unauthorized("failed to authenticate user", err)
+		}
+
+		text := strings.TrimSpace(input.Body.Text)
+		if text == "" {
+			return nil, huma.Error400BadRequest("question text is empty")
+		}
+
+		inquiry, err := inquiryCase.Ask(usecase.NewContext(ctx, user), input.ID, text)
+		if errors.Is(err, repo.ErrNotFound) {
+			return nil, huma.Error404NotFound("motorcycle not found", err)
+		}
+		if err != nil {
+			return nil, huma.Error500InternalServerError("failed to send question", err)
+		}
+		return &AskOutput{Body: *inquiry}, nil
+	}
+}
+
+func SetupHuma(api huma.API, cases usecase.Cases) {
+	huma.Register(api, huma.Operation{
+		OperationID:   "ask-about-motorcycle",
+		Method:        http.MethodPost,
+		Path:          "/motorcycles/{id}/inquiries",
+		Summary:       "Ask managers a question about the motorcycle, the answer comes from the bot",
+		Tags:          []string{"inquiries"},
+		DefaultStatus: http.StatusCreated,
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, AskHandler(cases.Inquiry, cases.User))
+}
@@ -0,0 +1,33 @@
+package motorcycles
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+)
+
+// motorcycleETag - сильный ETag мотоцикла, построенный из его версии
+func motorcycleETag(motorcycle *domain.Motorcycle) string {
+	return strconv.Quote(strconv.Itoa(motorcycle.Version))
+}
+
+// parseIfMatch извлекает версию из If-Match. Пустой заголовок и * означают
+// любую версию (nil). Нечисловой ETag не может совпасть с нашим, поэтому это 412
+func parseIfMatch(value string) (*int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "*" {
+		return nil, nil
+	}
+	if strings.Contains(value, ",") {
+		return nil, huma.Error400BadRequest("If-Match must contain a single ETag")
+	}
+
+	version, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(value, "W/"), `"`))
+	if err != nil {
+		return nil, huma.Error412PreconditionFailed(fmt.Sprintf("If-Match %s does not match the motorcycle", value))
+	}
+	return &version, nil
+}
@@ -0,0 +1,58 @@
+package motorcycles
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+)
+
+func TestParseIfMatch(t *testing.T) {
+	version := func(v int) *int { return &v }
+	tests := []struct {
+		name       string
+		value      string
+		want       *int
+		wantStatus int
+	}{
+		{name: "empty", value: ""},
+		{name: "spaces", value: "  "},
+		{name: "any", value: "*"},
+		{name: "strong", value: `"7"`, want: version(7)},
+		{name: "weak", value: `W/"7"`, want: version(7)},
+		{name: "unquoted", value: "7", want: version(7)},
+		{name: "padded", value: ` "12" `, want: version(12)},
+		{name: "list", value: `"7", "8"`, wantStatus: http.StatusBadRequest},
+		{name: "foreign etag", value: `"abc"`, wantStatus: http.StatusPreconditionFailed},
+		{name: "empty etag", value: `""`, wantStatus: http.StatusPreconditionFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseIfMatch(tt.value)
+			if tt.wantStatus != 0 {
+				var statusErr huma.StatusError
+				if !errors.As(err, &statusErr) || statusErr.GetStatus() != tt.wantStatus {
+					t.Fatalf("parseIfMatch(%q) error = %v, want status %d", tt.value, err, tt.wantStatus)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseIfMatch(%q) error = %v", tt.value, err)
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("parseIfMatch(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestETagRoundTrip(t *testing.T) {
+	for _, v := range []int{0, 1, 42} {
+		got, err := parseIfMatch(motorcycleETag(&domain.Motorcycle{Version: v}))
+		if err != nil || got == nil || *got != v {
+			t.Errorf("parseIfMatch(motorcycleETag(version %d)) = %v, %v", v, got, err)
+		}
+	}
+}
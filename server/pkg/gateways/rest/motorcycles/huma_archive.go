@@ -0,0 +1,114 @@
+package motorcycles
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/gateways/rest/auth"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+	"github.com/shampsdev/go-telegram-template/pkg/usecase"
+)
+
+// archiveError переводит ошибки архива и удаления в HTTP-статусы
+func archiveError(msg string, err error) error {
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		return huma.Error404NotFound("motorcycle not found", err)
+	case errors.Is(err, usecase.ErrNotArchived):
+		return huma.Error409Conflict(msg, err)
+	default:
+		return huma.Error500InternalServerError(msg, err)
+	}
+}
+
+type ArchiveMotorcycleInput struct {
+	XAPIToken string `header:"X-API-Token" required:"true" doc:"Telegram init data"`
+	ID        string `path:"id" doc:"Motorcycle ID"`
+}
+
+type ArchiveMotorcycleOutput struct {
+	Body domain.Motorcycle `json:"body"`
+}
+
+func ArchiveMotorcycleHandler(motorcycleCase *usecase.Motorcycle, userCase *usecase.User) func(ctx context.Context, input *ArchiveMotorcycleInput) (*ArchiveMotorcycleOutput, error) {
+	return func(ctx context.Context, input *ArchiveMotorcycleInput) (*ArchiveMotorcycleOutput, error) {
+		user, err := auth.RequireAdminFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error403Forbidden("admin access required", err)
+		}
+
+		motorcycle, err := motorcycleCase.ArchiveMotorcycle(usecase.NewContext(ctx, user), input.ID)
+		if err != nil {
+			return nil, archiveError("failed to archive motorcycle", err)
+		}
+		return &ArchiveMotorcycleOutput{Body: *motorcycle}, nil
+	}
+}
+
+func RestoreMotorcycleHandler(motorcycleCase *usecase.Motorcycle, userCase *usecase.User) func(ctx context.Context, input *ArchiveMotorcycleInput) (*ArchiveMotorcycleOutput, error) {
+	return func(ctx context.Context, input *ArchiveMotorcycleInput) (*ArchiveMotorcycleOutput, error) {
+		user, err := auth.RequireAdminFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error403Forbidden("admin access required", err)
+		}
+
+		motorcycle, err := motorcycleCase.RestoreMotorcycle(usecase.NewContext(ctx, user), input.ID)
+		if err != nil {
+			return nil, archiveError("failed to restore motorcycle", err)
+		}
+		return &ArchiveMotorcycleOutput{Body: *motorcycle}, nil
+	}
+}
+
+func DeleteMotorcycleHandler(motorcycleCase *usecase.Motorcycle, userCase *usecase.User) func(ctx context.Context, input *ArchiveMotorcycleInput) (*struct{}, error) {
+	return func(ctx context.Context, input *ArchiveMotorcycleInput) (*struct{}, error) {
+		user, err := auth.RequireAdminFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error403Forbidden("admin access required", err)
+		}
+
+		if err := motorcycleCase.DeleteMotorcycle(usecase.NewContext(ctx, user), input.ID); err != nil {
+			return nil, archiveError("failed to delete motorcycle", err)
+		}
+		return nil, nil
+	}
+}
+
+func setupArchiveHuma(api huma.API, cases usecase.Cases) {
+	huma.Register(api, huma.Operation{
+		OperationID: "archive-motorcycle",
+		Method:      http.MethodPost,
+		Path:        "/admin/motorcycle/{id}/archive",
+		Summary:     "Hide motorcycle from the catalog keeping its data (admin only)",
+		Tags:        []string{"admin", "motorcycles"},
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, ArchiveMotorcycleHandler(cases.Motorcycle, cases.User))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "restore-motorcycle",
+		Method:      http.MethodPost,
+		Path:        "/admin/motorcycle/{id}/restore",
+		Summary:     "Return archived motorcycle to the catalog (admin only)",
+		Tags:        []string{"admin", "motorcycles"},
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, RestoreMotorcycleHandler(cases.Motorcycle, cases.User))
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "delete-motorcycle",
+		Method:        http.MethodDelete,
+		Path:          "/admin/motorcycle/{id}",
+		Summary:       "Delete archived motorcycle and its photos for good (admin only)",
+		Tags:          []string{"admin", "motorcycles"},
+		DefaultStatus: http.StatusNoContent,
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, DeleteMotorcycleHandler(cases.Motorcycle, cases.User))
+}
@@ -0,0 +1,49 @@
+package motorcycles
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/gateways/rest/auth"
+	"github.com/shampsdev/go-telegram-template/pkg/usecase"
+)
+
+type GetAuditLogInput struct {
+	XAPIToken string `header:"X-API-Token" required:"true" doc:"Telegram init data"`
+	ID        string `path:"id" doc:"Motorcycle ID"`
+}
+
+type GetAuditLogOutput struct {
+	Body []*domain.AuditEntry `json:"body"`
+}
+
+func GetAuditLogHandler(motorcycleCase *usecase.Motorcycle, userCase *usecase.User) func(ctx context.Context, input *GetAuditLogInput) (*GetAuditLogOutput, error) {
+	return func(ctx context.Context, input *GetAuditLogInput) (*GetAuditLogOutput, error) {
+		user, err := auth.RequireAdminFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error403Forbidden("admin access required", err)
+		}
+
+		// Мотоцикл не проверяем: журнал удаленного тоже должен открываться
+		entries, err := motorcycleCase.AuditLog(usecase.NewContext(ctx, user), input.ID)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("failed to get audit log", err)
+		}
+		return &GetAuditLogOutput{Body: entries}, nil
+	}
+}
+
+func setupAuditHuma(api huma.API, cases usecase.Cases) {
+	huma.Register(api, huma.Operation{
+		OperationID: "get-motorcycle-audit-log",
+		Method:      http.MethodGet,
+		Path:        "/admin/motorcycle/{id}/audit",
+		Summary:     "Get who changed what in the motorcycle, oldest first (admin only)",
+		Tags:        []string{"admin", "motorcycles"},
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, GetAuditLogHandler(cases.Motorcycle, cases.User))
+}
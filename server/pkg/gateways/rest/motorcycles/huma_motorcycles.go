@@ -2,11 +2,13 @@ package motorcycles
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/shampsdev/go-telegram-template/pkg/domain"
 	"github.com/shampsdev/go-telegram-template/pkg/gateways/rest/auth"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
 	"github.com/shampsdev/go-telegram-template/pkg/usecase"
 )
 
@@ -18,20 +20,40 @@ type GetMotorcyclesInput struct {
 	Title     string  `query:"title" doc:"Filter by title (partial match)"`
 	MinPrice  float64 `query:"minPrice" doc:"Minimum price"`
 	MaxPrice  float64 `query:"maxPrice" doc:"Maximum price"`
+	Query      string `query:"q" doc:"Full-text search, understands hints like \"Honda 400 до 50к км\""`
+	Brand      string `query:"brand" doc:"Filter by brand"`
+	MinVolume  int    `query:"minVolume" doc:"Minimum engine volume, cc"`
+	MaxVolume  int    `query:"maxVolume" doc:"Maximum engine volume, cc"`
+	MaxMileage int    `query:"maxMileage" doc:"Maximum mileage, km"`
+	MinYear    int    `query:"minYear" doc:"Minimum model year"`
+	MaxYear    int    `query:"maxYear" doc:"Maximum model year"`
+	Sort       string `query:"sort" enum:"newest,price,mileage,volume,year" doc:"Sort key, by default available first, then newest"`
+	Order      string `query:"order" enum:"asc,desc" doc:"Sort direction, default depends on sort key"`
+	Limit      int    `query:"limit" minimum:"1" maximum:"100" default:"20" doc:"Page size"`
+	Cursor     string `query:"cursor" doc:"Opaque cursor from nextCursor of the previous page"`
+	Archived   bool   `query:"archived" doc:"Show archived motorcycles instead of the catalog (admin only)"`
 }
 
 type GetMotorcyclesOutput struct {
-	Body []domain.Motorcycle `json:"body"`
+	Body domain.MotorcycleList `json:"body"`
 }
 
-func GetMotorcyclesHandler(motorcycleCase *usecase.Motorcycle, userCase *usecase.User) func(ctx context.Context, input *GetMotorcyclesInput) (*GetMotorcyclesOutput, error) {
+func GetMotorcyclesHandler(motorcycleCase *usecase.Motorcycle, favoriteCase *usecase.Favorite, userCase *usecase.User) func(ctx context.Context, input *GetMotorcyclesInput) (*GetMotorcyclesOutput, error) {
 	return func(ctx context.Context, input *GetMotorcyclesInput) (*GetMotorcyclesOutput, error) {
-		_, err := auth.AuthenticateFromInput(ctx, input.XAPIToken, userCase)
+		user, err := auth.AuthenticateFromInput(ctx, input.XAPIToken, userCase)
 		if err != nil {
 			return nil, huma.Error401Unauthorized("authentication required", err)
 		}
+		if input.Archived && !user.IsAdmin {
+			return nil, huma.Error403Forbidden("admin access required")
+		}
 
 		filter := &domain.FilterMotorcycle{
+			Archived:      &input.Archived,
+			Sort:          domain.MotorcycleSort(input.Sort),
+			Order:         domain.SortOrder(input.Order),
+			Limit:         input.Limit,
+			Cursor:        input.Cursor,
 			IncludePhotos: true,
 		}
 
@@ -48,18 +70,42 @@ func GetMotorcyclesHandler(motorcycleCase *usecase.Motorcycle, userCase *usecase
 		if input.MaxPrice > 0 {
 			filter.MaxPrice = &input.MaxPrice
 		}
+		if input.Query != "" {
+			filter.Query = &input.Query
+		}
+		if input.Brand != "" {
+			filter.Brand = &input.Brand
+		}
+		if input.MinVolume > 0 {
+			filter.MinVolume = &input.MinVolume
+		}
+		if input.MaxVolume > 0 {
+			filter.MaxVolume = &input.MaxVolume
+		}
+		if input.MaxMileage > 0 {
+			filter.MaxMileage = &input.MaxMileage
+		}
+		if input.MinYear > 0 {
+			filter.MinYear = &input.MinYear
+		}
+		if input.MaxYear > 0 {
+			filter.MaxYear = &input.MaxYear
+		}
 
 		motorcycles, err := motorcycleCase.ListMotorcycles(ctx, filter)
+		if errors.Is(err, repo.ErrInvalidCursor) {
+			return nil, huma.Error400BadRequest("invalid cursor", err)
+		}
 		if err != nil {
 			return nil, huma.Error400BadRequest("failed to get motorcycles", err)
 		}
-
-		result := make([]domain.Motorcycle, len(motorcycles))
-		for i, m := range motorcycles {
-			result[i] = *m
+		if user.IsAdmin {
+			if err := favoriteCase.FillFavoriteCounts(ctx, motorcycles.Items...); err != nil {
+				return nil, huma.Error500InternalServerError("failed to count favorites", err)
+			}
 		}
 
-		return &GetMotorcyclesOutput{Body: result}, nil
+		return &GetMotorcyclesOutput{Body: *motorcycles}, nil
 	}
 }
 
@@ -69,13 +115,14 @@ type GetMotorcycleInput struct {
 }
 
 type GetMotorcycleOutput struct {
+	ETag string            `header:"ETag" doc:"Motorcycle version, pass it in If-Match to PATCH"`
 	Body domain.Motorcycle `json:"body"`
 }
 
-func GetMotorcycleHandler(motorcycleCase *usecase.Motorcycle, userCase *usecase.User) func(ctx context.Context, input *GetMotorcycleInput) (*GetMotorcycleOutput, error) {
+func GetMotorcycleHandler(motorcycleCase *usecase.Motorcycle, favoriteCase *usecase.Favorite, userCase *usecase.User) func(ctx context.Context, input *GetMotorcycleInput) (*GetMotorcycleOutput, error) {
 	return func(ctx context.Context, input *GetMotorcycleInput) (*GetMotorcycleOutput, error) {
 		// Проверяем аутентификацию пользователя
-		_, err := auth.AuthenticateFromInput(ctx, input.XAPIToken, userCase)
+		user, err := auth.AuthenticateFromInput(ctx, input.XAPIToken, userCase)
 		if err != nil {
 			return nil, huma.Error401Unauthorized("authentication required", err)
 		}
@@ -84,8 +131,17 @@ func GetMotorcycleHandler(motorcycleCase *usecase.Motorcycle, userCase *usecase.
 		if err != nil {
 			return nil, huma.Error404NotFound("motorcycle not found", err)
 		}
+		// Архив видят только админы
+		if motorcycle.ArchivedAt != nil && !user.IsAdmin {
+			return nil, huma.Error404NotFound("motorcycle not found")
+		}
+		if user.IsAdmin {
+			if err := favoriteCase.FillFavoriteCounts(ctx, motorcycle); err != nil {
+				return nil, huma.Error500InternalServerError("failed to count favorites", err)
+			}
+		}
 
-		return &GetMotorcycleOutput{Body: *motorcycle}, nil
+		return &GetMotorcycleOutput{ETag: motorcycleETag(motorcycle), Body: *motorcycle}, nil
 	}
 }
 
@@ -97,10 +153,11 @@ type CreateMotorcycleFromURLInput struct {
 }
 
 type CreateMotorcycleFromURLOutput struct {
-	Body domain.Motorcycle `json:"body"`
+	Body domain.ImportJob `json:"body"`
 }
 
-func CreateMotorcycleFromURLHandler(motorcycleCase *usecase.Motorcycle, userCase *usecase.User) func(ctx context.Context, input *CreateMotorcycleFromURLInput) (*CreateMotorcycleFromURLOutput, error) {
+// CreateMotorcycleFromURLHandler ставит импорт в очередь, ход выполнения - GET /admin/imports/{id}
+func CreateMotorcycleFromURLHandler(importCase *usecase.Import, userCase *usecase.User) func(ctx context.Context, input *CreateMotorcycleFromURLInput) (*CreateMotorcycleFromURLOutput, error) {
 	return func(ctx context.Context, input *CreateMotorcycleFromURLInput) (*CreateMotorcycleFromURLOutput, error) {
 		// Проверяем аутентификацию и админские права
 		user, err := auth.RequireAdminFromInput(ctx, input.XAPIToken, userCase)
@@ -108,22 +165,27 @@ func CreateMotorcycleFromURLHandler(motorcycleCase *usecase.Motorcycle, userCase
 			return nil, huma.Error403Forbidden("admin access required", err)
 		}
 
-		motorcycle, err := motorcycleCase.CreateMotorcycleFromURL(usecase.NewContext(ctx, user), input.Body.URL)
+		job, err := importCase.Enqueue(usecase.NewContext(ctx, user), input.Body.URL)
+		if errors.Is(err, usecase.ErrUnsupportedSource) {
+			return nil, huma.Error400BadRequest("unsupported source", err)
+		}
 		if err != nil {
-			return nil, huma.Error400BadRequest("failed to create motorcycle from URL", err)
+			return nil, huma.Error500InternalServerError("failed to enqueue import", err)
 		}
 
-		return &CreateMotorcycleFromURLOutput{Body: *motorcycle}, nil
+		return &CreateMotorcycleFromURLOutput{Body: *job}, nil
 	}
 }
 
 type PatchMotorcycleInput struct {
 	XAPIToken string                `header:"X-API-Token" required:"true" doc:"Telegram init data"`
 	ID        string                 `path:"id" doc:"Motorcycle ID"`
+	IfMatch   string                 `header:"If-Match" doc:"ETag from GET /motorcycles/{id}; 412 if the motorcycle was changed since"`
 	Body      domain.PatchMotorcycle `json:"body"`
 }
 
 type PatchMotorcycleOutput struct {
+	ETag string            `header:"ETag" doc:"New motorcycle version"`
 	Body domain.Motorcycle `json:"body"`
 }
 
@@ -135,12 +197,18 @@ func PatchMotorcycleHandler(motorcycleCase *usecase.Motorcycle, userCase *usecas
 			return nil, huma.Error403Forbidden("admin access required", err)
 		}
 
-		motorcycle, err := motorcycleCase.PatchMotorcycle(usecase.NewContext(ctx, user), input.ID, &input.Body)
+		patch := input.Body
+		patch.Version, err = parseIfMatch(input.IfMatch)
 		if err != nil {
-			return nil, huma.Error400BadRequest("failed to update motorcycle", err)
+			return nil, err
 		}
 
-		return &PatchMotorcycleOutput{Body: *motorcycle}, nil
+		motorcycle, err := motorcycleCase.PatchMotorcycle(usecase.NewContext(ctx, user), input.ID, &patch)
+		if err != nil {
+			return nil, statusError("failed to update motorcycle", err)
+		}
+
+		return &PatchMotorcycleOutput{ETag: motorcycleETag(motorcycle), Body: *motorcycle}, nil
 	}
 }
 
@@ -166,7 +234,7 @@ func UpdateMotorcycleStatusHandler(motorcycleCase *usecase.Motorcycle, userCase
 
 		motorcycle, err := motorcycleCase.UpdateMotorcycleStatus(usecase.NewContext(ctx, user), input.ID, input.Body.Status)
 		if err != nil {
-			return nil, huma.Error400BadRequest("failed to update motorcycle status", err)
+			return nil, statusError("failed to update motorcycle status", err)
 		}
 
 		return &UpdateMotorcycleStatusOutput{Body: *motorcycle}, nil
@@ -179,12 +247,12 @@ func SetupHuma(api huma.API, cases usecase.Cases) {
 		OperationID: "get-motorcycles",
 		Method:      http.MethodGet,
 		Path:        "/motorcycles",
-		Summary:     "Get all motorcycles (authenticated users only)",
+		Summary:     "Search motorcycles with facet counts (authenticated users only)",
 		Tags:        []string{"motorcycles"},
 		Security: []map[string][]string{
 			{"ApiKeyAuth": {}},
 		},
-	}, GetMotorcyclesHandler(cases.Motorcycle, cases.User))
+	}, GetMotorcyclesHandler(cases.Motorcycle, cases.Favorite, cases.User))
 
 	huma.Register(api, huma.Operation{
 		OperationID: "get-motorcycle",
@@ -195,25 +263,26 @@ func SetupHuma(api huma.API, cases usecase.Cases) {
 		Security: []map[string][]string{
 			{"ApiKeyAuth": {}},
 		},
-	}, GetMotorcycleHandler(cases.Motorcycle, cases.User))
+	}, GetMotorcycleHandler(cases.Motorcycle, cases.Favorite, cases.User))
 
 	// Admin endpoints
 	huma.Register(api, huma.Operation{
-		OperationID: "create-motorcycle-from-url",
-		Method:      http.MethodPost,
-		Path:        "/admin/motorcycle/from-url",
-		Summary:     "Create motorcycle from URL (admin only)",
-		Tags:        []string{"admin", "motorcycles"},
+		OperationID:   "create-motorcycle-from-url",
+		Method:        http.MethodPost,
+		Path:          "/admin/motorcycle/from-url",
+		Summary:       "Enqueue motorcycle import from URL (admin only)",
+		Tags:          []string{"admin", "motorcycles"},
+		DefaultStatus: http.StatusAccepted,
 		Security: []map[string][]string{
 			{"ApiKeyAuth": {}},
 		},
-	}, CreateMotorcycleFromURLHandler(cases.Motorcycle, cases.User))
+	}, CreateMotorcycleFromURLHandler(cases.Import, cases.User))
 
 	huma.Register(api, huma.Operation{
 		OperationID: "patch-motorcycle",
 		Method:      http.MethodPatch,
 		Path:        "/admin/motorcycle/{id}",
-		Summary:     "Update motorcycle (admin only)",
+		Summary:     "Update motorcycle, 412 if If-Match is stale (admin only)",
 		Tags:        []string{"admin", "motorcycles"},
 		Security: []map[string][]string{
 			{"ApiKeyAuth": {}},
@@ -224,11 +293,16 @@ func SetupHuma(api huma.API, cases usecase.Cases) {
 		OperationID: "update-motorcycle-status",
 		Method:      http.MethodPatch,
 		Path:        "/admin/motorcycle/{id}/status",
-		Summary:     "Update motorcycle status (admin only)",
+		Summary:     "Move motorcycle to another status, 409 if the transition is not allowed (admin only)",
 		Tags:        []string{"admin", "motorcycles"},
 		Security: []map[string][]string{
 			{"ApiKeyAuth": {}},
 		},
 	}, UpdateMotorcycleStatusHandler(cases.Motorcycle, cases.User))
+
+	setupPhotosHuma(api, cases)
+	setupArchiveHuma(api, cases)
+	setupStatusHuma(api, cases)
+	setupAuditHuma(api, cases)
 }
 
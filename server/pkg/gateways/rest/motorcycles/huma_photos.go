@@ -0,0 +1,240 @@
+package motorcycles
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/gateways/rest/auth"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+	"github.com/shampsdev/go-telegram-template/pkg/usecase"
+)
+
+const (
+	// maxPhotoBytes - предел размера одного загружаемого файла
+	maxPhotoBytes = 20 << 20
+	// maxUploadPhotos - сколько файлов можно загрузить одним запросом
+	maxUploadPhotos = 20
+)
+
+// photoError переводит ошибки работы с фотографиями в HTTP-статусы
+func photoError(msg string, err error) error {
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		return huma.Error404NotFound("motorcycle or photo not found", err)
+	case errors.Is(err, usecase.ErrInvalidPhoto), errors.Is(err, usecase.ErrInvalidPhotoOrder):
+		return huma.Error400BadRequest(msg, err)
+	default:
+		return huma.Error500InternalServerError(msg, err)
+	}
+}
+
+// readPhotos читает загруженные файлы, проверяя размер каждого
+func readPhotos(files []huma.FormFile) ([][]byte, error) {
+	result := make([][]byte, 0, len(files))
+	for _, file := range files {
+		data, err := io.ReadAll(io.LimitReader(file, maxPhotoBytes+1))
+		file.Close()
+		if err != nil {
+			return nil, huma.Error400BadRequest("failed to read photo", err)
+		}
+		if len(data) > maxPhotoBytes {
+			return nil, huma.NewError(http.StatusRequestEntityTooLarge, fmt.Sprintf("photo %q is larger than %d bytes", file.Filename, maxPhotoBytes))
+		}
+		result = append(result, data)
+	}
+	return result, nil
+}
+
+type PhotosOutput struct {
+	Body domain.Motorcycle `json:"body"`
+}
+
+type UploadPhotosInput struct {
+	XAPIToken string `header:"X-API-Token" required:"true" doc:"Telegram init data"`
+	ID        string `path:"id" doc:"Motorcycle ID"`
+	RawBody   huma.MultipartFormFiles[struct {
+		Photos []huma.FormFile `form:"photos" contentType:"image/*" required:"true" doc:"Photos to append to the gallery"`
+	}]
+}
+
+func UploadPhotosHandler(motorcycleCase *usecase.Motorcycle, userCase *usecase.User) func(ctx context.Context, input *UploadPhotosInput) (*PhotosOutput, error) {
+	return func(ctx context.Context, input *UploadPhotosInput) (*PhotosOutput, error) {
+		user, err := auth.RequireAdminFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error403Forbidden("admin access required", err)
+		}
+
+		files := input.RawBody.Data().Photos
+		if len(files) > maxUploadPhotos {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("at most %d photos per request", maxUploadPhotos))
+		}
+		data, err := readPhotos(files)
+		if err != nil {
+			return nil, err
+		}
+
+		motorcycle, err := motorcycleCase.UploadPhotos(usecase.NewContext(ctx, user), input.ID, data)
+		if err != nil {
+			return nil, photoError("failed to upload photos", err)
+		}
+		return &PhotosOutput{Body: *motorcycle}, nil
+	}
+}
+
+type ReplacePhotoInput struct {
+	XAPIToken string `header:"X-API-Token" required:"true" doc:"Telegram init data"`
+	ID        string `path:"id" doc:"Motorcycle ID"`
+	PhotoID   string `path:"photoId" doc:"Photo ID"`
+	RawBody   huma.MultipartFormFiles[struct {
+		Photo huma.FormFile `form:"photo" contentType:"image/*" required:"true" doc:"New photo file"`
+	}]
+}
+
+func ReplacePhotoHandler(motorcycleCase *usecase.Motorcycle, userCase *usecase.User) func(ctx context.Context, input *ReplacePhotoInput) (*PhotosOutput, error) {
+	return func(ctx context.Context, input *ReplacePhotoInput) (*PhotosOutput, error) {
+		user, err := auth.RequireAdminFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error403Forbidden("admin access required", err)
+		}
+
+		data, err := readPhotos([]huma.FormFile{input.RawBody.Data().Photo})
+		if err != nil {
+			return nil, err
+		}
+
+		motorcycle, err := motorcycleCase.ReplacePhoto(usecase.NewContext(ctx, user), input.ID, input.PhotoID, data[0])
+		if err != nil {
+			return nil, photoError("failed to replace photo", err)
+		}
+		return &PhotosOutput{Body: *motorcycle}, nil
+	}
+}
+
+type DeletePhotoInput struct {
+	XAPIToken string `header:"X-API-Token" required:"true" doc:"Telegram init data"`
+	ID        string `path:"id" doc:"Motorcycle ID"`
+	PhotoID   string `path:"photoId" doc:"Photo ID"`
+}
+
+func DeletePhotoHandler(motorcycleCase *usecase.Motorcycle, userCase *usecase.User) func(ctx context.Context, input *DeletePhotoInput) (*PhotosOutput, error) {
+	return func(ctx context.Context, input *DeletePhotoInput) (*PhotosOutput, error) {
+		user, err := auth.RequireAdminFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error403Forbidden("admin access required", err)
+		}
+
+		motorcycle, err := motorcycleCase.DeletePhoto(usecase.NewContext(ctx, user), input.ID, input.PhotoID)
+		if err != nil {
+			return nil, photoError("failed to delete photo", err)
+		}
+		return &PhotosOutput{Body: *motorcycle}, nil
+	}
+}
+
+type ReorderPhotosInput struct {
+	XAPIToken string `header:"X-API-Token" required:"true" doc:"Telegram init data"`
+	ID        string `path:"id" doc:"Motorcycle ID"`
+	Body      struct {
+		PhotoIDs []string `json:"photoIds" doc:"All photo IDs in the new order, the first one becomes the cover"`
+	} `json:"body"`
+}
+
+func ReorderPhotosHandler(motorcycleCase *usecase.Motorcycle, userCase *usecase.User) func(ctx context.Context, input *ReorderPhotosInput) (*PhotosOutput, error) {
+	return func(ctx context.Context, input *ReorderPhotosInput) (*PhotosOutput, error) {
+		user, err := auth.RequireAdminFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error403Forbidden("admin access required", err)
+		}
+
+		motorcycle, err := motorcycleCase.ReorderPhotos(usecase.NewContext(ctx, user), input.ID, input.Body.PhotoIDs)
+		if err != nil {
+			return nil, photoError("failed to reorder photos", err)
+		}
+		return &PhotosOutput{Body: *motorcycle}, nil
+	}
+}
+
+type SetCoverPhotoInput struct {
+	XAPIToken string `header:"X-API-Token" required:"true" doc:"Telegram init data"`
+	ID        string `path:"id" doc:"Motorcycle ID"`
+	Body      struct {
+		PhotoID string `json:"photoId" doc:"Photo to show first"`
+	} `json:"body"`
+}
+
+func SetCoverPhotoHandler(motorcycleCase *usecase.Motorcycle, userCase *usecase.User) func(ctx context.Context, input *SetCoverPhotoInput) (*PhotosOutput, error) {
+	return func(ctx context.Context, input *SetCoverPhotoInput) (*PhotosOutput, error) {
+		user, err := auth.RequireAdminFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error403Forbidden("admin access required", err)
+		}
+
+		motorcycle, err := motorcycleCase.SetCoverPhoto(usecase.NewContext(ctx, user), input.ID, input.Body.PhotoID)
+		if err != nil {
+			return nil, photoError("failed to set cover photo", err)
+		}
+		return &PhotosOutput{Body: *motorcycle}, nil
+	}
+}
+
+func setupPhotosHuma(api huma.API, cases usecase.Cases) {
+	huma.Register(api, huma.Operation{
+		OperationID: "upload-motorcycle-photos",
+		Method:      http.MethodPost,
+		Path:        "/admin/motorcycle/{id}/photos",
+		Summary:     "Upload photos to the end of the gallery (admin only)",
+		Tags:        []string{"admin", "motorcycles"},
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, UploadPhotosHandler(cases.Motorcycle, cases.User))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "reorder-motorcycle-photos",
+		Method:      http.MethodPut,
+		Path:        "/admin/motorcycle/{id}/photos/order",
+		Summary:     "Reorder gallery photos (admin only)",
+		Tags:        []string{"admin", "motorcycles"},
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, ReorderPhotosHandler(cases.Motorcycle, cases.User))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "set-motorcycle-cover-photo",
+		Method:      http.MethodPut,
+		Path:        "/admin/motorcycle/{id}/photos/cover",
+		Summary:     "Set the cover photo (admin only)",
+		Tags:        []string{"admin", "motorcycles"},
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, SetCoverPhotoHandler(cases.Motorcycle, cases.User))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "replace-motorcycle-photo",
+		Method:      http.MethodPut,
+		Path:        "/admin/motorcycle/{id}/photos/{photoId}",
+		Summary:     "Replace photo file keeping its position (admin only)",
+		Tags:        []string{"admin", "motorcycles"},
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, ReplacePhotoHandler(cases.Motorcycle, cases.User))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "delete-motorcycle-photo",
+		Method:      http.MethodDelete,
+		Path:        "/admin/motorcycle/{id}/photos/{photoId}",
+		Summary:     "Delete photo and its files (admin only)",
+		Tags:        []string{"admin", "motorcycles"},
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, DeletePhotoHandler(cases.Motorcycle, cases.User))
+}
@@ -0,0 +1,147 @@
+package motorcycles
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/gateways/rest/auth"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+	"github.com/shampsdev/go-telegram-template/pkg/usecase"
+)
+
+// statusError переводит ошибки смены статуса в HTTP-статусы. Незаполненный черновик -
+// 422, каждое недостающее поле - отдельная ошибка с location вида body.price.
+// Устаревший If-Match - 412
+func statusError(msg string, err error) error {
+	var transitionErr *usecase.StatusTransitionError
+	var publishErr *usecase.PublishError
+	switch {
+	case errors.As(err, &publishErr):
+		details := make([]error, 0, len(publishErr.Problems))
+		for _, problem := range publishErr.Problems {
+			details = append(details, &huma.ErrorDetail{Message: problem.Message, Location: "body." + problem.Field})
+		}
+		return huma.Error422UnprocessableEntity("motorcycle is not ready to publish", details...)
+	case errors.Is(err, repo.ErrNotFound):
+		return huma.Error404NotFound("motorcycle not found", err)
+	case errors.Is(err, usecase.ErrVersionMismatch):
+		return huma.Error412PreconditionFailed("motorcycle was changed by someone else, reload it and retry", err)
+	case errors.Is(err, usecase.ErrInvalidStatus), errors.Is(err, usecase.ErrInvalidData):
+		return huma.Error400BadRequest(msg, err)
+	case errors.As(err, &transitionErr), errors.Is(err, repo.ErrConflict):
+		return huma.Error409Conflict(msg, err)
+	default:
+		return huma.Error400BadRequest(msg, err)
+	}
+}
+
+type GetStatusHistoryInput struct {
+	XAPIToken string `header:"X-API-Token" required:"true" doc:"Telegram init data"`
+	ID        string `path:"id" doc:"Motorcycle ID"`
+}
+
+type GetStatusHistoryOutput struct {
+	Body []*domain.MotorcycleStatusChange `json:"body"`
+}
+
+func GetStatusHistoryHandler(motorcycleCase *usecase.Motorcycle, userCase *usecase.User) func(ctx context.Context, input *GetStatusHistoryInput) (*GetStatusHistoryOutput, error) {
+	return func(ctx context.Context, input *GetStatusHistoryInput) (*GetStatusHistoryOutput, error) {
+		user, err := auth.RequireAdminFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error403Forbidden("admin access required", err)
+		}
+
+		history, err := motorcycleCase.StatusHistory(usecase.NewContext(ctx, user), input.ID)
+		if errors.Is(err, repo.ErrNotFound) {
+			return nil, huma.Error404NotFound("motorcycle not found", err)
+		}
+		if err != nil {
+			return nil, huma.Error500InternalServerError("failed to get status history", err)
+		}
+		return &GetStatusHistoryOutput{Body: history}, nil
+	}
+}
+
+type PublishMotorcycleInput struct {
+	XAPIToken string `header:"X-API-Token" required:"true" doc:"Telegram init data"`
+	ID        string `path:"id" doc:"Motorcycle ID"`
+}
+
+type PublishMotorcycleOutput struct {
+	Body domain.Motorcycle `json:"body"`
+}
+
+func PublishMotorcycleHandler(motorcycleCase *usecase.Motorcycle, userCase *usecase.User) func(ctx context.Context, input *PublishMotorcycleInput) (*PublishMotorcycleOutput, error) {
+	return func(ctx context.Context, input *PublishMotorcycleInput) (*PublishMotorcycleOutput, error) {
+		user, err := auth.RequireAdminFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error403Forbidden("admin access required", err)
+		}
+
+		motorcycle, err := motorcycleCase.PublishMotorcycle(usecase.NewContext(ctx, user), input.ID)
+		if err != nil {
+			return nil, statusError("failed to publish motorcycle", err)
+		}
+		return &PublishMotorcycleOutput{Body: *motorcycle}, nil
+	}
+}
+
+type CheckPublishOutput struct {
+	Body domain.PublishCheck `json:"body"`
+}
+
+func CheckPublishHandler(motorcycleCase *usecase.Motorcycle, userCase *usecase.User) func(ctx context.Context, input *PublishMotorcycleInput) (*CheckPublishOutput, error) {
+	return func(ctx context.Context, input *PublishMotorcycleInput) (*CheckPublishOutput, error) {
+		user, err := auth.RequireAdminFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error403Forbidden("admin access required", err)
+		}
+
+		check, err := motorcycleCase.CheckPublish(usecase.NewContext(ctx, user), input.ID)
+		if errors.Is(err, repo.ErrNotFound) {
+			return nil, huma.Error404NotFound("motorcycle not found", err)
+		}
+		if err != nil {
+			return nil, huma.Error500InternalServerError("failed to check motorcycle", err)
+		}
+		return &CheckPublishOutput{Body: *check}, nil
+	}
+}
+
+func setupStatusHuma(api huma.API, cases usecase.Cases) {
+	huma.Register(api, huma.Operation{
+		OperationID: "check-motorcycle-publish",
+		Method:      http.MethodGet,
+		Path:        "/admin/motorcycle/{id}/publish",
+		Summary:     "List what the draft is missing to be published (admin only)",
+		Tags:        []string{"admin", "motorcycles"},
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, CheckPublishHandler(cases.Motorcycle, cases.User))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "publish-motorcycle",
+		Method:      http.MethodPost,
+		Path:        "/admin/motorcycle/{id}/publish",
+		Summary:     "Publish draft to the catalog, 422 lists missing fields (admin only)",
+		Tags:        []string{"admin", "motorcycles"},
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, PublishMotorcycleHandler(cases.Motorcycle, cases.User))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-motorcycle-status-history",
+		Method:      http.MethodGet,
+		Path:        "/admin/motorcycle/{id}/status/history",
+		Summary:     "Get motorcycle status transitions, oldest first (admin only)",
+		Tags:        []string{"admin", "motorcycles"},
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, GetStatusHistoryHandler(cases.Motorcycle, cases.User))
+}
@@ -0,0 +1,171 @@
+package reservations
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/gateways/rest/auth"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+	"github.com/shampsdev/go-telegram-template/pkg/usecase"
+)
+
+// reservationError переводит ошибки заявок в HTTP-статусы
+func reservationError(msg string, err error) error {
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		return huma.Error404NotFound("motorcycle or reservation not found", err)
+	case errors.Is(err, repo.ErrAlreadyExists):
+		return huma.Error409Conflict("reservation request is already pending", err)
+	case errors.Is(err, usecase.ErrNotAvailable), errors.Is(err, usecase.ErrReservationDecided):
+		return huma.Error409Conflict(msg, err)
+	default:
+		return huma.Error500InternalServerError(msg, err)
+	}
+}
+
+type ReservationOutput struct {
+	Body domain.Reservation `json:"body"`
+}
+
+type RequestReservationInput struct {
+	XAPIToken string `header:"X-API-Token" required:"true" doc:"Telegram init data"`
+	ID        string `path:"id" doc:"Motorcycle ID"`
+	Body      struct {
+		Comment string `json:"comment,omitempty" maxLength:"500" doc:"Message for the manager"`
+	} `json:"body"`
+}
+
+func RequestReservationHandler(reservationCase *usecase.Reservation, userCase *usecase.User) func(ctx context.Context, input *RequestReservationInput) (*ReservationOutput, error) {
+	return func(ctx context.Context, input *RequestReservationInput) (*ReservationOutput, error) {
+		user, err := auth.AuthenticateFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error401Unauthorized("failed to authenticate user", err)
+		}
+
+		reservation, err := reservationCase.RequestReservation(usecase.NewContext(ctx, user), input.ID, input.Body.Comment)
+		if err != nil {
+			return nil, reservationError("failed to request reservation", err)
+		}
+		return &ReservationOutput{Body: *reservation}, nil
+	}
+}
+
+type ListReservationsInput struct {
+	XAPIToken    string `header:"X-API-Token" required:"true" doc:"Telegram init data"`
+	Status       string `query:"status" enum:"pending,approved,declined,expired,closed" doc:"Filter by reservation status"`
+	MotorcycleID string `query:"motorcycleId" doc:"Filter by motorcycle"`
+}
+
+type ListReservationsOutput struct {
+	Body []*domain.Reservation `json:"body"`
+}
+
+func ListReservationsHandler(reservationCase *usecase.Reservation, userCase *usecase.User) func(ctx context.Context, input *ListReservationsInput) (*ListReservationsOutput, error) {
+	return func(ctx context.Context, input *ListReservationsInput) (*ListReservationsOutput, error) {
+		_, err := auth.RequireAdminFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error403Forbidden("admin access required", err)
+		}
+
+		filter := &domain.FilterReservation{}
+		if input.Status != "" {
+			status := domain.ReservationStatus(input.Status)
+			filter.Status = &status
+		}
+		if input.MotorcycleID != "" {
+			filter.MotorcycleID = &input.MotorcycleID
+		}
+
+		reservations, err := reservationCase.ListReservations(ctx, filter)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("failed to list reservations", err)
+		}
+		return &ListReservationsOutput{Body: reservations}, nil
+	}
+}
+
+type DecideReservationInput struct {
+	XAPIToken string `header:"X-API-Token" required:"true" doc:"Telegram init data"`
+	ID        string `path:"id" doc:"Reservation ID"`
+}
+
+func ApproveReservationHandler(reservationCase *usecase.Reservation, userCase *usecase.User) func(ctx context.Context, input *DecideReservationInput) (*ReservationOutput, error) {
+	return func(ctx context.Context, input *DecideReservationInput) (*ReservationOutput, error) {
+		user, err := auth.RequireAdminFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error403Forbidden("admin access required", err)
+		}
+
+		reservation, err := reservationCase.Approve(usecase.NewContext(ctx, user), input.ID)
+		if err != nil {
+			return nil, reservationError("failed to approve reservation", err)
+		}
+		return &ReservationOutput{Body: *reservation}, nil
+	}
+}
+
+func DeclineReservationHandler(reservationCase *usecase.Reservation, userCase *usecase.User) func(ctx context.Context, input *DecideReservationInput) (*ReservationOutput, error) {
+	return func(ctx context.Context, input *DecideReservationInput) (*ReservationOutput, error) {
+		user, err := auth.RequireAdminFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error403Forbidden("admin access required", err)
+		}
+
+		reservation, err := reservationCase.Decline(usecase.NewContext(ctx, user), input.ID)
+		if err != nil {
+			return nil, reservationError("failed to decline reservation", err)
+		}
+		return &ReservationOutput{Body: *reservation}, nil
+	}
+}
+
+func SetupHuma(api huma.API, cases usecase.Cases) {
+	huma.Register(api, huma.Operation{
+		OperationID:   "request-reservation",
+		Method:        http.MethodPost,
+		Path:          "/motorcycles/{id}/reservations",
+		Summary:       "Request a reservation of an available motorcycle",
+		Tags:          []string{"reservations"},
+		DefaultStatus: http.StatusCreated,
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, RequestReservationHandler(cases.Reservation, cases.User))
+
+	// Решение по заявкам (только для админов)
+	huma.Register(api, huma.Operation{
+		OperationID: "list-reservations",
+		Method:      http.MethodGet,
+		Path:        "/admin/reservations",
+		Summary:     "List reservation requests, newest first (admin only)",
+		Tags:        []string{"admin", "reservations"},
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, ListReservationsHandler(cases.Reservation, cases.User))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "approve-reservation",
+		Method:      http.MethodPost,
+		Path:        "/admin/reservations/{id}/approve",
+		Summary:     "Approve reservation and mark motorcycle reserved (admin only)",
+		Tags:        []string{"admin", "reservations"},
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, ApproveReservationHandler(cases.Reservation, cases.User))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "decline-reservation",
+		Method:      http.MethodPost,
+		Path:        "/admin/reservations/{id}/decline",
+		Summary:     "Decline reservation request (admin only)",
+		Tags:        []string{"admin", "reservations"},
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, DeclineReservationHandler(cases.Reservation, cases.User))
+}
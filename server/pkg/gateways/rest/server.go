@@ -28,6 +28,9 @@ func NewServer(ctx context.Context, cfg *config.Config, useCases usecase.Cases)
 	auth.BotToken = cfg.TG.BotToken
 	
 	api, router := NewHumaAPI(ctx, useCases)
+	if cfg.Storage.Backend == config.StorageBackendDisk {
+		router.Handle(imagesRoute+"*", imagesHandler(cfg.Storage.ImagesPath))
+	}
 
 	s := &Server{
 		API:    api,
@@ -0,0 +1,27 @@
+package rest
+
+import (
+	"net/http"
+	"strings"
+)
+
+// imagesRoute - маршрут, по которому раздаются изображения бэкенда disk
+const imagesRoute = "/images/"
+
+// imagesMaxAge - ключи изображений уникальны, поэтому браузеру можно долго
+// не перепроверять их; после срока действует Last-Modified от http.FileServer
+const imagesMaxAge = "public, max-age=604800"
+
+// imagesHandler раздает файлы из dir без листинга папок
+func imagesHandler(dir string) http.Handler {
+	files := http.StripPrefix(imagesRoute, http.FileServer(http.Dir(dir)))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Cache-Control", imagesMaxAge)
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		files.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,110 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/gateways/rest/auth"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+	"github.com/shampsdev/go-telegram-template/pkg/usecase"
+)
+
+type ListFavoritesInput struct {
+	XAPIToken string `header:"X-API-Token" required:"true" doc:"Telegram init data"`
+}
+
+type ListFavoritesOutput struct {
+	Body []*domain.Favorite `json:"body"`
+}
+
+func ListFavoritesHandler(favoriteCase *usecase.Favorite, userCase *usecase.User) func(ctx context.Context, input *ListFavoritesInput) (*ListFavoritesOutput, error) {
+	return func(ctx context.Context, input *ListFavoritesInput) (*ListFavoritesOutput, error) {
+		user, err := auth.AuthenticateFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error401Unauthorized("failed to authenticate user", err)
+		}
+
+		favorites, err := favoriteCase.ListFavorites(usecase.NewContext(ctx, user))
+		if err != nil {
+			return nil, huma.Error500InternalServerError("failed to get favorites", err)
+		}
+		return &ListFavoritesOutput{Body: favorites}, nil
+	}
+}
+
+type FavoriteInput struct {
+	XAPIToken    string `header:"X-API-Token" required:"true" doc:"Telegram init data"`
+	MotorcycleID string `path:"motorcycleId" doc:"Motorcycle ID"`
+}
+
+func AddFavoriteHandler(favoriteCase *usecase.Favorite, userCase *usecase.User) func(ctx context.Context, input *FavoriteInput) (*struct{}, error) {
+	return func(ctx context.Context, input *FavoriteInput) (*struct{}, error) {
+		user, err := auth.AuthenticateFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error401Unauthorized("failed to authenticate user", err)
+		}
+
+		err = favoriteCase.AddFavorite(usecase.NewContext(ctx, user), input.MotorcycleID)
+		if errors.Is(err, repo.ErrNotFound) {
+			return nil, huma.Error404NotFound("motorcycle not found", err)
+		}
+		if err != nil {
+			return nil, huma.Error500InternalServerError("failed to add favorite", err)
+		}
+		return nil, nil
+	}
+}
+
+func RemoveFavoriteHandler(favoriteCase *usecase.Favorite, userCase *usecase.User) func(ctx context.Context, input *FavoriteInput) (*struct{}, error) {
+	return func(ctx context.Context, input *FavoriteInput) (*struct{}, error) {
+		user, err := auth.AuthenticateFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error401Unauthorized("failed to authenticate user", err)
+		}
+
+		if err := favoriteCase.RemoveFavorite(usecase.NewContext(ctx, user), input.MotorcycleID); err != nil {
+			return nil, huma.Error500InternalServerError("failed to remove favorite", err)
+		}
+		return nil, nil
+	}
+}
+
+func setupFavoritesHuma(api huma.API, cases usecase.Cases) {
+	huma.Register(api, huma.Operation{
+		OperationID: "list-favorites",
+		Method:      http.MethodGet,
+		Path:        "/users/me/favorites",
+		Summary:     "List my favorite motorcycles, recently added first",
+		Tags:        []string{"users", "favorites"},
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, ListFavoritesHandler(cases.Favorite, cases.User))
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "add-favorite",
+		Method:        http.MethodPut,
+		Path:          "/users/me/favorites/{motorcycleId}",
+		Summary:       "Add motorcycle to my favorites, repeating is a no-op",
+		Tags:          []string{"users", "favorites"},
+		DefaultStatus: http.StatusNoContent,
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, AddFavoriteHandler(cases.Favorite, cases.User))
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "remove-favorite",
+		Method:        http.MethodDelete,
+		Path:          "/users/me/favorites/{motorcycleId}",
+		Summary:       "Remove motorcycle from my favorites",
+		Tags:          []string{"users", "favorites"},
+		DefaultStatus: http.StatusNoContent,
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, RemoveFavoriteHandler(cases.Favorite, cases.User))
+}
@@ -0,0 +1,165 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/gateways/rest/auth"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+	"github.com/shampsdev/go-telegram-template/pkg/usecase"
+)
+
+type ListSavedSearchesInput struct {
+	XAPIToken string `header:"X-API-Token" required:"true" doc:"Telegram init data"`
+}
+
+type ListSavedSearchesOutput struct {
+	Body []*domain.SavedSearch `json:"body"`
+}
+
+func ListSavedSearchesHandler(savedSearchCase *usecase.SavedSearch, userCase *usecase.User) func(ctx context.Context, input *ListSavedSearchesInput) (*ListSavedSearchesOutput, error) {
+	return func(ctx context.Context, input *ListSavedSearchesInput) (*ListSavedSearchesOutput, error) {
+		user, err := auth.AuthenticateFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error401Unauthorized("failed to authenticate user", err)
+		}
+
+		searches, err := savedSearchCase.ListSavedSearches(usecase.NewContext(ctx, user))
+		if err != nil {
+			return nil, huma.Error500InternalServerError("failed to get saved searches", err)
+		}
+		return &ListSavedSearchesOutput{Body: searches}, nil
+	}
+}
+
+type SavedSearchBody struct {
+	Name   string                   `json:"name" minLength:"1" maxLength:"100" doc:"Search name, unique among my saved searches"`
+	Filter domain.SavedSearchFilter `json:"filter" doc:"Catalog filter, empty fields match any motorcycle"`
+}
+
+type CreateSavedSearchInput struct {
+	XAPIToken string          `header:"X-API-Token" required:"true" doc:"Telegram init data"`
+	Body      SavedSearchBody `json:"body"`
+}
+
+type SavedSearchOutput struct {
+	Body domain.SavedSearch `json:"body"`
+}
+
+func CreateSavedSearchHandler(savedSearchCase *usecase.SavedSearch, userCase *usecase.User) func(ctx context.Context, input *CreateSavedSearchInput) (*SavedSearchOutput, error) {
+	return func(ctx context.Context, input *CreateSavedSearchInput) (*SavedSearchOutput, error) {
+		user, err := auth.AuthenticateFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error401Unauthorized("failed to authenticate user", err)
+		}
+
+		search, err := savedSearchCase.CreateSavedSearch(usecase.NewContext(ctx, user), input.Body.Name, input.Body.Filter)
+		if err != nil {
+			return nil, savedSearchError(err, "failed to create saved search")
+		}
+		return &SavedSearchOutput{Body: *search}, nil
+	}
+}
+
+type UpdateSavedSearchInput struct {
+	XAPIToken string          `header:"X-API-Token" required:"true" doc:"Telegram init data"`
+	ID        string          `path:"id" doc:"Saved search ID"`
+	Body      SavedSearchBody `json:"body"`
+}
+
+func UpdateSavedSearchHandler(savedSearchCase *usecase.SavedSearch, userCase *usecase.User) func(ctx context.Context, input *UpdateSavedSearchInput) (*SavedSearchOutput, error) {
+	return func(ctx context.Context, input *UpdateSavedSearchInput) (*SavedSearchOutput, error) {
+		user, err := auth.AuthenticateFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error401Unauthorized("failed to authenticate user", err)
+		}
+
+		search, err := savedSearchCase.UpdateSavedSearch(usecase.NewContext(ctx, user), input.ID, input.Body.Name, input.Body.Filter)
+		if err != nil {
+			return nil, savedSearchError(err, "failed to update saved search")
+		}
+		return &SavedSearchOutput{Body: *search}, nil
+	}
+}
+
+type DeleteSavedSearchInput struct {
+	XAPIToken string `header:"X-API-Token" required:"true" doc:"Telegram init data"`
+	ID        string `path:"id" doc:"Saved search ID"`
+}
+
+func DeleteSavedSearchHandler(savedSearchCase *usecase.SavedSearch, userCase *usecase.User) func(ctx context.Context, input *DeleteSavedSearchInput) (*struct{}, error) {
+	return func(ctx context.Context, input *DeleteSavedSearchInput) (*struct{}, error) {
+		user, err := auth.AuthenticateFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error401Unauthorized("failed to authenticate user", err)
+		}
+
+		if err := savedSearchCase.DeleteSavedSearch(usecase.NewContext(ctx, user), input.ID); err != nil {
+			return nil, savedSearchError(err, "failed to delete saved search")
+		}
+		return nil, nil
+	}
+}
+
+func savedSearchError(err error, msg string) error {
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		return huma.Error404NotFound("saved search not found", err)
+	case errors.Is(err, repo.ErrAlreadyExists):
+		return huma.Error409Conflict("saved search with this name already exists", err)
+	case errors.Is(err, usecase.ErrInvalidSavedSearch), errors.Is(err, usecase.ErrInvalidFilter):
+		return huma.Error400BadRequest(err.Error(), err)
+	}
+	return huma.Error500InternalServerError(msg, err)
+}
+
+func setupSavedSearchesHuma(api huma.API, cases usecase.Cases) {
+	huma.Register(api, huma.Operation{
+		OperationID: "list-saved-searches",
+		Method:      http.MethodGet,
+		Path:        "/users/me/saved-searches",
+		Summary:     "List my saved searches",
+		Tags:        []string{"users", "saved-searches"},
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, ListSavedSearchesHandler(cases.SavedSearch, cases.User))
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "create-saved-search",
+		Method:        http.MethodPost,
+		Path:          "/users/me/saved-searches",
+		Summary:       "Save a catalog filter, the bot notifies about new matching motorcycles",
+		Tags:          []string{"users", "saved-searches"},
+		DefaultStatus: http.StatusCreated,
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, CreateSavedSearchHandler(cases.SavedSearch, cases.User))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "update-saved-search",
+		Method:      http.MethodPut,
+		Path:        "/users/me/saved-searches/{id}",
+		Summary:     "Replace name and filter of my saved search",
+		Tags:        []string{"users", "saved-searches"},
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, UpdateSavedSearchHandler(cases.SavedSearch, cases.User))
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "delete-saved-search",
+		Method:        http.MethodDelete,
+		Path:          "/users/me/saved-searches/{id}",
+		Summary:       "Delete my saved search",
+		Tags:          []string{"users", "saved-searches"},
+		DefaultStatus: http.StatusNoContent,
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, DeleteSavedSearchHandler(cases.SavedSearch, cases.User))
+}
@@ -0,0 +1,116 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/gateways/rest/auth"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+	"github.com/shampsdev/go-telegram-template/pkg/usecase"
+)
+
+type SubscriptionInput struct {
+	XAPIToken string `header:"X-API-Token" required:"true" doc:"Telegram init data"`
+}
+
+type SubscriptionOutput struct {
+	Body domain.Subscription `json:"body"`
+}
+
+func GetSubscriptionHandler(subscriptionCase *usecase.Subscription, userCase *usecase.User) func(ctx context.Context, input *SubscriptionInput) (*SubscriptionOutput, error) {
+	return func(ctx context.Context, input *SubscriptionInput) (*SubscriptionOutput, error) {
+		user, err := auth.AuthenticateFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error401Unauthorized("failed to authenticate user", err)
+		}
+
+		subscription, err := subscriptionCase.GetSubscription(usecase.NewContext(ctx, user))
+		if errors.Is(err, repo.ErrNotFound) {
+			return nil, huma.Error404NotFound("not subscribed", err)
+		}
+		if err != nil {
+			return nil, huma.Error500InternalServerError("failed to get subscription", err)
+		}
+		return &SubscriptionOutput{Body: *subscription}, nil
+	}
+}
+
+type SetSubscriptionInput struct {
+	XAPIToken string `header:"X-API-Token" required:"true" doc:"Telegram init data"`
+	// Body - фильтр подписки, пустые поля не ограничивают выборку. brand и maxPrice
+	// остались на прежнем месте, остальные поля сохраненного поиска необязательны
+	Body domain.SavedSearchFilter `json:"body"`
+}
+
+func SetSubscriptionHandler(subscriptionCase *usecase.Subscription, userCase *usecase.User) func(ctx context.Context, input *SetSubscriptionInput) (*SubscriptionOutput, error) {
+	return func(ctx context.Context, input *SetSubscriptionInput) (*SubscriptionOutput, error) {
+		user, err := auth.AuthenticateFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error401Unauthorized("failed to authenticate user", err)
+		}
+
+		subscription, err := subscriptionCase.Subscribe(usecase.NewContext(ctx, user), &domain.SetSubscription{
+			SavedSearchFilter: input.Body,
+		})
+		if errors.Is(err, usecase.ErrInvalidFilter) {
+			return nil, huma.Error400BadRequest(err.Error(), err)
+		}
+		if err != nil {
+			return nil, huma.Error500InternalServerError("failed to subscribe", err)
+		}
+		return &SubscriptionOutput{Body: *subscription}, nil
+	}
+}
+
+func DeleteSubscriptionHandler(subscriptionCase *usecase.Subscription, userCase *usecase.User) func(ctx context.Context, input *SubscriptionInput) (*struct{}, error) {
+	return func(ctx context.Context, input *SubscriptionInput) (*struct{}, error) {
+		user, err := auth.AuthenticateFromInput(ctx, input.XAPIToken, userCase)
+		if err != nil {
+			return nil, huma.Error401Unauthorized("failed to authenticate user", err)
+		}
+
+		if err := subscriptionCase.Unsubscribe(usecase.NewContext(ctx, user)); err != nil {
+			return nil, huma.Error500InternalServerError("failed to unsubscribe", err)
+		}
+		return nil, nil
+	}
+}
+
+func setupSubscriptionHuma(api huma.API, cases usecase.Cases) {
+	huma.Register(api, huma.Operation{
+		OperationID: "get-subscription",
+		Method:      http.MethodGet,
+		Path:        "/users/me/subscription",
+		Summary:     "Get my notification subscription, 404 if not subscribed",
+		Tags:        []string{"users", "subscriptions"},
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, GetSubscriptionHandler(cases.Subscription, cases.User))
+
+	huma.Register(api, huma.Operation{
+		OperationID: "set-subscription",
+		Method:      http.MethodPut,
+		Path:        "/users/me/subscription",
+		Summary:     "Subscribe to new motorcycles and price drops or replace the filter",
+		Tags:        []string{"users", "subscriptions"},
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, SetSubscriptionHandler(cases.Subscription, cases.User))
+
+	huma.Register(api, huma.Operation{
+		OperationID:   "delete-subscription",
+		Method:        http.MethodDelete,
+		Path:          "/users/me/subscription",
+		Summary:       "Unsubscribe from notifications",
+		Tags:          []string{"users", "subscriptions"},
+		DefaultStatus: http.StatusNoContent,
+		Security: []map[string][]string{
+			{"ApiKeyAuth": {}},
+		},
+	}, DeleteSubscriptionHandler(cases.Subscription, cases.User))
+}
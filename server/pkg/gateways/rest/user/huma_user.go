@@ -84,4 +84,8 @@ func SetupHuma(api huma.API, cases usecase.Cases) {
 			{"ApiKeyAuth": {}},
 		},
 	}, GetMeHandler(cases.User))
+
+	setupFavoritesHuma(api, cases)
+	setupSubscriptionHuma(api, cases)
+	setupSavedSearchesHuma(api, cases)
 }
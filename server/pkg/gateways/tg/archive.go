@@ -0,0 +1,97 @@
+package tg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+	"github.com/shampsdev/go-telegram-template/pkg/usecase"
+	"github.com/shampsdev/go-telegram-template/pkg/utils/slogx"
+)
+
+const archiveHelpText = `🗄 Архив:
+/archive <id> - скрыть мотоцикл из каталога
+/restore <id> - вернуть мотоцикл из архива
+/delete <id> - удалить архивный мотоцикл вместе с фотографиями`
+
+func (b *Bot) registerArchiveHandlers() {
+	b.RegisterHandler(bot.HandlerTypeMessageText, "archive", bot.MatchTypeCommandStartOnly, b.handleCommandArchive)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "restore", bot.MatchTypeCommandStartOnly, b.handleCommandRestore)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "delete", bot.MatchTypeCommandStartOnly, b.handleCommandDelete)
+}
+
+// archiveCommand проверяет права и возвращает ID мотоцикла из аргумента команды
+func (b *Bot) archiveCommand(ctx context.Context, update *models.Update) (usecase.Context, string, bool) {
+	chatID := update.Message.Chat.ID
+	user, err := b.getOrCreateUser(ctx, update.Message.From)
+	if err != nil {
+		slogx.FromCtxWithErr(ctx, err).Error("error getting or creating user")
+		b.sendError(ctx, chatID, "Произошла ошибка при получении информации о вас.")
+		return usecase.Context{}, "", false
+	}
+	if !user.IsAdmin {
+		b.sendMessage(ctx, chatID, "🚫 У вас нет прав для управления каталогом.")
+		return usecase.Context{}, "", false
+	}
+
+	args := strings.Fields(update.Message.Text)[1:]
+	if len(args) != 1 {
+		b.sendMessage(ctx, chatID, archiveHelpText)
+		return usecase.Context{}, "", false
+	}
+	return usecase.NewContext(ctx, user), args[0], true
+}
+
+func (b *Bot) handleCommandArchive(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	uctx, id, ok := b.archiveCommand(ctx, update)
+	if !ok {
+		return
+	}
+	motorcycle, err := b.cases.Motorcycle.ArchiveMotorcycle(uctx, id)
+	if err != nil {
+		b.replyArchiveError(ctx, update.Message.Chat.ID, err)
+		return
+	}
+	b.sendMessage(ctx, update.Message.Chat.ID, fmt.Sprintf("🗄 «%s» перенесен в архив. Вернуть: /restore %s", motorcycle.Title, motorcycle.ID))
+}
+
+func (b *Bot) handleCommandRestore(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	uctx, id, ok := b.archiveCommand(ctx, update)
+	if !ok {
+		return
+	}
+	motorcycle, err := b.cases.Motorcycle.RestoreMotorcycle(uctx, id)
+	if err != nil {
+		b.replyArchiveError(ctx, update.Message.Chat.ID, err)
+		return
+	}
+	b.sendMessage(ctx, update.Message.Chat.ID, fmt.Sprintf("♻️ «%s» снова в каталоге", motorcycle.Title))
+}
+
+func (b *Bot) handleCommandDelete(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	uctx, id, ok := b.archiveCommand(ctx, update)
+	if !ok {
+		return
+	}
+	if err := b.cases.Motorcycle.DeleteMotorcycle(uctx, id); err != nil {
+		b.replyArchiveError(ctx, update.Message.Chat.ID, err)
+		return
+	}
+	b.sendMessage(ctx, update.Message.Chat.ID, "🗑 Мотоцикл и его фотографии удалены")
+}
+
+func (b *Bot) replyArchiveError(ctx context.Context, chatID int64, err error) {
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		b.sendError(ctx, chatID, "Мотоцикл не найден.")
+	case errors.Is(err, usecase.ErrNotArchived):
+		b.sendError(ctx, chatID, "Сначала перенесите мотоцикл в архив: /archive <id>")
+	default:
+		slogx.FromCtxWithErr(ctx, err).Error("error updating motorcycle archive")
+		b.sendError(ctx, chatID, "Ошибка при обновлении мотоцикла.")
+	}
+}
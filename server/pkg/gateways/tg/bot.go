@@ -7,7 +7,9 @@ import (
 	"log/slog"
 	"net/url"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
@@ -19,6 +21,17 @@ import (
 	"github.com/shampsdev/go-telegram-template/pkg/utils/slogx"
 )
 
+const (
+	// importPollInterval - как часто бот проверяет задачу импорта
+	importPollInterval = 2 * time.Second
+	// importTrackTimeout - сколько бот следит за задачей, дальше админ проверяет каталог сам
+	importTrackTimeout = 30 * time.Minute
+
+	importStartText = "🔄 Обрабатываю страницу и загружаю фотографии..."
+	// concurrentUpdateText - мотоцикл несколько раз подряд меняли параллельно
+	concurrentUpdateText = "Мотоцикл одновременно редактирует другой админ. Отправьте значение еще раз."
+)
+
 type Bot struct {
 	*bot.Bot
 	cases usecase.Cases
@@ -31,6 +44,13 @@ type Bot struct {
 	waitingPrice sync.Map
 	// Состояние ожидания ввода даты прибытия: userID -> motorcycleID
 	waitingArrivalDate sync.Map
+	// Состояние ожидания фотографий: userID -> photoTarget
+	waitingPhoto sync.Map
+	// Состояние ожидания ответа на вопрос покупателя: userID -> inquiryReply
+	waitingInquiryReply sync.Map
+
+	// limiter держит темп рассылки уведомлений в пределах лимитов Telegram
+	limiter *sendLimiter
 }
 
 func NewBot(ctx context.Context, cfg *config.Config, pool *pgxpool.Pool) (*Bot, error) {
@@ -43,12 +63,16 @@ func NewBot(ctx context.Context, cfg *config.Config, pool *pgxpool.Pool) (*Bot,
 	if err != nil {
 		return nil, fmt.Errorf("error creating bot: %w", err)
 	}
-	cases := usecase.Setup(ctx, cfg, pool)
+	cases, err := usecase.Setup(ctx, cfg, pool)
+	if err != nil {
+		return nil, fmt.Errorf("error setting up usecases: %w", err)
+	}
 
 	b := &Bot{
-		Bot:   tgb,
-		cases: cases,
-		log:   slogx.FromCtx(ctx),
+		Bot:     tgb,
+		cases:   cases,
+		log:     slogx.FromCtx(ctx),
+		limiter: newSendLimiter(cfg.Notification.RatePerSecond, cfg.Notification.ChatInterval),
 	}
 
 	me, err := b.GetMe(context.Background())
@@ -70,8 +94,17 @@ func (b *Bot) Run(ctx context.Context) {
 	}
 
 	b.RegisterHandler(bot.HandlerTypeMessageText, "/start", bot.MatchTypeExact, b.handleCommandStart)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "/cancel", bot.MatchTypeExact, b.handleCommandCancel)
+	b.registerPhotoHandlers()
+	b.registerArchiveHandlers()
+	b.registerPublishHandlers()
+	b.registerReservationHandlers()
+	b.registerInquiryHandlers()
+	b.registerSubscriptionHandlers()
 	b.RegisterHandler(bot.HandlerTypeMessageText, "", bot.MatchTypePrefix, b.handleMessage)
 
+	go b.cases.Notification.Deliver(ctx, b.sendNotification)
+
 	b.Start(ctx)
 }
 
@@ -87,9 +120,9 @@ func (b *Bot) handleCommandStart(ctx context.Context, _ *bot.Bot, update *models
 	// Приветственное сообщение
 	var text string
 	if user.IsAdmin {
-		text = "👋 Добро пожаловать в админ-панель!\n\n🔗 Отправьте ссылку с jmmoto.ru, чтобы добавить новый мотоцикл в каталог"
+		text = fmt.Sprintf("👋 Добро пожаловать в админ-панель!\n\n🔗 Отправьте ссылку с %s, чтобы добавить новый мотоцикл в каталог\n\n%s\n\n%s\n\n%s\n\n%s", b.supportedSources(), photosHelpText, publishHelpText, archiveHelpText, reservationHelpText)
 	} else {
-		text = "🏍️ Добро пожаловать в каталог мотоциклов!\n\n📱 Нажмите кнопку \"Каталог\" чтобы посмотреть доступные мотоциклы"
+		text = "🏍️ Добро пожаловать в каталог мотоциклов!\n\n📱 Нажмите кнопку \"Каталог\" чтобы посмотреть доступные мотоциклы\n\n" + subscriptionHelpText
 	}
 
 	b.sendMessage(ctx, update.Message.Chat.ID, text)
@@ -109,6 +142,12 @@ func (b *Bot) handleMessage(ctx context.Context, _ *bot.Bot, update *models.Upda
 		return
 	}
 
+	// Проверяем, ожидаем ли мы ответ на вопрос покупателя
+	if inquiryID, ok := b.pendingInquiryReply(update.Message.From.ID); ok {
+		b.handleInquiryReplyInput(ctx, update, inquiryID)
+		return
+	}
+
 	// Проверяем, ожидаем ли мы ввод цены
 	if motorcycleID, ok := b.waitingPrice.Load(update.Message.From.ID); ok {
 		b.handlePriceInput(ctx, update, motorcycleID.(string))
@@ -123,19 +162,19 @@ func (b *Bot) handleMessage(ctx context.Context, _ *bot.Bot, update *models.Upda
 
 	// Проверяем, является ли сообщение URL (только для админов)
 	if user.IsAdmin && b.isURL(text) {
-		// Проверяем, что это ссылка с jmmoto.ru
-		if b.isJMMotoURL(text) {
+		// Проверяем, что для сайта есть парсер
+		if b.cases.Motorcycle.IsSupportedSource(text) {
 			b.handleURL(ctx, update, text)
 			return
 		} else {
-			b.sendMessage(ctx, update.Message.Chat.ID, "⚠️ Пожалуйста, отправьте ссылку с сайта jmmoto.ru")
+			b.sendMessage(ctx, update.Message.Chat.ID, fmt.Sprintf("⚠️ Этот сайт не поддерживается. Отправьте ссылку с %s", b.supportedSources()))
 			return
 		}
 	}
 
 	// Для любого другого сообщения показываем соответствующую подсказку
 	if user.IsAdmin {
-		b.sendMessage(ctx, update.Message.Chat.ID, "🔗 Отправьте ссылку с jmmoto.ru для добавления мотоцикла")
+		b.sendMessage(ctx, update.Message.Chat.ID, fmt.Sprintf("🔗 Отправьте ссылку с %s для добавления мотоцикла", b.supportedSources()))
 	} else {
 		b.sendMessage(ctx, update.Message.Chat.ID, "📱 Нажмите кнопку \"Каталог\" чтобы посмотреть доступные мотоциклы")
 	}
@@ -149,12 +188,9 @@ func (b *Bot) isURL(text string) bool {
 	return u.Scheme == "http" || u.Scheme == "https"
 }
 
-func (b *Bot) isJMMotoURL(text string) bool {
-	u, err := url.Parse(text)
-	if err != nil {
-		return false
-	}
-	return u.Host == "jmmoto.ru" || u.Host == "www.jmmoto.ru"
+// supportedSources возвращает список сайтов для подсказок админу
+func (b *Bot) supportedSources() string {
+	return strings.Join(b.cases.Motorcycle.SupportedSources(), ", ")
 }
 
 func (b *Bot) handleURL(ctx context.Context, update *models.Update, urlText string) {
@@ -175,17 +211,17 @@ func (b *Bot) handleURL(ctx context.Context, update *models.Update, urlText stri
 	// Отправляем сообщение о начале обработки
 	msg, err := b.SendMessage(ctx, &bot.SendMessageParams{
 		ChatID: update.Message.Chat.ID,
-		Text:   "🔄 Обрабатываю страницу и загружаю фотографии...",
+		Text:   importStartText,
 	})
 	if err != nil {
 		slogx.FromCtxWithErr(ctx, err).Error("error sending message")
 		return
 	}
 
-	// Создаем мотоцикл из URL
-	motorcycle, err := b.cases.Motorcycle.CreateMotorcycleFromURL(usecase.NewContext(ctx, user), urlText)
+	// Ставим импорт в очередь, воркер скачает страницу и фотографии в фоне
+	job, err := b.cases.Import.Enqueue(usecase.NewContext(ctx, user), urlText)
 	if err != nil {
-		slogx.FromCtxWithErr(ctx, err).Error("error creating motorcycle from URL")
+		slogx.FromCtxWithErr(ctx, err).Error("error enqueuing import")
 		b.EditMessageText(ctx, &bot.EditMessageTextParams{
 			ChatID:    update.Message.Chat.ID,
 			MessageID: msg.ID,
@@ -194,16 +230,81 @@ func (b *Bot) handleURL(ctx context.Context, update *models.Update, urlText stri
 		return
 	}
 
-	// Сохраняем состояние ожидания ввода цены
-	b.waitingPrice.Store(update.Message.From.ID, motorcycle.ID)
+	go b.trackImport(ctx, update.Message.Chat.ID, msg.ID, update.Message.From.ID, job.ID)
+}
+
+// trackImport следит за задачей импорта и обновляет сообщение "Обрабатываю страницу"
+func (b *Bot) trackImport(ctx context.Context, chatID int64, messageID int, userID int64, jobID string) {
+	ctx, cancel := context.WithTimeout(ctx, importTrackTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(importPollInterval)
+	defer ticker.Stop()
+
+	lastText := importStartText
+	for {
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				b.editMessage(context.WithoutCancel(ctx), chatID, messageID, "⌛ Импорт идет дольше обычного. Проверьте каталог позже.")
+			}
+			return
+		case <-ticker.C:
+		}
+
+		job, err := b.cases.Import.GetJob(ctx, jobID)
+		if err != nil {
+			slogx.FromCtxWithErr(ctx, err).Error("error getting import job", "job_id", jobID)
+			continue
+		}
 
-	// Обновляем сообщение с просьбой ввести цену
-	b.EditMessageText(ctx, &bot.EditMessageTextParams{
-		ChatID:    update.Message.Chat.ID,
-		MessageID: msg.ID,
-		Text: fmt.Sprintf("✅ Мотоцикл успешно добавлен:\n🏍️ %s\n\n💰 Введите цену в рублях (только число, например: 500000)",
-			motorcycle.Title),
+		switch job.Status {
+		case domain.ImportJobStatusSucceeded:
+			motorcycle, err := b.cases.Motorcycle.GetMotorcycle(ctx, *job.MotorcycleID)
+			if err != nil {
+				slogx.FromCtxWithErr(ctx, err).Error("error getting imported motorcycle")
+				b.editMessage(ctx, chatID, messageID, "❌ Мотоцикл добавлен, но не удалось его загрузить. Проверьте каталог.")
+				return
+			}
+
+			// Сохраняем состояние ожидания ввода цены
+			b.resetInput(userID)
+			b.waitingPrice.Store(userID, motorcycle.ID)
+			b.editMessage(ctx, chatID, messageID, fmt.Sprintf("✅ Мотоцикл успешно добавлен:\n🏍️ %s\n\n💰 Введите цену в рублях (только число, например: 500000)",
+				motorcycle.Title))
+			return
+		case domain.ImportJobStatusFailed:
+			b.editMessage(ctx, chatID, messageID, fmt.Sprintf("❌ Ошибка при обработке страницы: %s", job.LastError))
+			return
+		}
+
+		if text := importProgressText(job); text != lastText {
+			b.editMessage(ctx, chatID, messageID, text)
+			lastText = text
+		}
+	}
+}
+
+// importProgressText описывает ход импорта для админа
+func importProgressText(job *domain.ImportJob) string {
+	if job.Status == domain.ImportJobStatusQueued && job.LastError != "" {
+		return fmt.Sprintf("⏳ Попытка %d из %d не удалась, повторю позже.\n%s", job.Attempts, job.MaxAttempts, job.LastError)
+	}
+	if job.Stage == domain.ImportJobStagePhotos && job.PhotosTotal > 0 {
+		return fmt.Sprintf("🔄 Загружаю фотографии: %d из %d", job.PhotosDone, job.PhotosTotal)
+	}
+	return importStartText
+}
+
+func (b *Bot) editMessage(ctx context.Context, chatID int64, messageID int, text string) {
+	_, err := b.EditMessageText(ctx, &bot.EditMessageTextParams{
+		ChatID:    chatID,
+		MessageID: messageID,
+		Text:      text,
 	})
+	if err != nil {
+		slogx.FromCtxWithErr(ctx, err).Error("error editing message")
+	}
 }
 
 func (b *Bot) handlePriceInput(ctx context.Context, update *models.Update, motorcycleID string) {
@@ -240,6 +341,11 @@ func (b *Bot) handlePriceInput(ctx context.Context, update *models.Update, motor
 	}
 
 	_, err = b.cases.Motorcycle.PatchMotorcycle(usecase.NewContext(ctx, user), motorcycleID, patch)
+	if errors.Is(err, usecase.ErrVersionMismatch) {
+		b.waitingPrice.Store(update.Message.From.ID, motorcycleID)
+		b.sendError(ctx, update.Message.Chat.ID, concurrentUpdateText)
+		return
+	}
 	if err != nil {
 		slogx.FromCtxWithErr(ctx, err).Error("error updating motorcycle")
 		b.sendError(ctx, update.Message.Chat.ID, "Ошибка при обновлении мотоцикла.")
@@ -288,30 +394,33 @@ func (b *Bot) handleArrivalDateInput(ctx context.Context, update *models.Update,
 		return
 	}
 
-	// Получаем текущие данные мотоцикла
-	motorcycle, err := b.cases.Motorcycle.GetMotorcycle(usecase.NewContext(ctx, user), motorcycleID)
-	if err != nil {
-		slogx.FromCtxWithErr(ctx, err).Error("error getting motorcycle")
-		b.sendError(ctx, update.Message.Chat.ID, "Ошибка при получении данных мотоцикла.")
-		return
-	}
-
-	// Обновляем данные мотоцикла с датой прибытия и устанавливаем статус available
-	var motorcycleData *domain.MotorcycleData
-	if motorcycle.Data != nil {
-		motorcycleData = motorcycle.Data
-	} else {
-		motorcycleData = &domain.MotorcycleData{}
-	}
-	motorcycleData.ArrivalDate = arrivalDateText
-
+	// Меняем только дату прибытия, остальные данные мотоцикла сохраняются, и устанавливаем статус available
 	status := domain.MotorcycleStatusAvailable
 	patch := &domain.PatchMotorcycle{
-		Data:   motorcycleData,
+		Data:   domain.MotorcycleDataPatch{"arrival_date": &arrivalDateText},
 		Status: &status,
 	}
 
 	updatedMotorcycle, err := b.cases.Motorcycle.PatchMotorcycle(usecase.NewContext(ctx, user), motorcycleID, patch)
+	if errors.Is(err, repo.ErrNotFound) {
+		b.sendError(ctx, update.Message.Chat.ID, "Мотоцикл не найден.")
+		return
+	}
+	if errors.Is(err, usecase.ErrVersionMismatch) {
+		b.waitingArrivalDate.Store(update.Message.From.ID, motorcycleID)
+		b.sendError(ctx, update.Message.Chat.ID, concurrentUpdateText)
+		return
+	}
+	var publishErr *usecase.PublishError
+	if errors.As(err, &publishErr) {
+		b.sendMessage(ctx, update.Message.Chat.ID, publishProblemsText(motorcycleID, publishErr))
+		return
+	}
+	var transitionErr *usecase.StatusTransitionError
+	if errors.As(err, &transitionErr) {
+		b.sendError(ctx, update.Message.Chat.ID, fmt.Sprintf("Мотоцикл в статусе %s нельзя опубликовать.", transitionErr.From))
+		return
+	}
 	if err != nil {
 		slogx.FromCtxWithErr(ctx, err).Error("error updating motorcycle")
 		b.sendError(ctx, update.Message.Chat.ID, "Ошибка при обновлении мотоцикла.")
@@ -319,11 +428,13 @@ func (b *Bot) handleArrivalDateInput(ctx context.Context, update *models.Update,
 	}
 
 	b.sendMessage(ctx, update.Message.Chat.ID, fmt.Sprintf(
-		"🎉 Мотоцикл успешно добавлен в каталог!\n\n🏍️ %s\n💰 Цена: %.0f ₽\n📅 Дата прибытия: %s\n📊 Статус: %s\n\n✨ Теперь он доступен в мини-приложении!",
+		"🎉 Мотоцикл успешно добавлен в каталог!\n\n🏍️ %s\n💰 Цена: %.0f ₽\n📅 Дата прибытия: %s\n📊 Статус: %s\n\n✨ Теперь он доступен в мини-приложении!\n\n📷 Фотографии: /photos %s\n🗄 Убрать из каталога: /archive %s",
 		updatedMotorcycle.Title,
 		updatedMotorcycle.Price,
 		arrivalDateText,
 		updatedMotorcycle.Status,
+		updatedMotorcycle.ID,
+		updatedMotorcycle.ID,
 	))
 }
 
@@ -0,0 +1,25 @@
+package tg
+
+import (
+	"context"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+)
+
+// cancelHelpText - подсказка к любому ожиданию ввода
+const cancelHelpText = "Передумали - /cancel"
+
+// resetInput сбрасывает все ожидания ввода пользователя. Новый диалог начинается
+// только после сброса, поэтому следующее сообщение не уйдет в забытый старый диалог
+func (b *Bot) resetInput(userID int64) {
+	b.waitingPrice.Delete(userID)
+	b.waitingArrivalDate.Delete(userID)
+	b.waitingPhoto.Delete(userID)
+	b.waitingInquiryReply.Delete(userID)
+}
+
+func (b *Bot) handleCommandCancel(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	b.resetInput(update.Message.From.ID)
+	b.sendMessage(ctx, update.Message.Chat.ID, "↩️ Отменено")
+}
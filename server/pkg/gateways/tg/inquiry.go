@@ -0,0 +1,91 @@
+package tg
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+	"github.com/shampsdev/go-telegram-template/pkg/usecase"
+	"github.com/shampsdev/go-telegram-template/pkg/utils/slogx"
+)
+
+// inquiryReplyTTL - сколько бот ждет ответ после нажатия «Ответить». Дальше сообщение
+// админа уже не считается ответом, чтобы случайный текст не ушел покупателю
+const inquiryReplyTTL = 15 * time.Minute
+
+// inquiryReply - ожидание ответа админа на вопрос покупателя
+type inquiryReply struct {
+	inquiryID string
+	expiresAt time.Time
+}
+
+func (b *Bot) registerInquiryHandlers() {
+	b.RegisterHandler(bot.HandlerTypeCallbackQueryData, usecase.InquiryReplyData, bot.MatchTypePrefix, b.handleInquiryReplyCallback)
+}
+
+// handleInquiryReplyCallback ждет от админа ответ на вопрос следующим сообщением
+func (b *Bot) handleInquiryReplyCallback(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	query := update.CallbackQuery
+	answer := "✍️ Напишите ответ следующим сообщением"
+
+	user, err := b.cases.User.GetByTelegramID(ctx, query.From.ID)
+	if err != nil || !user.IsAdmin {
+		answer = "🚫 Нет прав"
+	} else {
+		b.resetInput(query.From.ID)
+		b.waitingInquiryReply.Store(query.From.ID, inquiryReply{
+			inquiryID: strings.TrimPrefix(query.Data, usecase.InquiryReplyData),
+			expiresAt: time.Now().Add(inquiryReplyTTL),
+		})
+		b.sendMessage(ctx, query.From.ID, "✍️ Напишите ответ покупателю одним сообщением, бот перешлет его от своего имени. "+cancelHelpText)
+	}
+
+	_, err = b.AnswerCallbackQuery(ctx, &bot.AnswerCallbackQueryParams{
+		CallbackQueryID: query.ID,
+		Text:            answer,
+	})
+	if err != nil {
+		slogx.FromCtxWithErr(ctx, err).Warn("error answering callback query")
+	}
+}
+
+// pendingInquiryReply возвращает вопрос, на который админ отвечает, если ожидание не истекло
+func (b *Bot) pendingInquiryReply(userID int64) (string, bool) {
+	value, ok := b.waitingInquiryReply.Load(userID)
+	if !ok {
+		return "", false
+	}
+	reply := value.(inquiryReply)
+	if time.Now().After(reply.expiresAt) {
+		b.waitingInquiryReply.CompareAndDelete(userID, reply)
+		return "", false
+	}
+	return reply.inquiryID, true
+}
+
+func (b *Bot) handleInquiryReplyInput(ctx context.Context, update *models.Update, inquiryID string) {
+	b.waitingInquiryReply.Delete(update.Message.From.ID)
+	chatID := update.Message.Chat.ID
+
+	user, err := b.getOrCreateUser(ctx, update.Message.From)
+	if err != nil {
+		slogx.FromCtxWithErr(ctx, err).Error("error getting or creating user")
+		b.sendError(ctx, chatID, "Произошла ошибка при получении информации о вас.")
+		return
+	}
+
+	_, err = b.cases.Inquiry.Answer(usecase.NewContext(ctx, user), inquiryID, update.Message.Text)
+	switch {
+	case err == nil:
+		b.sendMessage(ctx, chatID, "✅ Ответ отправлен покупателю")
+	case errors.Is(err, repo.ErrNotFound):
+		b.sendError(ctx, chatID, "Вопрос не найден.")
+	default:
+		slogx.FromCtxWithErr(ctx, err).Error("error answering inquiry")
+		b.sendError(ctx, chatID, "Ошибка при отправке ответа. Нажмите «Ответить» еще раз.")
+	}
+}
@@ -0,0 +1,75 @@
+package tg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/usecase"
+	"github.com/shampsdev/go-telegram-template/pkg/utils/slogx"
+)
+
+// maxCaptionLength - предел Telegram для подписи к фотографии
+const maxCaptionLength = 1024
+
+// sendNotification отправляет сообщение из очереди уведомлений, соблюдая лимиты Telegram
+func (b *Bot) sendNotification(ctx context.Context, notification *domain.Notification) error {
+	if err := b.limiter.Wait(ctx, notification.ChatID); err != nil {
+		return err
+	}
+
+	markup := b.notificationMarkup(notification.Buttons)
+	if notification.PhotoURL != "" && len([]rune(notification.Text)) <= maxCaptionLength {
+		_, err := b.SendPhoto(ctx, &bot.SendPhotoParams{
+			ChatID:      notification.ChatID,
+			Photo:       &models.InputFileString{Data: notification.PhotoURL},
+			Caption:     notification.Text,
+			ReplyMarkup: markup,
+		})
+		if !errors.Is(err, bot.ErrorBadRequest) {
+			return b.deliveryError(err)
+		}
+		// Telegram не смог скачать фотографию (например, хранилище не доступно снаружи) - шлем текст
+		slogx.FromCtxWithErr(ctx, err).Warn("failed to send notification photo, sending text", "notification_id", notification.ID)
+	}
+
+	_, err := b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:      notification.ChatID,
+		Text:        notification.Text,
+		ReplyMarkup: markup,
+	})
+	return b.deliveryError(err)
+}
+
+// deliveryError переводит ошибку Telegram в ошибку очереди уведомлений
+func (b *Bot) deliveryError(err error) error {
+	var tooMany *bot.TooManyRequestsError
+	switch {
+	case errors.As(err, &tooMany):
+		b.limiter.Pause(time.Duration(tooMany.RetryAfter) * time.Second)
+	// Бот заблокирован или чат не существует - повтор не поможет
+	case errors.Is(err, bot.ErrorForbidden):
+		return fmt.Errorf("%w: %v", usecase.ErrUndeliverable, err)
+	}
+	return err
+}
+
+// notificationMarkup собирает кнопки уведомления в один ряд
+func (b *Bot) notificationMarkup(buttons []domain.NotificationButton) models.ReplyMarkup {
+	if len(buttons) == 0 {
+		return nil
+	}
+	row := make([]models.InlineKeyboardButton, 0, len(buttons))
+	for _, button := range buttons {
+		if button.StartApp != "" {
+			row = append(row, models.InlineKeyboardButton{Text: button.Text, URL: b.webAppUrl + "?startapp=" + button.StartApp})
+			continue
+		}
+		row = append(row, models.InlineKeyboardButton{Text: button.Text, CallbackData: button.Data})
+	}
+	return models.InlineKeyboardMarkup{InlineKeyboard: [][]models.InlineKeyboardButton{row}}
+}
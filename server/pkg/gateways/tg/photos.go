@@ -0,0 +1,275 @@
+package tg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+	"github.com/shampsdev/go-telegram-template/pkg/usecase"
+	"github.com/shampsdev/go-telegram-template/pkg/utils/slogx"
+)
+
+// maxPhotoDownloadBytes - Bot API не отдает ботам файлы больше 20 МБ
+const maxPhotoDownloadBytes = 20 << 20
+
+const photosHelpText = `📷 Управление фотографиями:
+/photos <id> - список фотографий
+/photo_add <id> - добавить фотографии, затем /done
+/photo_replace <id> <N> - заменить фотографию N
+/photo_delete <id> <N> - удалить фотографию N
+/photo_cover <id> <N> - сделать фотографию N обложкой
+/photo_order <id> <N1> <N2> ... - новый порядок всех фотографий`
+
+// photoTarget - куда пойдет следующая присланная фотография.
+// Пустой photoID - добавить в конец галереи, иначе заменить эту фотографию
+type photoTarget struct {
+	motorcycleID string
+	photoID      string
+}
+
+func (b *Bot) registerPhotoHandlers() {
+	b.RegisterHandler(bot.HandlerTypeMessageText, "photos", bot.MatchTypeCommandStartOnly, b.handleCommandPhotos)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "photo_add", bot.MatchTypeCommandStartOnly, b.handleCommandPhotoAdd)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "photo_replace", bot.MatchTypeCommandStartOnly, b.handleCommandPhotoReplace)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "photo_delete", bot.MatchTypeCommandStartOnly, b.handleCommandPhotoDelete)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "photo_cover", bot.MatchTypeCommandStartOnly, b.handleCommandPhotoCover)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "photo_order", bot.MatchTypeCommandStartOnly, b.handleCommandPhotoOrder)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "done", bot.MatchTypeCommandStartOnly, b.handleCommandDone)
+	b.RegisterHandlerMatchFunc(func(update *models.Update) bool {
+		return update.Message != nil && (len(update.Message.Photo) > 0 || update.Message.Document != nil)
+	}, b.handlePhotoMessage)
+}
+
+// photoCommand проверяет права и разбирает аргументы команды: ID мотоцикла и номера фотографий
+func (b *Bot) photoCommand(ctx context.Context, update *models.Update, minNumbers int) (usecase.Context, *domain.Motorcycle, []int, bool) {
+	chatID := update.Message.Chat.ID
+	user, err := b.getOrCreateUser(ctx, update.Message.From)
+	if err != nil {
+		slogx.FromCtxWithErr(ctx, err).Error("error getting or creating user")
+		b.sendError(ctx, chatID, "Произошла ошибка при получении информации о вас.")
+		return usecase.Context{}, nil, nil, false
+	}
+	if !user.IsAdmin {
+		b.sendMessage(ctx, chatID, "🚫 У вас нет прав для управления фотографиями.")
+		return usecase.Context{}, nil, nil, false
+	}
+
+	args := strings.Fields(update.Message.Text)[1:]
+	if len(args) < 1+minNumbers {
+		b.sendMessage(ctx, chatID, photosHelpText)
+		return usecase.Context{}, nil, nil, false
+	}
+
+	uctx := usecase.NewContext(ctx, user)
+	motorcycle, err := b.cases.Motorcycle.GetMotorcycle(uctx, args[0])
+	if err != nil {
+		b.sendError(ctx, chatID, "Мотоцикл не найден.")
+		return usecase.Context{}, nil, nil, false
+	}
+
+	numbers := make([]int, 0, len(args)-1)
+	for _, arg := range args[1:] {
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 1 || n > len(motorcycle.Photos) {
+			b.sendError(ctx, chatID, fmt.Sprintf("Номер фотографии должен быть от 1 до %d.", len(motorcycle.Photos)))
+			return usecase.Context{}, nil, nil, false
+		}
+		numbers = append(numbers, n)
+	}
+
+	return uctx, motorcycle, numbers, true
+}
+
+func (b *Bot) handleCommandPhotos(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	_, motorcycle, _, ok := b.photoCommand(ctx, update, 0)
+	if !ok {
+		return
+	}
+	b.sendMessage(ctx, update.Message.Chat.ID, photosListText(motorcycle))
+}
+
+func (b *Bot) handleCommandPhotoAdd(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	_, motorcycle, _, ok := b.photoCommand(ctx, update, 0)
+	if !ok {
+		return
+	}
+	b.resetInput(update.Message.From.ID)
+	b.waitingPhoto.Store(update.Message.From.ID, photoTarget{motorcycleID: motorcycle.ID})
+	b.sendMessage(ctx, update.Message.Chat.ID, fmt.Sprintf("📤 Отправьте фотографии для «%s». Когда закончите - /done", motorcycle.Title))
+}
+
+func (b *Bot) handleCommandPhotoReplace(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	_, motorcycle, numbers, ok := b.photoCommand(ctx, update, 1)
+	if !ok {
+		return
+	}
+	b.resetInput(update.Message.From.ID)
+	b.waitingPhoto.Store(update.Message.From.ID, photoTarget{
+		motorcycleID: motorcycle.ID,
+		photoID:      motorcycle.Photos[numbers[0]-1].ID,
+	})
+	b.sendMessage(ctx, update.Message.Chat.ID, fmt.Sprintf("📤 Отправьте новую фотографию %d", numbers[0]))
+}
+
+func (b *Bot) handleCommandPhotoDelete(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	uctx, motorcycle, numbers, ok := b.photoCommand(ctx, update, 1)
+	if !ok {
+		return
+	}
+	updated, err := b.cases.Motorcycle.DeletePhoto(uctx, motorcycle.ID, motorcycle.Photos[numbers[0]-1].ID)
+	b.replyPhotos(ctx, update.Message.Chat.ID, updated, err, fmt.Sprintf("🗑 Фотография %d удалена", numbers[0]))
+}
+
+func (b *Bot) handleCommandPhotoCover(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	uctx, motorcycle, numbers, ok := b.photoCommand(ctx, update, 1)
+	if !ok {
+		return
+	}
+	updated, err := b.cases.Motorcycle.SetCoverPhoto(uctx, motorcycle.ID, motorcycle.Photos[numbers[0]-1].ID)
+	b.replyPhotos(ctx, update.Message.Chat.ID, updated, err, fmt.Sprintf("⭐ Фотография %d теперь обложка", numbers[0]))
+}
+
+func (b *Bot) handleCommandPhotoOrder(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	uctx, motorcycle, numbers, ok := b.photoCommand(ctx, update, 1)
+	if !ok {
+		return
+	}
+	photoIDs := make([]string, 0, len(numbers))
+	for _, n := range numbers {
+		photoIDs = append(photoIDs, motorcycle.Photos[n-1].ID)
+	}
+	updated, err := b.cases.Motorcycle.ReorderPhotos(uctx, motorcycle.ID, photoIDs)
+	b.replyPhotos(ctx, update.Message.Chat.ID, updated, err, "🔀 Порядок фотографий обновлен")
+}
+
+func (b *Bot) handleCommandDone(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	if _, ok := b.waitingPhoto.LoadAndDelete(update.Message.From.ID); !ok {
+		b.sendMessage(ctx, update.Message.Chat.ID, photosHelpText)
+		return
+	}
+	b.sendMessage(ctx, update.Message.Chat.ID, "✅ Готово")
+}
+
+// handlePhotoMessage загружает присланную фотографию по ожидающей команде /photo_add или /photo_replace
+func (b *Bot) handlePhotoMessage(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	chatID := update.Message.Chat.ID
+	value, ok := b.waitingPhoto.Load(update.Message.From.ID)
+	if !ok {
+		b.sendMessage(ctx, chatID, "📷 Чтобы добавить фотографии к мотоциклу, сначала отправьте /photo_add <id>")
+		return
+	}
+	target := value.(photoTarget)
+
+	user, err := b.getOrCreateUser(ctx, update.Message.From)
+	if err != nil || !user.IsAdmin {
+		b.waitingPhoto.Delete(update.Message.From.ID)
+		b.sendMessage(ctx, chatID, "🚫 У вас нет прав для управления фотографиями.")
+		return
+	}
+
+	data, err := b.downloadPhoto(ctx, update.Message)
+	if err != nil {
+		slogx.FromCtxWithErr(ctx, err).Error("error downloading photo")
+		b.sendError(ctx, chatID, "Не удалось скачать фотографию из Telegram.")
+		return
+	}
+
+	uctx := usecase.NewContext(ctx, user)
+	if target.photoID != "" {
+		// Замена - одноразовая операция
+		b.waitingPhoto.Delete(update.Message.From.ID)
+		updated, err := b.cases.Motorcycle.ReplacePhoto(uctx, target.motorcycleID, target.photoID, data)
+		b.replyPhotos(ctx, chatID, updated, err, "🔁 Фотография заменена")
+		return
+	}
+
+	updated, err := b.cases.Motorcycle.UploadPhotos(uctx, target.motorcycleID, [][]byte{data})
+	if err != nil {
+		b.replyPhotos(ctx, chatID, nil, err, "")
+		return
+	}
+	b.sendMessage(ctx, chatID, fmt.Sprintf("✅ Добавлено, всего фотографий: %d. Еще фото или /done", len(updated.Photos)))
+}
+
+// replyPhotos сообщает результат операции с галереей
+func (b *Bot) replyPhotos(ctx context.Context, chatID int64, motorcycle *domain.Motorcycle, err error, success string) {
+	switch {
+	case err == nil:
+		b.sendMessage(ctx, chatID, success+"\n\n"+photosListText(motorcycle))
+	case errors.Is(err, repo.ErrNotFound):
+		b.sendError(ctx, chatID, "Мотоцикл или фотография не найдены.")
+	case errors.Is(err, usecase.ErrInvalidPhoto):
+		b.sendError(ctx, chatID, "Файл не похож на фотографию.")
+	case errors.Is(err, usecase.ErrInvalidPhotoOrder):
+		b.sendError(ctx, chatID, "Перечислите номера всех фотографий, каждый по одному разу.")
+	default:
+		slogx.FromCtxWithErr(ctx, err).Error("error updating photos")
+		b.sendError(ctx, chatID, "Ошибка при обновлении фотографий.")
+	}
+}
+
+// downloadPhoto скачивает самое крупное превью фотографии или присланный документ-изображение
+func (b *Bot) downloadPhoto(ctx context.Context, msg *models.Message) ([]byte, error) {
+	var fileID string
+	switch {
+	case len(msg.Photo) > 0:
+		fileID = msg.Photo[len(msg.Photo)-1].FileID
+	case msg.Document != nil && strings.HasPrefix(msg.Document.MimeType, "image/"):
+		fileID = msg.Document.FileID
+	default:
+		return nil, fmt.Errorf("message has no image")
+	}
+
+	file, err := b.GetFile(ctx, &bot.GetFileParams{FileID: fileID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.FileDownloadLink(file), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		// В ссылке есть токен бота, поэтому не оборачиваем ошибку с URL
+		return nil, errors.New("failed to download file")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPhotoDownloadBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if len(data) > maxPhotoDownloadBytes {
+		return nil, fmt.Errorf("file is larger than %d bytes", maxPhotoDownloadBytes)
+	}
+	return data, nil
+}
+
+// photosListText перечисляет фотографии с номерами для команд
+func photosListText(motorcycle *domain.Motorcycle) string {
+	if len(motorcycle.Photos) == 0 {
+		return fmt.Sprintf("🏍️ %s\n📷 Фотографий нет. Добавить: /photo_add %s", motorcycle.Title, motorcycle.ID)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "🏍️ %s\n🆔 %s\n", motorcycle.Title, motorcycle.ID)
+	for i, photo := range motorcycle.Photos {
+		fmt.Fprintf(&sb, "\n%d. %s", i+1, photo.S3URL)
+		if i == 0 {
+			sb.WriteString(" ⭐")
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,69 @@
+package tg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+	"github.com/shampsdev/go-telegram-template/pkg/usecase"
+	"github.com/shampsdev/go-telegram-template/pkg/utils/slogx"
+)
+
+const publishHelpText = `🚀 Публикация:
+/publish <id> - опубликовать черновик в каталоге`
+
+func (b *Bot) registerPublishHandlers() {
+	b.RegisterHandler(bot.HandlerTypeMessageText, "publish", bot.MatchTypeCommandStartOnly, b.handleCommandPublish)
+}
+
+func (b *Bot) handleCommandPublish(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	chatID := update.Message.Chat.ID
+	user, err := b.getOrCreateUser(ctx, update.Message.From)
+	if err != nil {
+		slogx.FromCtxWithErr(ctx, err).Error("error getting or creating user")
+		b.sendError(ctx, chatID, "Произошла ошибка при получении информации о вас.")
+		return
+	}
+	if !user.IsAdmin {
+		b.sendMessage(ctx, chatID, "🚫 У вас нет прав для управления каталогом.")
+		return
+	}
+
+	args := strings.Fields(update.Message.Text)[1:]
+	if len(args) != 1 {
+		b.sendMessage(ctx, chatID, publishHelpText)
+		return
+	}
+
+	motorcycle, err := b.cases.Motorcycle.PublishMotorcycle(usecase.NewContext(ctx, user), args[0])
+	var publishErr *usecase.PublishError
+	var transitionErr *usecase.StatusTransitionError
+	switch {
+	case err == nil:
+		b.sendMessage(ctx, chatID, fmt.Sprintf("🎉 «%s» опубликован в каталоге", motorcycle.Title))
+	case errors.As(err, &publishErr):
+		b.sendMessage(ctx, chatID, publishProblemsText(args[0], publishErr))
+	case errors.As(err, &transitionErr):
+		b.sendError(ctx, chatID, fmt.Sprintf("Мотоцикл в статусе %s нельзя опубликовать.", transitionErr.From))
+	case errors.Is(err, repo.ErrNotFound):
+		b.sendError(ctx, chatID, "Мотоцикл не найден.")
+	default:
+		slogx.FromCtxWithErr(ctx, err).Error("error publishing motorcycle")
+		b.sendError(ctx, chatID, "Ошибка при публикации мотоцикла.")
+	}
+}
+
+// publishProblemsText перечисляет, что нужно заполнить перед публикацией
+func publishProblemsText(motorcycleID string, err *usecase.PublishError) string {
+	var sb strings.Builder
+	sb.WriteString("📝 Черновик сохранен, но для публикации не хватает:")
+	for _, problem := range err.Problems {
+		fmt.Fprintf(&sb, "\n• %s", problem.Message)
+	}
+	fmt.Fprintf(&sb, "\n\n📷 Фотографии: /photo_add %s\nКогда все будет готово: /publish %s", motorcycleID, motorcycleID)
+	return sb.String()
+}
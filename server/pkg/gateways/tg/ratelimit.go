@@ -0,0 +1,81 @@
+package tg
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// maxTrackedChats - после стольких чатов limiter забывает чаты, в которые уже можно писать
+const maxTrackedChats = 10000
+
+// sendLimiter держит темп рассылки в пределах лимитов Telegram: не больше
+// perSecond сообщений в секунду всего и одно сообщение в чат раз в chatInterval
+type sendLimiter struct {
+	mu           sync.Mutex
+	interval     time.Duration
+	chatInterval time.Duration
+	// next - когда можно отправить следующее сообщение
+	next time.Time
+	// chats - когда можно отправить следующее сообщение в чат
+	chats map[int64]time.Time
+}
+
+func newSendLimiter(perSecond int, chatInterval time.Duration) *sendLimiter {
+	return &sendLimiter{
+		interval:     time.Second / time.Duration(max(perSecond, 1)),
+		chatInterval: chatInterval,
+		chats:        map[int64]time.Time{},
+	}
+}
+
+// Wait занимает ближайшее свободное время отправки в чат chatID и ждет его
+func (l *sendLimiter) Wait(ctx context.Context, chatID int64) error {
+	at := l.reserve(chatID, time.Now())
+
+	timer := time.NewTimer(time.Until(at))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserve занимает ближайшее после now время отправки в чат chatID и возвращает его
+func (l *sendLimiter) reserve(chatID int64, now time.Time) time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	at := now
+	if l.next.After(at) {
+		at = l.next
+	}
+	if chatAt := l.chats[chatID]; chatAt.After(at) {
+		at = chatAt
+	}
+	l.next = at.Add(l.interval)
+	if len(l.chats) >= maxTrackedChats {
+		l.forget(now)
+	}
+	l.chats[chatID] = at.Add(l.chatInterval)
+	return at
+}
+
+// Pause откладывает все отправки на d: Telegram ответил 429 Too Many Requests
+func (l *sendLimiter) Pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if next := time.Now().Add(d); next.After(l.next) {
+		l.next = next
+	}
+}
+
+// forget удаляет чаты, в которые уже можно писать без ожидания. Вызывается под mu
+func (l *sendLimiter) forget(now time.Time) {
+	for chatID, at := range l.chats {
+		if !at.After(now) {
+			delete(l.chats, chatID)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package tg
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSendLimiterReserve(t *testing.T) {
+	type send struct {
+		chatID int64
+		// after - сдвиг момента вызова от начала теста
+		after time.Duration
+		// want - ожидаемый сдвиг времени отправки от начала теста
+		want time.Duration
+	}
+	tests := []struct {
+		name         string
+		perSecond    int
+		chatInterval time.Duration
+		sends        []send
+	}{
+		{
+			name:      "first send is immediate",
+			perSecond: 10,
+			sends:     []send{{chatID: 1}},
+		},
+		{
+			name:      "global rate spreads chats",
+			perSecond: 10,
+			sends: []send{
+				{chatID: 1},
+				{chatID: 2, want: 100 * time.Millisecond},
+				{chatID: 3, want: 200 * time.Millisecond},
+			},
+		},
+		{
+			name:         "same chat waits chat interval",
+			perSecond:    10,
+			chatInterval: time.Second,
+			sends: []send{
+				{chatID: 1},
+				{chatID: 1, want: time.Second},
+				{chatID: 2, want: 1100 * time.Millisecond},
+			},
+		},
+		{
+			name:         "free slot after a pause in sending",
+			perSecond:    10,
+			chatInterval: time.Second,
+			sends: []send{
+				{chatID: 1},
+				{chatID: 2, after: 5 * time.Second, want: 5 * time.Second},
+				{chatID: 1, after: 5 * time.Second, want: 5100 * time.Millisecond},
+			},
+		},
+		{
+			name:      "zero rate falls back to one per second",
+			perSecond: 0,
+			sends: []send{
+				{chatID: 1},
+				{chatID: 2, want: time.Second},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newSendLimiter(tt.perSecond, tt.chatInterval)
+			start := time.Now()
+			for i, s := range tt.sends {
+				if got := l.reserve(s.chatID, start.Add(s.after)).Sub(start); got != s.want {
+					t.Errorf("send %d to chat %d at +%v: got +%v, want +%v", i, s.chatID, s.after, got, s.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSendLimiterPause(t *testing.T) {
+	l := newSendLimiter(10, 0)
+	l.Pause(time.Hour)
+	now := time.Now()
+	if got := l.reserve(1, now); got.Before(now.Add(59 * time.Minute)) {
+		t.Errorf("reserve after Pause(1h) = +%v, want about +1h", got.Sub(now))
+	}
+
+	// Короткая пауза не отменяет уже отложенную отправку
+	l.Pause(time.Second)
+	if got := l.reserve(2, now); got.Before(now.Add(59 * time.Minute)) {
+		t.Errorf("reserve after shorter Pause = +%v, want about +1h", got.Sub(now))
+	}
+}
+
+func TestSendLimiterForget(t *testing.T) {
+	l := newSendLimiter(1000, time.Second)
+	start := time.Now()
+	for chatID := range int64(maxTrackedChats) {
+		l.reserve(chatID, start)
+	}
+	// К этому моменту все чаты снова свободны, и limiter должен их забыть
+	l.reserve(-1, start.Add(time.Hour))
+	if len(l.chats) != 1 {
+		t.Errorf("tracked chats = %d, want 1", len(l.chats))
+	}
+}
@@ -0,0 +1,111 @@
+package tg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+	"github.com/shampsdev/go-telegram-template/pkg/usecase"
+	"github.com/shampsdev/go-telegram-template/pkg/utils/slogx"
+)
+
+const reservationHelpText = `📝 Брони:
+/reservations - заново прислать заявки, которые ждут решения`
+
+func (b *Bot) registerReservationHandlers() {
+	b.RegisterHandler(bot.HandlerTypeMessageText, "reservations", bot.MatchTypeCommandStartOnly, b.handleCommandReservations)
+	b.RegisterHandler(bot.HandlerTypeCallbackQueryData, "resv:", bot.MatchTypePrefix, b.handleReservationCallback)
+}
+
+func (b *Bot) handleCommandReservations(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	chatID := update.Message.Chat.ID
+	user, err := b.getOrCreateUser(ctx, update.Message.From)
+	if err != nil {
+		slogx.FromCtxWithErr(ctx, err).Error("error getting or creating user")
+		b.sendError(ctx, chatID, "Произошла ошибка при получении информации о вас.")
+		return
+	}
+	if !user.IsAdmin {
+		b.sendMessage(ctx, chatID, "🚫 У вас нет прав для управления каталогом.")
+		return
+	}
+
+	count, err := b.cases.Reservation.NotifyPending(ctx, user)
+	if err != nil {
+		slogx.FromCtxWithErr(ctx, err).Error("error resending pending reservations")
+		b.sendError(ctx, chatID, "Не удалось получить заявки.")
+		return
+	}
+	if count == 0 {
+		b.sendMessage(ctx, chatID, "✅ Заявок, ожидающих решения, нет")
+	}
+}
+
+func (b *Bot) handleReservationCallback(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	query := update.CallbackQuery
+	answer := b.decideReservation(ctx, query)
+	_, err := b.AnswerCallbackQuery(ctx, &bot.AnswerCallbackQueryParams{
+		CallbackQueryID: query.ID,
+		Text:            answer,
+	})
+	if err != nil {
+		slogx.FromCtxWithErr(ctx, err).Warn("error answering callback query")
+	}
+}
+
+// decideReservation выполняет нажатую кнопку и возвращает текст ответа на нажатие.
+// При успехе решение дописывается в сообщение, а кнопки убираются
+func (b *Bot) decideReservation(ctx context.Context, query *models.CallbackQuery) string {
+	user, err := b.cases.User.GetByTelegramID(ctx, query.From.ID)
+	if err != nil || !user.IsAdmin {
+		return "🚫 Нет прав"
+	}
+
+	var reservation *domain.Reservation
+	var result string
+	uctx := usecase.NewContext(ctx, user)
+	switch {
+	case strings.HasPrefix(query.Data, usecase.ReservationApproveData):
+		reservation, err = b.cases.Reservation.Approve(uctx, strings.TrimPrefix(query.Data, usecase.ReservationApproveData))
+		result = "✅ Подтверждено"
+	case strings.HasPrefix(query.Data, usecase.ReservationDeclineData):
+		reservation, err = b.cases.Reservation.Decline(uctx, strings.TrimPrefix(query.Data, usecase.ReservationDeclineData))
+		result = "❌ Отклонено"
+	default:
+		return "Неизвестное действие"
+	}
+
+	switch {
+	case err == nil:
+	case errors.Is(err, repo.ErrNotFound):
+		return "Заявка не найдена"
+	case errors.Is(err, usecase.ErrReservationDecided):
+		return "Заявка уже рассмотрена"
+	case errors.Is(err, usecase.ErrNotAvailable):
+		return "Мотоцикл уже недоступен для брони"
+	default:
+		slogx.FromCtxWithErr(ctx, err).Error("error deciding reservation", "data", query.Data)
+		return "Ошибка, попробуйте еще раз"
+	}
+
+	if message := query.Message.Message; message != nil {
+		text := fmt.Sprintf("%s\n\n%s", message.Text, result)
+		if reservation.ExpiresAt != nil {
+			text += fmt.Sprintf(" до %s", reservation.ExpiresAt.Format("02.01.2006 15:04"))
+		}
+		_, err := b.EditMessageText(ctx, &bot.EditMessageTextParams{
+			ChatID:    message.Chat.ID,
+			MessageID: message.ID,
+			Text:      fmt.Sprintf("%s (%s)", text, user.FirstName),
+		})
+		if err != nil {
+			slogx.FromCtxWithErr(ctx, err).Warn("error editing reservation message")
+		}
+	}
+	return result
+}
@@ -0,0 +1,99 @@
+package tg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-telegram/bot"
+	"github.com/go-telegram/bot/models"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+	"github.com/shampsdev/go-telegram-template/pkg/usecase"
+	"github.com/shampsdev/go-telegram-template/pkg/utils/slogx"
+)
+
+const subscriptionHelpText = `🔔 Уведомления:
+/subscribe - сообщать о новых мотоциклах и снижении цен
+/unsubscribe - отключить уведомления`
+
+func (b *Bot) registerSubscriptionHandlers() {
+	b.RegisterHandler(bot.HandlerTypeMessageText, "subscribe", bot.MatchTypeCommandStartOnly, b.handleCommandSubscribe)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "unsubscribe", bot.MatchTypeCommandStartOnly, b.handleCommandUnsubscribe)
+}
+
+// handleCommandSubscribe подписывает на все мотоциклы. Фильтр уже настроенной
+// в мини-приложении подписки не сбрасывается
+func (b *Bot) handleCommandSubscribe(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	chatID := update.Message.Chat.ID
+	user, err := b.getOrCreateUser(ctx, update.Message.From)
+	if err != nil {
+		slogx.FromCtxWithErr(ctx, err).Error("error getting or creating user")
+		b.sendError(ctx, chatID, "Произошла ошибка при получении информации о вас.")
+		return
+	}
+	uctx := usecase.NewContext(ctx, user)
+
+	subscription, err := b.cases.Subscription.GetSubscription(uctx)
+	if errors.Is(err, repo.ErrNotFound) {
+		subscription, err = b.cases.Subscription.Subscribe(uctx, &domain.SetSubscription{})
+	}
+	if err != nil {
+		slogx.FromCtxWithErr(ctx, err).Error("error subscribing user")
+		b.sendError(ctx, chatID, "Не удалось включить уведомления.")
+		return
+	}
+	b.sendMessage(ctx, chatID, "🔔 Уведомления включены: "+subscriptionFilterText(subscription)+"\n\nОтключить: /unsubscribe")
+}
+
+func (b *Bot) handleCommandUnsubscribe(ctx context.Context, _ *bot.Bot, update *models.Update) {
+	chatID := update.Message.Chat.ID
+	user, err := b.getOrCreateUser(ctx, update.Message.From)
+	if err != nil {
+		slogx.FromCtxWithErr(ctx, err).Error("error getting or creating user")
+		b.sendError(ctx, chatID, "Произошла ошибка при получении информации о вас.")
+		return
+	}
+
+	if err := b.cases.Subscription.Unsubscribe(usecase.NewContext(ctx, user)); err != nil {
+		slogx.FromCtxWithErr(ctx, err).Error("error unsubscribing user")
+		b.sendError(ctx, chatID, "Не удалось отключить уведомления.")
+		return
+	}
+	b.sendMessage(ctx, chatID, "🔕 Уведомления отключены. Включить снова: /subscribe")
+}
+
+// subscriptionFilterText описывает фильтр подписки
+func subscriptionFilterText(subscription *domain.Subscription) string {
+	filter := subscription.SavedSearchFilter
+	var parts []string
+	if filter.Status != nil {
+		parts = append(parts, "статус "+string(*filter.Status))
+	}
+	if filter.Brand != nil {
+		parts = append(parts, "марка "+*filter.Brand)
+	}
+	if filter.Title != nil {
+		parts = append(parts, "название «"+*filter.Title+"»")
+	}
+	if filter.MinPrice != nil {
+		parts = append(parts, fmt.Sprintf("цена от %.0f", *filter.MinPrice))
+	}
+	if filter.MaxPrice != nil {
+		parts = append(parts, fmt.Sprintf("цена до %.0f", *filter.MaxPrice))
+	}
+	if filter.MinVolume != nil {
+		parts = append(parts, fmt.Sprintf("объем от %d см³", *filter.MinVolume))
+	}
+	if filter.MaxVolume != nil {
+		parts = append(parts, fmt.Sprintf("объем до %d см³", *filter.MaxVolume))
+	}
+	if filter.MaxMileage != nil {
+		parts = append(parts, fmt.Sprintf("пробег до %d км", *filter.MaxMileage))
+	}
+	if len(parts) == 0 {
+		return "все мотоциклы"
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,150 @@
+// Package imageproc готовит фотографии к публикации: поворачивает по EXIF,
+// уменьшает до нескольких размеров и перекодирует в JPEG и WebP.
+// Перекодирование заодно удаляет все метаданные исходного файла (EXIF, GPS).
+package imageproc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/disintegration/imaging"
+	"github.com/shampsdev/go-telegram-template/pkg/config"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/utils/slogx"
+
+	// Декодер WebP для исходников с сайтов, которые отдают фото в WebP
+	_ "golang.org/x/image/webp"
+)
+
+// maxPixels защищает от "бомб": маленьких файлов с огромным разрешением
+const maxPixels = 50_000_000
+
+var (
+	// ErrInvalidImage - данные не удалось разобрать как изображение
+	ErrInvalidImage  = errors.New("invalid image")
+	ErrTooManyPixels = fmt.Errorf("%w: resolution is too large", ErrInvalidImage)
+)
+
+// Size - вариант размера: изображение вписывается в квадрат MaxSide x MaxSide
+type Size struct {
+	Name    string
+	MaxSide int
+}
+
+// Sizes - варианты, которые получает каждая фотография мотоцикла
+var Sizes = []Size{
+	{Name: domain.PhotoVariantThumb, MaxSide: 320},
+	{Name: domain.PhotoVariantMedium, MaxSide: 1024},
+	{Name: domain.PhotoVariantFull, MaxSide: 2048},
+}
+
+// Variant - закодированный вариант фотографии
+type Variant struct {
+	Name   string
+	Format string
+	Width  int
+	Height int
+	Data   []byte
+}
+
+type Processor struct {
+	cfg config.ImagesConfig
+	// cwebp - путь к кодировщику WebP; пустой, если он не установлен
+	cwebp string
+}
+
+func NewProcessor(ctx context.Context, cfg config.ImagesConfig) *Processor {
+	p := &Processor{cfg: cfg}
+	if path, err := exec.LookPath(cfg.CWebPPath); err == nil {
+		p.cwebp = path
+	} else {
+		slogx.FromCtx(ctx).Warn("cwebp not found, WebP variants are disabled", "path", cfg.CWebPPath)
+	}
+	return p
+}
+
+// Process возвращает варианты всех размеров из Sizes: JPEG всегда, WebP - если есть cwebp
+func (p *Processor) Process(ctx context.Context, data []byte) ([]Variant, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read image header: %v", ErrInvalidImage, err)
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return nil, fmt.Errorf("%w: %dx%d", ErrTooManyPixels, cfg.Width, cfg.Height)
+	}
+
+	img, err := imaging.Decode(bytes.NewReader(data), imaging.AutoOrientation(true))
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to decode image: %v", ErrInvalidImage, err)
+	}
+
+	variants := make([]Variant, 0, len(Sizes)*2)
+	for _, size := range Sizes {
+		resized := fit(img, size.MaxSide)
+		bounds := resized.Bounds()
+
+		var jpeg bytes.Buffer
+		if err := imaging.Encode(&jpeg, resized, imaging.JPEG, imaging.JPEGQuality(p.cfg.JPEGQuality)); err != nil {
+			return nil, fmt.Errorf("failed to encode %s jpeg: %w", size.Name, err)
+		}
+		variants = append(variants, Variant{
+			Name:   size.Name,
+			Format: domain.PhotoFormatJPEG,
+			Width:  bounds.Dx(),
+			Height: bounds.Dy(),
+			Data:   jpeg.Bytes(),
+		})
+
+		if p.cwebp == "" {
+			continue
+		}
+		webp, err := p.encodeWebP(ctx, jpeg.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s webp: %w", size.Name, err)
+		}
+		variants = append(variants, Variant{
+			Name:   size.Name,
+			Format: domain.PhotoFormatWebP,
+			Width:  bounds.Dx(),
+			Height: bounds.Dy(),
+			Data:   webp,
+		})
+	}
+	return variants, nil
+}
+
+// fit уменьшает изображение, чтобы большая сторона не превышала maxSide. Не увеличивает
+func fit(img image.Image, maxSide int) image.Image {
+	b := img.Bounds()
+	if b.Dx() <= maxSide && b.Dy() <= maxSide {
+		return img
+	}
+	return imaging.Fit(img, maxSide, maxSide, imaging.Lanczos)
+}
+
+// encodeWebP перекодирует JPEG в WebP внешним cwebp: чистого Go-кодировщика WebP нет
+func (p *Processor) encodeWebP(ctx context.Context, jpeg []byte) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "imageproc")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "in.jpg")
+	out := filepath.Join(dir, "out.webp")
+	if err := os.WriteFile(in, jpeg, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, p.cwebp, "-quiet", "-metadata", "none", "-q", fmt.Sprint(p.cfg.WebPQuality), in, "-o", out)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("cwebp failed: %w: %s", err, bytes.TrimSpace(output))
+	}
+	return os.ReadFile(out)
+}
@@ -0,0 +1,264 @@
+package imageproc
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"os/exec"
+	"testing"
+
+	"github.com/shampsdev/go-telegram-template/pkg/config"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+)
+
+// noWebP - процессор без cwebp: проверяет JPEG-варианты на любой машине
+func noWebP() *Processor {
+	return NewProcessor(context.Background(), config.ImagesConfig{CWebPPath: "cwebp-not-installed", JPEGQuality: 85})
+}
+
+// testImage рисует изображение с красной левой половиной, чтобы по результату
+// было видно, повернуто ли оно
+func testImage(width, height int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.RGBA{B: 255, A: 255}), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(0, 0, width/2, height), image.NewUniform(color.RGBA{R: 255, A: 255}), image.Point{}, draw.Src)
+	return img
+}
+
+func encodeJPEG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatalf("jpeg.Encode: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("png.Encode: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// withEXIFOrientation вставляет сразу после SOI сегмент APP1 с EXIF,
+// в котором есть только тег Orientation
+func withEXIFOrientation(data []byte, orientation uint16) []byte {
+	var tiff bytes.Buffer
+	tiff.WriteString("MM\x00\x2a")
+	binary.Write(&tiff, binary.BigEndian, uint32(8))      // смещение первого IFD
+	binary.Write(&tiff, binary.BigEndian, uint16(1))      // одна запись
+	binary.Write(&tiff, binary.BigEndian, uint16(0x0112)) // Orientation
+	binary.Write(&tiff, binary.BigEndian, uint16(3))      // SHORT
+	binary.Write(&tiff, binary.BigEndian, uint32(1))
+	binary.Write(&tiff, binary.BigEndian, orientation)
+	binary.Write(&tiff, binary.BigEndian, uint16(0))
+	binary.Write(&tiff, binary.BigEndian, uint32(0)) // следующего IFD нет
+
+	payload := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	var out bytes.Buffer
+	out.Write(data[:2])
+	out.Write([]byte{0xFF, 0xE1})
+	binary.Write(&out, binary.BigEndian, uint16(len(payload)+2))
+	out.Write(payload)
+	out.Write(data[2:])
+	return out.Bytes()
+}
+
+// jpegMarkers возвращает маркеры сегментов JPEG до начала данных скана
+func jpegMarkers(t *testing.T, data []byte) []byte {
+	t.Helper()
+	if len(data) < 2 || data[0] != 0xFF || data[1] != 0xD8 {
+		t.Fatal("not a JPEG")
+	}
+	var markers []byte
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			t.Fatalf("broken JPEG segment at %d", i)
+		}
+		marker := data[i+1]
+		markers = append(markers, marker)
+		if marker == 0xDA {
+			break
+		}
+		i += 2 + int(binary.BigEndian.Uint16(data[i+2:]))
+	}
+	return markers
+}
+
+func TestProcessSizes(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		// want - размеры thumb, medium и full
+		want [3][2]int
+	}{
+		{name: "landscape", width: 2560, height: 1920, want: [3][2]int{{320, 240}, {1024, 768}, {2048, 1536}}},
+		{name: "portrait", width: 1500, height: 3000, want: [3][2]int{{160, 320}, {512, 1024}, {1024, 2048}}},
+		{name: "square", width: 2048, height: 2048, want: [3][2]int{{320, 320}, {1024, 1024}, {2048, 2048}}},
+		{name: "small is not upscaled", width: 300, height: 200, want: [3][2]int{{300, 200}, {300, 200}, {300, 200}}},
+		{name: "medium source", width: 1200, height: 800, want: [3][2]int{{320, 213}, {1024, 682}, {1200, 800}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			variants, err := noWebP().Process(context.Background(), encodePNG(t, testImage(tt.width, tt.height)))
+			if err != nil {
+				t.Fatalf("Process() error = %v", err)
+			}
+			if len(variants) != len(Sizes) {
+				t.Fatalf("Process() returned %d variants, want %d", len(variants), len(Sizes))
+			}
+			for i, v := range variants {
+				if v.Name != Sizes[i].Name || v.Format != domain.PhotoFormatJPEG {
+					t.Errorf("variant %d = %s/%s, want %s/%s", i, v.Name, v.Format, Sizes[i].Name, domain.PhotoFormatJPEG)
+				}
+				if v.Width != tt.want[i][0] || v.Height != tt.want[i][1] {
+					t.Errorf("%s = %dx%d, want %dx%d", v.Name, v.Width, v.Height, tt.want[i][0], tt.want[i][1])
+				}
+				cfg, err := jpeg.DecodeConfig(bytes.NewReader(v.Data))
+				if err != nil {
+					t.Fatalf("%s is not a JPEG: %v", v.Name, err)
+				}
+				if cfg.Width != v.Width || cfg.Height != v.Height {
+					t.Errorf("%s data is %dx%d, variant says %dx%d", v.Name, cfg.Width, cfg.Height, v.Width, v.Height)
+				}
+				// Пропорции сохраняются с точностью до округления стороны
+				ratio := float64(tt.width) / float64(tt.height)
+				if got := float64(v.Width) / float64(v.Height); got < ratio*0.99 || got > ratio*1.01 {
+					t.Errorf("%s aspect ratio = %.3f, want %.3f", v.Name, got, ratio)
+				}
+			}
+		})
+	}
+}
+
+func TestProcessEXIF(t *testing.T) {
+	tests := []struct {
+		name        string
+		orientation uint16
+		// width, height - размер после поворота; исходник 300x150 меньше всех вариантов
+		width, height int
+	}{
+		{name: "normal", orientation: 1, width: 300, height: 150},
+		{name: "rotate 90", orientation: 6, width: 150, height: 300},
+		{name: "rotate 270", orientation: 8, width: 150, height: 300},
+		{name: "rotate 180", orientation: 3, width: 300, height: 150},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := withEXIFOrientation(encodeJPEG(t, testImage(300, 150)), tt.orientation)
+			if !bytes.Contains(jpegMarkers(t, data), []byte{0xE1}) {
+				t.Fatal("test image has no APP1 segment")
+			}
+
+			variants, err := noWebP().Process(context.Background(), data)
+			if err != nil {
+				t.Fatalf("Process() error = %v", err)
+			}
+			for _, v := range variants {
+				if bytes.Contains(jpegMarkers(t, v.Data), []byte{0xE1}) {
+					t.Errorf("%s keeps an APP1 (EXIF) segment", v.Name)
+				}
+				if v.Width != tt.width || v.Height != tt.height {
+					t.Errorf("%s = %dx%d, want %dx%d", v.Name, v.Width, v.Height, tt.width, tt.height)
+				}
+			}
+
+			img, err := jpeg.Decode(bytes.NewReader(variants[len(variants)-1].Data))
+			if err != nil {
+				t.Fatalf("jpeg.Decode: %v", err)
+			}
+			b := img.Bounds()
+			r, _, _, _ := img.At(b.Min.X+b.Dx()/8, b.Min.Y+b.Dy()/8).RGBA()
+			corner := "red"
+			if r < 0x8000 {
+				corner = "blue"
+			}
+			// Поворот на 90 переносит левую красную половину наверх, на 270 - вниз, на 180 - вправо
+			wantCorner := map[uint16]string{1: "red", 6: "red", 8: "blue", 3: "blue"}[tt.orientation]
+			if corner != wantCorner {
+				t.Errorf("top-left corner is %s, want %s", corner, wantCorner)
+			}
+		})
+	}
+}
+
+func TestProcessRejects(t *testing.T) {
+	// GIF с крошечным кадром, но огромным логическим экраном: размер берется из заголовка
+	var bomb bytes.Buffer
+	frame := image.NewPaletted(image.Rect(0, 0, 1, 1), []color.Color{color.Black})
+	err := gif.EncodeAll(&bomb, &gif.GIF{
+		Image:  []*image.Paletted{frame},
+		Delay:  []int{0},
+		Config: image.Config{ColorModel: frame.Palette, Width: 10000, Height: 10000},
+	})
+	if err != nil {
+		t.Fatalf("gif.EncodeAll: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{name: "too many pixels", data: bomb.Bytes(), wantErr: ErrTooManyPixels},
+		{name: "not an image", data: []byte("definitely not an image"), wantErr: ErrInvalidImage},
+		{name: "empty", data: nil, wantErr: ErrInvalidImage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := noWebP().Process(context.Background(), tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Process() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProcessWebP(t *testing.T) {
+	data := encodePNG(t, testImage(800, 600))
+
+	// Без cwebp остаются только JPEG
+	variants, err := noWebP().Process(context.Background(), data)
+	if err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	for _, v := range variants {
+		if v.Format != domain.PhotoFormatJPEG {
+			t.Errorf("%s has format %s without cwebp", v.Name, v.Format)
+		}
+	}
+
+	if _, err := exec.LookPath("cwebp"); err != nil {
+		t.Skip("cwebp is not installed")
+	}
+	p := NewProcessor(context.Background(), config.ImagesConfig{CWebPPath: "cwebp", JPEGQuality: 85, WebPQuality: 80})
+	variants, err = p.Process(context.Background(), data)
+	if err != nil {
+		t.Fatalf("Process() with cwebp error = %v", err)
+	}
+	if len(variants) != 2*len(Sizes) {
+		t.Fatalf("Process() with cwebp returned %d variants, want %d", len(variants), 2*len(Sizes))
+	}
+	for i, v := range variants {
+		wantFormat := domain.PhotoFormatJPEG
+		if i%2 == 1 {
+			wantFormat = domain.PhotoFormatWebP
+		}
+		if v.Format != wantFormat || v.Name != Sizes[i/2].Name {
+			t.Errorf("variant %d = %s/%s, want %s/%s", i, v.Name, v.Format, Sizes[i/2].Name, wantFormat)
+		}
+		if wantFormat == domain.PhotoFormatWebP && !bytes.HasPrefix(v.Data, []byte("RIFF")) {
+			t.Errorf("%s webp data is not RIFF", v.Name)
+		}
+	}
+}
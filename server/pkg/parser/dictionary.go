@@ -0,0 +1,154 @@
+package parser
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+)
+
+// Dictionary распознает марку и модель в названии объявления
+// по справочнику марок с синонимами. Справочник хранится в базе, см. usecase.Brand
+type Dictionary struct {
+	brands []*domain.Brand
+	// spellings - все написания марок, от длинных к коротким
+	spellings []brandSpelling
+	// namePattern ищет название в разметке, когда текстом его найти не удалось.
+	// nil для пустого справочника
+	namePattern *regexp.Regexp
+}
+
+type brandSpelling struct {
+	brand *domain.Brand
+	words []string
+}
+
+// Match - результат разбора названия по справочнику
+type Match struct {
+	Brand *domain.Brand
+	// Model - модель из справочника, если нашлась
+	Model *domain.BrandModel
+	// ModelName - название модели с остатком названия: "CB400SF Super Four"
+	ModelName string
+}
+
+func NewDictionary(brands []*domain.Brand) *Dictionary {
+	d := &Dictionary{brands: brands}
+	var names []string
+	for _, b := range brands {
+		for _, spelling := range append([]string{b.Name}, b.Aliases...) {
+			words := strings.Fields(spelling)
+			if len(words) == 0 {
+				continue
+			}
+			d.spellings = append(d.spellings, brandSpelling{brand: b, words: words})
+			names = append(names, regexp.QuoteMeta(strings.Join(words, " ")))
+		}
+	}
+	sort.SliceStable(d.spellings, func(i, j int) bool {
+		return len(strings.Join(d.spellings[i].words, " ")) > len(strings.Join(d.spellings[j].words, " "))
+	})
+	if len(names) == 0 {
+		return d
+	}
+	sort.SliceStable(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+
+	d.namePattern = regexp.MustCompile(`(` + strings.Join(names, "|") + `)(?:<!--\s*-->)?\s*(?:<!--\s*-->)?\s*([A-Z0-9]+)`)
+	return d
+}
+
+// ContainsBrand сообщает, упоминается ли в тексте какая-нибудь марка
+func (d *Dictionary) ContainsBrand(text string) bool {
+	text = strings.ToLower(text)
+	for _, s := range d.spellings {
+		if strings.Contains(text, strings.ToLower(strings.Join(s.words, " "))) {
+			return true
+		}
+	}
+	return false
+}
+
+// FindName ищет в HTML марку, за которой идет модель: "Honda<!-- -->CB400"
+func (d *Dictionary) FindName(html string) string {
+	if d.namePattern == nil {
+		return ""
+	}
+	matches := d.namePattern.FindStringSubmatch(html)
+	if len(matches) < 3 {
+		return ""
+	}
+	return strings.TrimSpace(matches[1] + " " + matches[2])
+}
+
+// Match разбирает название: марка должна стоять в начале,
+// модель ищется среди моделей марки без учета регистра, пробелов и дефисов
+func (d *Dictionary) Match(name string) Match {
+	words := strings.Fields(name)
+	for _, s := range d.spellings {
+		if !hasWordPrefix(words, s.words) {
+			continue
+		}
+		rest := words[len(s.words):]
+		m := Match{Brand: s.brand, ModelName: strings.Join(rest, " ")}
+
+		// Берем модель, совпавшую с наибольшим числом слов
+		matched := 0
+		for _, model := range s.brand.Models {
+			for _, spelling := range append([]string{model.Name}, model.Aliases...) {
+				key := compactName(spelling)
+				for n := len(rest); n > matched; n-- {
+					if compactName(strings.Join(rest[:n], "")) == key {
+						m.Model, matched = model, n
+						break
+					}
+				}
+			}
+		}
+		if m.Model != nil {
+			m.ModelName = strings.Join(append([]string{m.Model.Name}, rest[matched:]...), " ")
+		}
+		return m
+	}
+	return Match{ModelName: strings.Join(words, " ")}
+}
+
+// Normalize приводит название к виду "Марка Модель" в написании справочника
+// и проставляет марку, модель и их идентификаторы
+func (d *Dictionary) Normalize(data *domain.ParsedMotorcycleData) {
+	m := d.Match(data.Name)
+	data.Model = m.ModelName
+	if m.Brand == nil {
+		return
+	}
+
+	data.Brand, data.BrandID = m.Brand.Name, m.Brand.ID
+	if m.Model != nil {
+		data.ModelID = m.Model.ID
+	}
+	data.Name = strings.TrimSpace(data.Brand + " " + data.Model)
+}
+
+func hasWordPrefix(words, prefix []string) bool {
+	if len(words) < len(prefix) {
+		return false
+	}
+	for i, w := range prefix {
+		if !strings.EqualFold(words[i], w) {
+			return false
+		}
+	}
+	return true
+}
+
+// compactName убирает регистр, пробелы и дефисы: "CB 400-SF" -> "cb400sf"
+func compactName(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
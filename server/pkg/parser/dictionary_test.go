@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+)
+
+func testDictionary() *Dictionary {
+	return NewDictionary([]*domain.Brand{
+		{ID: "honda", Name: "Honda", Models: []*domain.BrandModel{
+			{ID: "cb400sf", Name: "CB400SF", Aliases: []string{"CB 400 Super Four"}},
+			{ID: "cb400", Name: "CB400"},
+		}},
+		{ID: "hd", Name: "Harley-Davidson", Aliases: []string{"Harley Davidson", "Harley"}, Models: []*domain.BrandModel{
+			{ID: "fat-boy", Name: "Fat Boy"},
+		}},
+		{ID: "bmw", Name: "BMW"},
+	})
+}
+
+func TestDictionaryMatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		title     string
+		brandID   string
+		modelID   string
+		modelName string
+	}{
+		{name: "brand and model", title: "Honda CB400SF", brandID: "honda", modelID: "cb400sf", modelName: "CB400SF"},
+		{name: "case insensitive", title: "HONDA cb400sf", brandID: "honda", modelID: "cb400sf", modelName: "CB400SF"},
+		{name: "spaces and dashes in model", title: "Honda CB-400 SF Revo", brandID: "honda", modelID: "cb400sf", modelName: "CB400SF Revo"},
+		{name: "model alias", title: "Honda CB 400 Super Four", brandID: "honda", modelID: "cb400sf", modelName: "CB400SF"},
+		{name: "longest model alias wins", title: "Honda CB400 Super Four", brandID: "honda", modelID: "cb400sf", modelName: "CB400SF"},
+		{name: "shorter model", title: "Honda CB400 Revo", brandID: "honda", modelID: "cb400", modelName: "CB400 Revo"},
+		{name: "longest brand alias first", title: "Harley Davidson Fat Boy", brandID: "hd", modelID: "fat-boy", modelName: "Fat Boy"},
+		{name: "short alias", title: "Harley Fatboy 2010", brandID: "hd", modelID: "fat-boy", modelName: "Fat Boy 2010"},
+		{name: "unknown model", title: "BMW R1200GS", brandID: "bmw", modelName: "R1200GS"},
+		{name: "brand not at start", title: "Мотоцикл Honda CB400", modelName: "Мотоцикл Honda CB400"},
+		{name: "unknown brand", title: "Ural Solo", modelName: "Ural Solo"},
+		{name: "brand prefix of word", title: "Hondas CB400", modelName: "Hondas CB400"},
+	}
+	d := testDictionary()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := d.Match(tt.title)
+
+			var brandID, modelID string
+			if m.Brand != nil {
+				brandID = m.Brand.ID
+			}
+			if m.Model != nil {
+				modelID = m.Model.ID
+			}
+			if brandID != tt.brandID || modelID != tt.modelID || m.ModelName != tt.modelName {
+				t.Errorf("Match(%q) = (%q, %q, %q), want (%q, %q, %q)",
+					tt.title, brandID, modelID, m.ModelName, tt.brandID, tt.modelID, tt.modelName)
+			}
+		})
+	}
+}
+
+func TestDictionaryNormalize(t *testing.T) {
+	data := &domain.ParsedMotorcycleData{Name: "harley davidson fat boy"}
+	testDictionary().Normalize(data)
+
+	if data.Name != "Harley-Davidson Fat Boy" || data.Brand != "Harley-Davidson" || data.BrandID != "hd" || data.ModelID != "fat-boy" {
+		t.Errorf("Normalize = %+v", data)
+	}
+}
+
+func TestEmptyDictionary(t *testing.T) {
+	d := NewDictionary(nil)
+
+	if m := d.Match("Honda CB400"); m.Brand != nil || m.ModelName != "Honda CB400" {
+		t.Errorf("Match = %+v, want no brand", m)
+	}
+	if name := d.FindName("Honda<!-- -->CB400"); name != "" {
+		t.Errorf("FindName = %q, want empty", name)
+	}
+}
@@ -0,0 +1,50 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// fetchTimeout ограничивает загрузку страницы целиком, с чтением тела. Он заметно меньше
+// аренды задачи импорта, поэтому зависший сайт не отдаст задачу второму воркеру
+const fetchTimeout = 30 * time.Second
+
+// userAgent имитирует браузер, иначе часть сайтов отдает заглушку
+const userAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36"
+
+// fetchPage загружает страницу, вызывающий обязан закрыть тело ответа
+func fetchPage(ctx context.Context, client *http.Client, pageURL *url.URL) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания запроса: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("неверный статус код: %d", resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
+// resolveURL преобразует ссылку со страницы в абсолютный http(s) URL
+func resolveURL(pageURL *url.URL, ref string) (string, bool) {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return "", false
+	}
+	u = pageURL.ResolveReference(u)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", false
+	}
+	return u.String(), true
+}
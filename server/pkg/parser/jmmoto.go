@@ -2,7 +2,8 @@ package parser
 
 import (
 	"fmt"
-	"net/http"
+	"io"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
@@ -11,38 +12,19 @@ import (
 	"github.com/shampsdev/go-telegram-template/pkg/domain"
 )
 
-type MotorcycleParser struct{}
+// JMMotoParser разбирает страницы объявлений jmmoto.ru
+type JMMotoParser struct{}
 
-func NewMotorcycleParser() *MotorcycleParser {
-	return &MotorcycleParser{}
+func NewJMMotoParser() *JMMotoParser {
+	return &JMMotoParser{}
 }
 
-func (p *MotorcycleParser) ParseMotorcycle(url string) (*domain.ParsedMotorcycleData, error) {
-	// Создаем HTTP клиент
-	client := &http.Client{}
-
-	// Создаем запрос
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("ошибка создания запроса: %w", err)
-	}
-
-	// Устанавливаем User-Agent для имитации браузера
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
-
-	// Выполняем запрос
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("ошибка выполнения запроса: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("неверный статус код: %d", resp.StatusCode)
-	}
+func (p *JMMotoParser) Hosts() []string {
+	return []string{"jmmoto.ru"}
+}
 
-	// Парсим HTML
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+func (p *JMMotoParser) Parse(r io.Reader, pageURL *url.URL, dict *Dictionary) (*domain.ParsedMotorcycleData, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка парсинга HTML: %w", err)
 	}
@@ -63,16 +45,9 @@ func (p *MotorcycleParser) ParseMotorcycle(url string) (*domain.ParsedMotorcycle
 	for _, selector := range nameSelectors {
 		doc.Find(selector).Each(func(i int, s *goquery.Selection) {
 			text := strings.TrimSpace(s.Text())
-			if text != "" && len(text) > 5 && len(text) < 100 {
-				brands := []string{"suzuki", "yamaha", "honda", "kawasaki", "ducati", "bmw", "triumph", "harley"}
-				textLower := strings.ToLower(text)
-				for _, brand := range brands {
-					if strings.Contains(textLower, brand) {
-						text = regexp.MustCompile(`\s+`).ReplaceAllString(text, " ")
-						data.Name = strings.TrimSpace(text)
-						return
-					}
-				}
+			if text != "" && len(text) > 5 && len(text) < 100 && dict.ContainsBrand(text) {
+				text = regexp.MustCompile(`\s+`).ReplaceAllString(text, " ")
+				data.Name = strings.TrimSpace(text)
 			}
 		})
 		if data.Name != "" {
@@ -85,26 +60,15 @@ func (p *MotorcycleParser) ParseMotorcycle(url string) (*domain.ParsedMotorcycle
 		nameSelector := "h1, h2"
 		doc.Find(nameSelector).Each(func(i int, s *goquery.Selection) {
 			text := strings.TrimSpace(s.Text())
-			if text != "" && len(text) > 5 && len(text) < 100 {
-				brands := []string{"suzuki", "yamaha", "honda", "kawasaki", "ducati", "bmw", "triumph", "harley"}
-				textLower := strings.ToLower(text)
-				for _, brand := range brands {
-					if strings.Contains(textLower, brand) {
-						data.Name = text
-						return
-					}
-				}
+			if text != "" && len(text) > 5 && len(text) < 100 && dict.ContainsBrand(text) {
+				data.Name = text
 			}
 		})
 	}
 
 	// Если не нашли, ищем в HTML с помощью regex
 	if data.Name == "" {
-		nameRe := regexp.MustCompile(`(Suzuki|Yamaha|Honda|Kawasaki|Ducati|BMW|Triumph|Harley-Davidson|Harley)(?:<!--\s*-->)?\s*(?:<!--\s*-->)?\s*([A-Z0-9]+)`)
-		matches := nameRe.FindStringSubmatch(bodyHTML)
-		if len(matches) >= 3 {
-			data.Name = strings.TrimSpace(matches[1] + " " + matches[2])
-		}
+		data.Name = dict.FindName(bodyHTML)
 	}
 
 	// Парсим год
@@ -115,11 +79,11 @@ func (p *MotorcycleParser) ParseMotorcycle(url string) (*domain.ParsedMotorcycle
 		}
 	}
 
-	// Парсим пробег
-	mileageRe := regexp.MustCompile(`Пробег:\s*(\d+)\s*км`)
-	if matches := mileageRe.FindStringSubmatch(bodyText); len(matches) >= 2 {
+	// Парсим пробег, переводя мили в километры
+	mileageRe := regexp.MustCompile(`Пробег:\s*(\d+)\s*(км|km|миль|mi)`)
+	if matches := mileageRe.FindStringSubmatch(bodyText); len(matches) >= 3 {
 		if mileage, err := strconv.Atoi(matches[1]); err == nil {
-			data.Mileage = mileage
+			data.Mileage = domain.MileageToKm(mileage, matches[2])
 		}
 	}
 
@@ -163,13 +127,10 @@ func (p *MotorcycleParser) ParseMotorcycle(url string) (*domain.ParsedMotorcycle
 				continue
 			}
 
-			// Преобразуем относительные URL в абсолютные
-			if strings.HasPrefix(src, "//") {
-				src = "https:" + src
-			} else if strings.HasPrefix(src, "/") {
-				src = "https://jmmoto.ru" + src
-			} else if !strings.HasPrefix(src, "http") {
-				src = "https://jmmoto.ru/" + src
+			// Преобразуем относительные URL в абсолютные относительно страницы
+			src, ok := resolveURL(pageURL, src)
+			if !ok {
+				continue
 			}
 
 			// Добавляем только уникальные изображения
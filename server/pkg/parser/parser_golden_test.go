@@ -0,0 +1,144 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+)
+
+// Корпус лежит в testdata/<host>: для каждой страницы <case>.html рядом
+// хранится <case>.json с исходным URL и ожидаемым результатом.
+// Справочник марок для нормализации названий - testdata/brands.json.
+// go test ./pkg/parser -update перезаписывает эталоны текущим результатом парсера
+var update = flag.Bool("update", false, "overwrite golden files with current parser output")
+
+type golden struct {
+	URL  string                      `json:"url"`
+	Data domain.ParsedMotorcycleData `json:"data"`
+}
+
+func TestGolden(t *testing.T) {
+	pages, err := filepath.Glob(filepath.Join("testdata", "*", "*.html"))
+	if err != nil {
+		t.Fatalf("failed to list pages: %v", err)
+	}
+	if len(pages) == 0 {
+		t.Fatal("no pages found in testdata")
+	}
+
+	registry := NewDefaultRegistry()
+	registry.SetBrands(readBrands(t))
+	for _, page := range pages {
+		name, _ := filepath.Rel("testdata", page)
+		t.Run(name, func(t *testing.T) {
+			goldenPath := strings.TrimSuffix(page, ".html") + ".json"
+			want := readGolden(t, goldenPath)
+
+			html, err := os.Open(page)
+			if err != nil {
+				t.Fatalf("failed to open page: %v", err)
+			}
+			defer html.Close()
+
+			got, err := registry.ParseHTML(html, want.URL)
+			if err != nil {
+				t.Fatalf("failed to parse page: %v", err)
+			}
+
+			if *update {
+				want.Data = *got
+				writeGolden(t, goldenPath, want)
+				return
+			}
+			for _, d := range diff(&want.Data, got) {
+				t.Error(d)
+			}
+		})
+	}
+}
+
+func readBrands(t *testing.T) []*domain.Brand {
+	t.Helper()
+	raw, err := os.ReadFile(filepath.Join("testdata", "brands.json"))
+	if err != nil {
+		t.Fatalf("failed to read brands: %v", err)
+	}
+	var brands []*domain.Brand
+	if err := json.Unmarshal(raw, &brands); err != nil {
+		t.Fatalf("failed to decode brands: %v", err)
+	}
+	return brands
+}
+
+func readGolden(t *testing.T, path string) *golden {
+	t.Helper()
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+	g := &golden{}
+	if err := json.Unmarshal(raw, g); err != nil {
+		t.Fatalf("failed to decode golden file: %v", err)
+	}
+	if g.URL == "" {
+		t.Fatalf("golden file %s has no url", path)
+	}
+	return g
+}
+
+func writeGolden(t *testing.T, path string, g *golden) {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(g); err != nil {
+		t.Fatalf("failed to encode golden file: %v", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("failed to write golden file: %v", err)
+	}
+}
+
+// diff сравнивает результаты поле за полем, чтобы в отчете было видно,
+// какое именно извлечение сломалось
+func diff(want, got *domain.ParsedMotorcycleData) []string {
+	var diffs []string
+	wv, gv := reflect.ValueOf(*want), reflect.ValueOf(*got)
+	for i := range wv.NumField() {
+		field := wv.Type().Field(i)
+		w, g := wv.Field(i).Interface(), gv.Field(i).Interface()
+		if reflect.DeepEqual(w, g) {
+			continue
+		}
+		if wl, ok := w.([]string); ok {
+			diffs = append(diffs, diffList(field.Name, wl, g.([]string))...)
+			continue
+		}
+		diffs = append(diffs, fmt.Sprintf("%s: want %q, got %q", field.Name, fmt.Sprint(w), fmt.Sprint(g)))
+	}
+	return diffs
+}
+
+func diffList(name string, want, got []string) []string {
+	var diffs []string
+	for i := range max(len(want), len(got)) {
+		switch {
+		case i >= len(got):
+			diffs = append(diffs, fmt.Sprintf("%s[%d]: missing %q", name, i, want[i]))
+		case i >= len(want):
+			diffs = append(diffs, fmt.Sprintf("%s[%d]: unexpected %q", name, i, got[i]))
+		case want[i] != got[i]:
+			diffs = append(diffs, fmt.Sprintf("%s[%d]: want %q, got %q", name, i, want[i], got[i]))
+		}
+	}
+	return diffs
+}
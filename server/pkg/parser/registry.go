@@ -0,0 +1,143 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+)
+
+var ErrUnsupportedHost = errors.New("unsupported host")
+
+// SiteParser разбирает страницы объявлений конкретного сайта.
+// Парсер не ходит в сеть: страницу загружает Registry,
+// поэтому его можно проверять на сохраненном HTML.
+type SiteParser interface {
+	// Hosts возвращает домены, которые обслуживает парсер (без www.)
+	Hosts() []string
+	// Parse разбирает HTML страницы, pageURL нужен для относительных ссылок,
+	// dict - для поиска названия мотоцикла на странице
+	Parse(r io.Reader, pageURL *url.URL, dict *Dictionary) (*domain.ParsedMotorcycleData, error)
+}
+
+// Registry выбирает парсер по хосту ссылки и нормализует
+// название по справочнику марок
+type Registry struct {
+	client  *http.Client
+	parsers map[string]SiteParser
+	dict    atomic.Pointer[Dictionary]
+}
+
+func NewRegistry(parsers ...SiteParser) *Registry {
+	r := &Registry{
+		client:  &http.Client{Timeout: fetchTimeout},
+		parsers: make(map[string]SiteParser),
+	}
+	// До SetBrands названия не нормализуются
+	r.dict.Store(NewDictionary(nil))
+	for _, p := range parsers {
+		r.Register(p)
+	}
+	return r
+}
+
+// NewDefaultRegistry возвращает реестр со всеми поддерживаемыми сайтами
+func NewDefaultRegistry() *Registry {
+	return NewRegistry(
+		NewJMMotoParser(),
+	)
+}
+
+func (r *Registry) Register(p SiteParser) {
+	for _, host := range p.Hosts() {
+		r.parsers[normalizeHost(host)] = p
+	}
+}
+
+// SetBrands заменяет справочник марок
+func (r *Registry) SetBrands(brands []*domain.Brand) {
+	r.dict.Store(NewDictionary(brands))
+}
+
+// ParseMotorcycle загружает страницу и разбирает ее парсером сайта
+func (r *Registry) ParseMotorcycle(ctx context.Context, rawURL string) (*domain.ParsedMotorcycleData, error) {
+	p, pageURL, err := r.lookup(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := fetchPage(ctx, r.client, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return r.parse(p, body, pageURL)
+}
+
+// ParseHTML разбирает уже загруженную страницу, например сохраненную на диск
+func (r *Registry) ParseHTML(html io.Reader, rawURL string) (*domain.ParsedMotorcycleData, error) {
+	p, pageURL, err := r.lookup(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return r.parse(p, html, pageURL)
+}
+
+func (r *Registry) parse(p SiteParser, html io.Reader, pageURL *url.URL) (*domain.ParsedMotorcycleData, error) {
+	dict := r.dict.Load()
+	data, err := p.Parse(html, pageURL, dict)
+	if err != nil {
+		return nil, err
+	}
+	dict.Normalize(data)
+	return data, nil
+}
+
+// Supports сообщает, есть ли парсер для хоста ссылки
+func (r *Registry) Supports(rawURL string) bool {
+	_, _, err := r.lookup(rawURL)
+	return err == nil
+}
+
+// Hosts возвращает отсортированный список поддерживаемых доменов
+func (r *Registry) Hosts() []string {
+	hosts := make([]string, 0, len(r.parsers))
+	for host := range r.parsers {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+func (r *Registry) lookup(rawURL string) (SiteParser, *url.URL, error) {
+	pageURL, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid url: %w", err)
+	}
+	if pageURL.Scheme != "http" && pageURL.Scheme != "https" {
+		return nil, nil, fmt.Errorf("invalid url scheme %q", pageURL.Scheme)
+	}
+
+	p, ok := r.parsers[normalizeHost(pageURL.Host)]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnsupportedHost, pageURL.Host)
+	}
+	return p, pageURL, nil
+}
+
+func normalizeHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+	return strings.TrimPrefix(host, "www.")
+}
@@ -0,0 +1,244 @@
+// Package disk хранит изображения в локальной папке. REST-сервер раздает ее
+// по маршруту /images, поэтому S3 для разработки и небольших установок не нужен.
+// Бот и сервер должны видеть одну и ту же папку (общий volume).
+package disk
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/shampsdev/go-telegram-template/pkg/config"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+	"github.com/shampsdev/go-telegram-template/pkg/repo/ingest"
+	"github.com/shampsdev/go-telegram-template/pkg/utils/slogx"
+)
+
+type Storage struct {
+	root    string
+	baseURL string
+	fetcher *ingest.Fetcher
+}
+
+func NewStorage(cfg config.StorageConfig, fetcher *ingest.Fetcher) (*Storage, error) {
+	// Папку раздает наш сервер или CDN перед ним, подписывать ссылки некому
+	baseURL := cfg.PublicURL
+	switch cfg.URLStrategy {
+	case config.StorageURLPublic:
+		if cfg.PublicURL == "" {
+			return nil, fmt.Errorf("STORAGE_PUBLIC_URL is required for disk storage")
+		}
+	case config.StorageURLCDN:
+		if cfg.CDNURL == "" {
+			return nil, fmt.Errorf("STORAGE_CDN_URL is required for cdn url strategy")
+		}
+		baseURL = cfg.CDNURL
+	default:
+		return nil, fmt.Errorf("url strategy %q is not supported by disk storage", cfg.URLStrategy)
+	}
+	root, err := filepath.Abs(cfg.ImagesPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve images path: %w", err)
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create images directory: %w", err)
+	}
+
+	return &Storage{
+		root:    root,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		fetcher: fetcher,
+	}, nil
+}
+
+func (s *Storage) SaveImageByURL(ctx context.Context, imageURL, key string) (string, error) {
+	imageData, err := s.fetcher.Fetch(ctx, imageURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to download image: %w", err)
+	}
+
+	fileKey, err := s.SaveImageByBytes(ctx, imageData, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to save image: %w", err)
+	}
+
+	return fileKey, nil
+}
+
+// SaveImagesByURL загружает изображения пулом воркеров ingest.Fetcher
+func (s *Storage) SaveImagesByURL(ctx context.Context, images []repo.ImageSource, progress func(done, total int)) []repo.SavedImage {
+	return s.fetcher.SaveAll(ctx, images, s.SaveImageByBytes, progress)
+}
+
+// SaveImageVariants сохраняет полученное изображение с вариантами размеров
+func (s *Storage) SaveImageVariants(ctx context.Context, data []byte, key string) repo.SavedImage {
+	return s.fetcher.Save(ctx, data, key, s.SaveImageByBytes)
+}
+
+// SaveImageByBytes сохраняет изображение и возвращает его ключ относительно root
+func (s *Storage) SaveImageByBytes(ctx context.Context, imageData []byte, uid string) (string, error) {
+	if uid == "" {
+		uid = uuid.New().String()
+	}
+	mimeType := http.DetectContentType(imageData)
+	fileExtension, _ := mime.ExtensionsByType(mimeType)
+	if len(fileExtension) == 0 {
+		fileExtension = []string{".jpeg"}
+	}
+
+	key := uid + fileExtension[0]
+	filePath, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create image directory: %w", err)
+	}
+
+	// Пишем во временный файл и переименовываем, чтобы сервер не отдал недописанное изображение
+	tmp, err := os.CreateTemp(filepath.Dir(filePath), ".upload-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(imageData); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to write image: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write image: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return "", fmt.Errorf("failed to set image permissions: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filePath); err != nil {
+		return "", fmt.Errorf("failed to move image into place: %w", err)
+	}
+
+	slogx.Info(ctx, "Succesfully saved image", slog.String("key", key))
+
+	return key, nil
+}
+
+// URL строит ссылку на файл от STORAGE_PUBLIC_URL или STORAGE_CDN_URL
+func (s *Storage) URL(key string) (string, error) {
+	if repo.IsAbsoluteURL(key) {
+		return key, nil
+	}
+	return fmt.Sprintf("%s/%s", s.baseURL, key), nil
+}
+
+// DeletePrefix удаляет все файлы, ключ которых начинается с prefix.
+// Для префикса вида "dir/" удаляется папка целиком
+func (s *Storage) DeletePrefix(ctx context.Context, prefix string) error {
+	dirKey, namePrefix := path.Split(prefix)
+	dir, err := s.path(dirKey)
+	if err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to list images under %s: %w", prefix, err)
+	}
+
+	if namePrefix == "" && dir != s.root {
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("failed to delete images under %s: %w", prefix, err)
+		}
+	} else {
+		for _, entry := range entries {
+			if !strings.HasPrefix(entry.Name(), namePrefix) {
+				continue
+			}
+			if err := os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+				return fmt.Errorf("failed to delete %s: %w", entry.Name(), err)
+			}
+		}
+	}
+
+	slogx.Info(ctx, "Deleted images by prefix", slog.String("prefix", prefix))
+	return nil
+}
+
+// List возвращает все файлы, ключ которых начинается с prefix
+func (s *Storage) List(ctx context.Context, prefix string) ([]repo.StoredObject, error) {
+	var objects []repo.StoredObject
+	err := filepath.WalkDir(s.root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.root, filePath)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, repo.StoredObject{
+			Key:        key,
+			Size:       info.Size(),
+			ModifiedAt: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list images under %s: %w", prefix, err)
+	}
+	return objects, nil
+}
+
+// Delete удаляет файлы по ключам из List и опустевшие после этого папки
+func (s *Storage) Delete(ctx context.Context, keys []string) error {
+	for _, key := range keys {
+		filePath, err := s.path(key)
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to delete %s: %w", key, err)
+		}
+
+		// os.Remove не удаляет непустые папки, поэтому поднимаемся, пока папка пуста
+		for dir := filepath.Dir(filePath); dir != s.root && strings.HasPrefix(dir, s.root); dir = filepath.Dir(dir) {
+			if os.Remove(dir) != nil {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// path переводит ключ в путь внутри root и не дает выйти за его пределы
+func (s *Storage) path(key string) (string, error) {
+	rel := filepath.FromSlash(strings.TrimSuffix(key, "/"))
+	if rel == "" {
+		return s.root, nil
+	}
+	if !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("invalid image key: %q", key)
+	}
+	return filepath.Join(s.root, rel), nil
+}
@@ -0,0 +1,101 @@
+package disk
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shampsdev/go-telegram-template/pkg/config"
+)
+
+func newTestStorage(t *testing.T) *Storage {
+	t.Helper()
+	s, err := NewStorage(config.StorageConfig{
+		ImagesPath:  t.TempDir(),
+		PublicURL:   "http://localhost/images",
+		URLStrategy: config.StorageURLPublic,
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewStorage: %v", err)
+	}
+	return s
+}
+
+func TestStoragePath(t *testing.T) {
+	s := newTestStorage(t)
+
+	tests := []struct {
+		key     string
+		want    string
+		wantErr bool
+	}{
+		{key: "", want: s.root},
+		{key: "motorcycles/", want: filepath.Join(s.root, "motorcycles")},
+		{key: "motorcycles/1/0.jpeg", want: filepath.Join(s.root, "motorcycles", "1", "0.jpeg")},
+		{key: "motorcycles/../avatars/1.jpeg", want: filepath.Join(s.root, "avatars", "1.jpeg")},
+		{key: "../etc/passwd", wantErr: true},
+		{key: "motorcycles/../../etc/passwd", wantErr: true},
+		{key: "/etc/passwd", wantErr: true},
+		{key: "..", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, err := s.path(tt.key)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("path(%q) = %q, want error", tt.key, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("path(%q): %v", tt.key, err)
+			}
+			if got != tt.want {
+				t.Errorf("path(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStorageRejectsEscapingKeys(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+	outside := filepath.Join(filepath.Dir(s.root), "outside.txt")
+	if err := os.WriteFile(outside, []byte("keep"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	if _, err := s.SaveImageByBytes(ctx, []byte("data"), "../escape"); err == nil {
+		t.Error("SaveImageByBytes: expected error for key outside root")
+	}
+	if err := s.Delete(ctx, []string{"../outside.txt"}); err == nil {
+		t.Error("Delete: expected error for key outside root")
+	}
+	if err := s.DeletePrefix(ctx, "../"); err == nil {
+		t.Error("DeletePrefix: expected error for prefix outside root")
+	}
+	if _, err := os.Stat(outside); err != nil {
+		t.Errorf("file outside root was touched: %v", err)
+	}
+}
+
+func TestStorageDeleteRemovesEmptyDirs(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+
+	key, err := s.SaveImageByBytes(ctx, []byte("data"), "motorcycles/1/0")
+	if err != nil {
+		t.Fatalf("SaveImageByBytes: %v", err)
+	}
+	if err := s.Delete(ctx, []string{key}); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(s.root, "motorcycles")); !os.IsNotExist(err) {
+		t.Errorf("empty directories left behind: %v", err)
+	}
+	if _, err := os.Stat(s.root); err != nil {
+		t.Errorf("root removed: %v", err)
+	}
+}
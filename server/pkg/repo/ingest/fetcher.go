@@ -0,0 +1,267 @@
+package ingest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shampsdev/go-telegram-template/pkg/config"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/imageproc"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+)
+
+// userAgent - часть сайтов не отдает картинки без браузерного User-Agent
+const userAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36"
+
+var (
+	ErrTooLarge = errors.New("image is too large")
+	ErrNotImage = errors.New("response is not an image")
+)
+
+// statusError - ответ с неуспешным статусом; 429 и 5xx считаются временными
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d", e.code)
+}
+
+// Processor готовит варианты изображения перед сохранением, см. imageproc
+type Processor interface {
+	Process(ctx context.Context, data []byte) ([]imageproc.Variant, error)
+}
+
+// Fetcher скачивает изображения с таймаутом, ограничением размера,
+// проверкой типа содержимого и повтором временных ошибок
+type Fetcher struct {
+	client    *http.Client
+	cfg       config.IngestConfig
+	processor Processor
+	// slots - общий на процесс пул из cfg.Workers одновременных загрузок:
+	// его делят импорты, загрузки аватаров и фото из бота
+	slots chan struct{}
+}
+
+// NewFetcher создает загрузчик; processor может быть nil, тогда SaveAll сохраняет оригиналы
+func NewFetcher(cfg config.IngestConfig, processor Processor) *Fetcher {
+	return &Fetcher{
+		client:    &http.Client{},
+		cfg:       cfg,
+		processor: processor,
+		slots:     make(chan struct{}, max(cfg.Workers, 1)),
+	}
+}
+
+// Fetch скачивает изображение, повторяя запрос при временных ошибках
+func (f *Fetcher) Fetch(ctx context.Context, imageURL string) ([]byte, error) {
+	var err error
+	backoff := f.cfg.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		var data []byte
+		data, err = f.fetchInSlot(ctx, imageURL)
+		if err == nil {
+			return data, nil
+		}
+		if attempt >= f.cfg.Retries || !isTransient(ctx, err) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return nil, fmt.Errorf("failed to fetch %s: %w", imageURL, err)
+}
+
+// fetchInSlot ждет свободного места в пуле загрузок. Место занимается на одну
+// попытку, чтобы ожидание перед повтором не держало пул
+func (f *Fetcher) fetchInSlot(ctx context.Context, imageURL string) ([]byte, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case f.slots <- struct{}{}:
+	}
+	defer func() { <-f.slots }()
+
+	return f.fetchOnce(ctx, imageURL)
+}
+
+func (f *Fetcher) fetchOnce(ctx context.Context, imageURL string) ([]byte, error) {
+	// Timeout <= 0 - без таймаута
+	if f.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, f.cfg.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "image/*")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &statusError{code: resp.StatusCode}
+	}
+	if resp.ContentLength > f.cfg.MaxBytes {
+		return nil, fmt.Errorf("%w: %d bytes", ErrTooLarge, resp.ContentLength)
+	}
+	declared, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if declared != "" && !isImageType(declared) {
+		return nil, fmt.Errorf("%w: %s", ErrNotImage, declared)
+	}
+
+	buf := new(bytes.Buffer)
+	n, err := io.Copy(buf, io.LimitReader(resp.Body, f.cfg.MaxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image data: %w", err)
+	}
+	if n > f.cfg.MaxBytes {
+		return nil, fmt.Errorf("%w: more than %d bytes", ErrTooLarge, f.cfg.MaxBytes)
+	}
+
+	// Заголовку верим не всегда: страница ошибки может прийти с image/jpeg.
+	// Форматы, которые DetectContentType не знает (AVIF, HEIC), пропускаем по заголовку
+	sniffed := http.DetectContentType(buf.Bytes())
+	if !isImageType(sniffed) && !(sniffed == "application/octet-stream" && isImageType(declared)) {
+		return nil, fmt.Errorf("%w: detected %s", ErrNotImage, sniffed)
+	}
+	return buf.Bytes(), nil
+}
+
+// SaveFunc сохраняет скачанное изображение под ключом и возвращает ключ объекта
+type SaveFunc func(ctx context.Context, data []byte, key string) (string, error)
+
+// SaveAll скачивает изображения параллельно и сохраняет их через save. Одновременных
+// загрузок не больше cfg.Workers на весь процесс, см. Fetcher.slots.
+// Если задан processor, вместо оригинала сохраняются варианты под ключами <key>/<размер>.
+// Результаты возвращаются в порядке images, progress вызывается после каждого изображения
+func (f *Fetcher) SaveAll(ctx context.Context, images []repo.ImageSource, save SaveFunc, progress func(done, total int)) []repo.SavedImage {
+	results := make([]repo.SavedImage, len(images))
+	if len(images) == 0 {
+		return results
+	}
+
+	indexes := make(chan int)
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		done int
+	)
+	for range min(max(f.cfg.Workers, 1), len(images)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = f.saveOne(ctx, images[i], save)
+
+				mu.Lock()
+				done++
+				if progress != nil {
+					progress(done, len(images))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range images {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
+
+func (f *Fetcher) saveOne(ctx context.Context, image repo.ImageSource, save SaveFunc) repo.SavedImage {
+	if err := ctx.Err(); err != nil {
+		return repo.SavedImage{Err: err}
+	}
+
+	data, err := f.Fetch(ctx, image.URL)
+	if err != nil {
+		return repo.SavedImage{Err: err}
+	}
+
+	return f.Save(ctx, data, image.Key, save)
+}
+
+// Save сохраняет уже полученное изображение: варианты размеров, если задан processor,
+// иначе оригинал. Используется и для файлов, загруженных админом напрямую
+func (f *Fetcher) Save(ctx context.Context, data []byte, key string, save SaveFunc) repo.SavedImage {
+	if f.processor == nil {
+		objectKey, err := save(ctx, data, key)
+		if err != nil {
+			return repo.SavedImage{Err: fmt.Errorf("failed to save image: %w", err)}
+		}
+		return repo.SavedImage{Key: objectKey}
+	}
+
+	variants, err := f.processor.Process(ctx, data)
+	if err != nil {
+		return repo.SavedImage{Err: fmt.Errorf("failed to process image: %w", err)}
+	}
+
+	saved := repo.SavedImage{Variants: make([]domain.PhotoVariant, 0, len(variants))}
+	for _, v := range variants {
+		objectKey, err := save(ctx, v.Data, fmt.Sprintf("%s/%s", key, v.Name))
+		if err != nil {
+			return repo.SavedImage{Err: fmt.Errorf("failed to save %s %s variant: %w", v.Name, v.Format, err)}
+		}
+		saved.Variants = append(saved.Variants, domain.PhotoVariant{
+			Name:   v.Name,
+			Format: v.Format,
+			Key:    objectKey,
+			Width:  v.Width,
+			Height: v.Height,
+		})
+		// Основной файл - самый крупный JPEG, его понимает любой клиент
+		if v.Name == domain.PhotoVariantFull && v.Format == domain.PhotoFormatJPEG {
+			saved.Key = objectKey
+		}
+	}
+	return saved
+}
+
+// isTransient сообщает, есть ли смысл повторить запрос
+func isTransient(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.code == http.StatusTooManyRequests || se.code >= 500
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+func isImageType(contentType string) bool {
+	return strings.HasPrefix(contentType, "image/")
+}
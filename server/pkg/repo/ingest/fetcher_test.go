@@ -0,0 +1,138 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shampsdev/go-telegram-template/pkg/config"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+)
+
+// png - заголовок PNG, по нему DetectContentType узнает изображение
+var png = []byte("\x89PNG\x0D\x0A\x1A\x0A....")
+
+func TestIsTransient(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want bool
+	}{
+		{name: "429", err: &statusError{code: http.StatusTooManyRequests}, want: true},
+		{name: "503", err: fmt.Errorf("wrapped: %w", &statusError{code: http.StatusServiceUnavailable}), want: true},
+		{name: "404", err: &statusError{code: http.StatusNotFound}},
+		{name: "403", err: &statusError{code: http.StatusForbidden}},
+		{name: "deadline", err: context.DeadlineExceeded, want: true},
+		{name: "network", err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}, want: true},
+		{name: "unexpected eof", err: io.ErrUnexpectedEOF, want: true},
+		{name: "too large", err: ErrTooLarge},
+		{name: "not image", err: ErrNotImage},
+		{name: "canceled context", ctx: canceled, err: &statusError{code: http.StatusServiceUnavailable}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			if got := isTransient(ctx, tt.err); got != tt.want {
+				t.Errorf("isTransient(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchRetriesTransientErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write(png)
+	}))
+	defer srv.Close()
+
+	f := NewFetcher(config.IngestConfig{Workers: 1, MaxBytes: 1 << 20, Retries: 2, RetryBackoff: time.Millisecond}, nil)
+	if _, err := f.Fetch(context.Background(), srv.URL); err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("calls = %d, want 3", calls.Load())
+	}
+}
+
+func TestFetchZeroTimeoutDisablesTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		_, _ = w.Write(png)
+	}))
+	defer srv.Close()
+
+	f := NewFetcher(config.IngestConfig{Workers: 1, Timeout: 0, MaxBytes: 1 << 20}, nil)
+	if _, err := f.Fetch(context.Background(), srv.URL); err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+}
+
+func TestSaveAllSharesWorkerPool(t *testing.T) {
+	const workers = 2
+	var active, peak atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		n := active.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		active.Add(-1)
+		_, _ = w.Write(png)
+	}))
+	defer srv.Close()
+
+	f := NewFetcher(config.IngestConfig{Workers: workers, Timeout: time.Second, MaxBytes: 1 << 20}, nil)
+	save := func(_ context.Context, _ []byte, key string) (string, error) { return key, nil }
+
+	// Несколько импортов и одиночные загрузки одновременно
+	images := make([]repo.ImageSource, 4)
+	for i := range images {
+		images[i] = repo.ImageSource{URL: srv.URL, Key: fmt.Sprint(i)}
+	}
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for _, saved := range f.SaveAll(context.Background(), images, save, nil) {
+				if saved.Err != nil {
+					t.Errorf("SaveAll: %v", saved.Err)
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := f.Fetch(context.Background(), srv.URL); err != nil {
+				t.Errorf("Fetch: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak.Load() > workers {
+		t.Errorf("peak concurrent downloads = %d, want at most %d", peak.Load(), workers)
+	}
+}
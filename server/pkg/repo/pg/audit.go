@@ -0,0 +1,81 @@
+package pg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+)
+
+type AuditRepo struct {
+	db   *pgxpool.Pool
+	psql sq.StatementBuilderType
+}
+
+func NewAuditRepo(db *pgxpool.Pool) *AuditRepo {
+	return &AuditRepo{
+		db:   db,
+		psql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (r *AuditRepo) Filter(ctx context.Context, filter *domain.FilterAuditEntry) ([]*domain.AuditEntry, error) {
+	s := r.psql.Select("id", "motorcycle_id", "actor_id", "action", "changes", "created_at").
+		From(`"motorcycle_audit"`).
+		OrderBy("created_at ASC", "id ASC")
+
+	if filter.MotorcycleID != nil {
+		s = s.Where(sq.Eq{"motorcycle_id": *filter.MotorcycleID})
+	}
+
+	sql, args, err := s.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*domain.AuditEntry{}
+	for rows.Next() {
+		var entry domain.AuditEntry
+		if err := rows.Scan(&entry.ID, &entry.MotorcycleID, &entry.ActorID, &entry.Action, &entry.Changes, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit entries: %w", err)
+	}
+	return entries, nil
+}
+
+// insertAuditEntry пишет запись журнала в транзакцию изменения мотоцикла.
+// created_at берется из clock_timestamp(), а не из now(): записи одной транзакции
+// должны идти в журнале в порядке вставки
+func insertAuditEntry(ctx context.Context, tx pgx.Tx, psql sq.StatementBuilderType, entry *domain.CreateAuditEntry) error {
+	changesJSON, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal changes: %w", err)
+	}
+
+	s, args, err := psql.Insert(`"motorcycle_audit"`).
+		Columns("motorcycle_id", "actor_id", "action", "changes", "created_at").
+		Values(entry.MotorcycleID, entry.ActorID, entry.Action, changesJSON, sq.Expr("clock_timestamp()")).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, s, args...); err != nil {
+		return fmt.Errorf("failed to create audit entry: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+)
+
+type BrandRepo struct {
+	db   *pgxpool.Pool
+	psql sq.StatementBuilderType
+}
+
+func NewBrandRepo(db *pgxpool.Pool) *BrandRepo {
+	return &BrandRepo{
+		db:   db,
+		psql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (r *BrandRepo) Create(ctx context.Context, brand *domain.CreateBrand) (string, error) {
+	s := r.psql.Insert(`"motorcycle_brand"`).
+		Columns("name", "aliases").
+		Values(brand.Name, nonNilAliases(brand.Aliases)).
+		Suffix("RETURNING id")
+
+	sql, args, err := s.ToSql()
+	if err != nil {
+		return "", fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	var id string
+	err = r.db.QueryRow(ctx, sql, args...).Scan(&id)
+	if err != nil {
+		return "", fmt.Errorf("failed to create brand: %w", uniqueViolation(err))
+	}
+	return id, nil
+}
+
+func (r *BrandRepo) Patch(ctx context.Context, id string, brand *domain.PatchBrand) error {
+	s := r.psql.Update(`"motorcycle_brand"`).
+		Where(sq.Eq{"id": id}).
+		Set("updated_at", time.Now())
+
+	if brand.Name != nil {
+		s = s.Set("name", *brand.Name)
+	}
+	if brand.Aliases != nil {
+		s = s.Set("aliases", nonNilAliases(*brand.Aliases))
+	}
+
+	return r.execOne(ctx, s, "failed to update brand")
+}
+
+func (r *BrandRepo) Filter(ctx context.Context, filter *domain.FilterBrand) ([]*domain.Brand, error) {
+	s := r.psql.Select("id", "name", "aliases", "created_at", "updated_at").
+		From(`"motorcycle_brand"`).
+		OrderBy("name")
+
+	if filter.ID != nil {
+		s = s.Where(sq.Eq{"id": *filter.ID})
+	}
+
+	sql, args, err := s.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute SQL: %w", err)
+	}
+	defer rows.Close()
+
+	brands := []*domain.Brand{}
+	brandMap := make(map[string]*domain.Brand)
+	for rows.Next() {
+		b := &domain.Brand{Models: []*domain.BrandModel{}}
+		err := rows.Scan(&b.ID, &b.Name, &b.Aliases, &b.CreatedAt, &b.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		brands = append(brands, b)
+		brandMap[b.ID] = b
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read brands: %w", err)
+	}
+	if len(brands) == 0 {
+		return brands, nil
+	}
+
+	ids := make([]string, 0, len(brands))
+	for _, b := range brands {
+		ids = append(ids, b.ID)
+	}
+
+	modelSQL, modelArgs, err := r.psql.Select("id", "brand_id", "name", "aliases", "created_at", "updated_at").
+		From(`"motorcycle_model"`).
+		Where(sq.Eq{"brand_id": ids}).
+		OrderBy("name").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build model SQL: %w", err)
+	}
+
+	modelRows, err := r.db.Query(ctx, modelSQL, modelArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query models: %w", err)
+	}
+	defer modelRows.Close()
+
+	for modelRows.Next() {
+		m := &domain.BrandModel{}
+		err := modelRows.Scan(&m.ID, &m.BrandID, &m.Name, &m.Aliases, &m.CreatedAt, &m.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan model row: %w", err)
+		}
+		if b, ok := brandMap[m.BrandID]; ok {
+			b.Models = append(b.Models, m)
+		}
+	}
+	if err := modelRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read models: %w", err)
+	}
+
+	return brands, nil
+}
+
+func (r *BrandRepo) Delete(ctx context.Context, id string) error {
+	s := r.psql.Delete(`"motorcycle_brand"`).
+		Where(sq.Eq{"id": id})
+
+	return r.execOne(ctx, s, "failed to delete brand")
+}
+
+func (r *BrandRepo) CreateModel(ctx context.Context, brandID string, model *domain.CreateBrandModel) (string, error) {
+	s := r.psql.Insert(`"motorcycle_model"`).
+		Columns("brand_id", "name", "aliases").
+		Values(brandID, model.Name, nonNilAliases(model.Aliases)).
+		Suffix("RETURNING id")
+
+	sql, args, err := s.ToSql()
+	if err != nil {
+		return "", fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	var id string
+	err = r.db.QueryRow(ctx, sql, args...).Scan(&id)
+	if err != nil {
+		return "", fmt.Errorf("failed to create model: %w", uniqueViolation(err))
+	}
+	return id, nil
+}
+
+func (r *BrandRepo) PatchModel(ctx context.Context, brandID, id string, model *domain.PatchBrandModel) error {
+	s := r.psql.Update(`"motorcycle_model"`).
+		Where(sq.Eq{"id": id, "brand_id": brandID}).
+		Set("updated_at", time.Now())
+
+	if model.Name != nil {
+		s = s.Set("name", *model.Name)
+	}
+	if model.Aliases != nil {
+		s = s.Set("aliases", nonNilAliases(*model.Aliases))
+	}
+
+	return r.execOne(ctx, s, "failed to update model")
+}
+
+func (r *BrandRepo) DeleteModel(ctx context.Context, brandID, id string) error {
+	s := r.psql.Delete(`"motorcycle_model"`).
+		Where(sq.Eq{"id": id, "brand_id": brandID})
+
+	return r.execOne(ctx, s, "failed to delete model")
+}
+
+// execOne выполняет запрос, который должен затронуть ровно одну запись
+func (r *BrandRepo) execOne(ctx context.Context, s sq.Sqlizer, msg string) error {
+	sql, args, err := s.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	tag, err := r.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", msg, uniqueViolation(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func nonNilAliases(aliases []string) []string {
+	if aliases == nil {
+		return []string{}
+	}
+	return aliases
+}
+
+// uniqueViolation превращает нарушение уникального индекса в repo.ErrAlreadyExists
+func uniqueViolation(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return repo.ErrAlreadyExists
+	}
+	return err
+}
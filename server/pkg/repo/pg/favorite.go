@@ -0,0 +1,117 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+)
+
+type FavoriteRepo struct {
+	db   *pgxpool.Pool
+	psql sq.StatementBuilderType
+}
+
+func NewFavoriteRepo(db *pgxpool.Pool) *FavoriteRepo {
+	return &FavoriteRepo{
+		db:   db,
+		psql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (r *FavoriteRepo) Add(ctx context.Context, userID, motorcycleID string) error {
+	s, args, err := r.psql.Insert(`"favorite"`).
+		Columns("user_id", "motorcycle_id").
+		Values(userID, motorcycleID).
+		Suffix("ON CONFLICT (user_id, motorcycle_id) DO NOTHING").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	if _, err := r.db.Exec(ctx, s, args...); err != nil {
+		return fmt.Errorf("failed to add favorite: %w", err)
+	}
+	return nil
+}
+
+func (r *FavoriteRepo) Remove(ctx context.Context, userID, motorcycleID string) error {
+	s, args, err := r.psql.Delete(`"favorite"`).
+		Where(sq.Eq{"user_id": userID, "motorcycle_id": motorcycleID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	if _, err := r.db.Exec(ctx, s, args...); err != nil {
+		return fmt.Errorf("failed to remove favorite: %w", err)
+	}
+	return nil
+}
+
+func (r *FavoriteRepo) Filter(ctx context.Context, userID string) ([]*domain.Favorite, error) {
+	s, args, err := r.psql.Select("motorcycle_id", "created_at").
+		From(`"favorite"`).
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("created_at DESC", "motorcycle_id ASC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, s, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query favorites: %w", err)
+	}
+	defer rows.Close()
+
+	favorites := []*domain.Favorite{}
+	for rows.Next() {
+		var favorite domain.Favorite
+		if err := rows.Scan(&favorite.MotorcycleID, &favorite.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan favorite: %w", err)
+		}
+		favorites = append(favorites, &favorite)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read favorites: %w", err)
+	}
+	return favorites, nil
+}
+
+func (r *FavoriteRepo) Counts(ctx context.Context, motorcycleIDs []string) (map[string]int, error) {
+	counts := make(map[string]int, len(motorcycleIDs))
+	if len(motorcycleIDs) == 0 {
+		return counts, nil
+	}
+
+	s, args, err := r.psql.Select("motorcycle_id", "COUNT(*)").
+		From(`"favorite"`).
+		Where(sq.Eq{"motorcycle_id": motorcycleIDs}).
+		GroupBy("motorcycle_id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, s, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count favorites: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var count int
+		if err := rows.Scan(&id, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan favorite count: %w", err)
+		}
+		counts[id] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read favorite counts: %w", err)
+	}
+	return counts, nil
+}
@@ -0,0 +1,211 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+)
+
+var importJobColumns = []string{
+	"id", "source_url", "status", "stage", "photos_total", "photos_done", "attempts", "max_attempts",
+	"last_error", "motorcycle_id", "created_by", "run_at", "started_at", "finished_at", "created_at", "updated_at",
+}
+
+// claimImportJobSQL берет ближайшую задачу из очереди или задачу,
+// у которой истекла аренда (воркер упал, не закончив работу)
+const claimImportJobSQL = `
+UPDATE "import_job" SET
+    status = 'running',
+    attempts = attempts + 1,
+    locked_until = NOW() + make_interval(secs => $1),
+    started_at = COALESCE(started_at, NOW()),
+    updated_at = NOW()
+WHERE id = (
+    SELECT id FROM "import_job"
+    WHERE (status = 'queued' AND run_at <= NOW())
+       OR (status = 'running' AND locked_until < NOW())
+    ORDER BY run_at
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING `
+
+type ImportJobRepo struct {
+	db   *pgxpool.Pool
+	psql sq.StatementBuilderType
+}
+
+func NewImportJobRepo(db *pgxpool.Pool) *ImportJobRepo {
+	return &ImportJobRepo{
+		db:   db,
+		psql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (r *ImportJobRepo) Create(ctx context.Context, job *domain.CreateImportJob) (string, error) {
+	s := r.psql.Insert(`"import_job"`).
+		Columns("source_url", "max_attempts", "created_by").
+		Values(job.SourceURL, job.MaxAttempts, job.CreatedBy).
+		Suffix("RETURNING id")
+
+	sql, args, err := s.ToSql()
+	if err != nil {
+		return "", fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	var id string
+	err = r.db.QueryRow(ctx, sql, args...).Scan(&id)
+	if err != nil {
+		return "", fmt.Errorf("failed to create import job: %w", err)
+	}
+	return id, nil
+}
+
+func (r *ImportJobRepo) Filter(ctx context.Context, filter *domain.FilterImportJob) ([]*domain.ImportJob, error) {
+	s := r.psql.Select(importJobColumns...).
+		From(`"import_job"`).
+		OrderBy("created_at DESC", "id DESC")
+
+	if filter.ID != nil {
+		s = s.Where(sq.Eq{"id": *filter.ID})
+	}
+	if filter.Status != nil {
+		s = s.Where(sq.Eq{"status": *filter.Status})
+	}
+	if filter.Limit > 0 {
+		s = s.Limit(uint64(filter.Limit))
+	}
+
+	sql, args, err := s.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute SQL: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := []*domain.ImportJob{}
+	for rows.Next() {
+		job, err := scanImportJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read import jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+func (r *ImportJobRepo) Claim(ctx context.Context, lease time.Duration) (*domain.ImportJob, error) {
+	sql := claimImportJobSQL + strings.Join(importJobColumns, ", ")
+
+	job, err := scanImportJob(r.db.QueryRow(ctx, sql, lease.Seconds()))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repo.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (r *ImportJobRepo) Progress(ctx context.Context, id string, stage domain.ImportJobStage, done, total int, lease time.Duration) error {
+	s := r.psql.Update(`"import_job"`).
+		Where(sq.Eq{"id": id}).
+		Set("stage", stage).
+		Set("photos_done", done).
+		Set("photos_total", total).
+		Set("locked_until", sq.Expr("NOW() + make_interval(secs => ?)", lease.Seconds())).
+		Set("updated_at", sq.Expr("NOW()"))
+
+	return r.exec(ctx, s, "failed to update import job progress")
+}
+
+func (r *ImportJobRepo) Succeed(ctx context.Context, id, motorcycleID string) error {
+	s := r.psql.Update(`"import_job"`).
+		Where(sq.Eq{"id": id}).
+		Set("status", domain.ImportJobStatusSucceeded).
+		Set("motorcycle_id", motorcycleID).
+		Set("last_error", "").
+		Set("locked_until", nil).
+		Set("finished_at", sq.Expr("NOW()")).
+		Set("updated_at", sq.Expr("NOW()"))
+
+	return r.exec(ctx, s, "failed to complete import job")
+}
+
+func (r *ImportJobRepo) Retry(ctx context.Context, id, lastError string, delay time.Duration) error {
+	s := r.psql.Update(`"import_job"`).
+		Where(sq.Eq{"id": id}).
+		Set("status", domain.ImportJobStatusQueued).
+		Set("last_error", lastError).
+		Set("locked_until", nil).
+		Set("run_at", sq.Expr("NOW() + make_interval(secs => ?)", delay.Seconds())).
+		Set("updated_at", sq.Expr("NOW()"))
+
+	return r.exec(ctx, s, "failed to reschedule import job")
+}
+
+func (r *ImportJobRepo) Fail(ctx context.Context, id, lastError string) error {
+	s := r.psql.Update(`"import_job"`).
+		Where(sq.Eq{"id": id}).
+		Set("status", domain.ImportJobStatusFailed).
+		Set("last_error", lastError).
+		Set("locked_until", nil).
+		Set("finished_at", sq.Expr("NOW()")).
+		Set("updated_at", sq.Expr("NOW()"))
+
+	return r.exec(ctx, s, "failed to fail import job")
+}
+
+func (r *ImportJobRepo) exec(ctx context.Context, s sq.UpdateBuilder, msg string) error {
+	sql, args, err := s.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	_, err = r.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	return nil
+}
+
+func scanImportJob(row pgx.Row) (*domain.ImportJob, error) {
+	var job domain.ImportJob
+	err := row.Scan(
+		&job.ID,
+		&job.SourceURL,
+		&job.Status,
+		&job.Stage,
+		&job.PhotosTotal,
+		&job.PhotosDone,
+		&job.Attempts,
+		&job.MaxAttempts,
+		&job.LastError,
+		&job.MotorcycleID,
+		&job.CreatedBy,
+		&job.RunAt,
+		&job.StartedAt,
+		&job.FinishedAt,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan import job: %w", err)
+	}
+	return &job, nil
+}
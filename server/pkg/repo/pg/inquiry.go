@@ -0,0 +1,124 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+)
+
+type InquiryRepo struct {
+	db   *pgxpool.Pool
+	psql sq.StatementBuilderType
+}
+
+func NewInquiryRepo(db *pgxpool.Pool) *InquiryRepo {
+	return &InquiryRepo{
+		db:   db,
+		psql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (r *InquiryRepo) Create(ctx context.Context, inquiry *domain.CreateInquiry, notify func(id string) []*domain.CreateNotification) (string, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	s, args, err := r.psql.Insert(`"inquiry"`).
+		Columns("motorcycle_id", "user_id", "text").
+		Values(inquiry.MotorcycleID, inquiry.UserID, inquiry.Text).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return "", fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	var id string
+	if err := tx.QueryRow(ctx, s, args...).Scan(&id); err != nil {
+		return "", fmt.Errorf("failed to create inquiry: %w", err)
+	}
+	if err := insertNotifications(ctx, tx, r.psql, notify(id)); err != nil {
+		return "", err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return id, nil
+}
+
+func (r *InquiryRepo) Filter(ctx context.Context, filter *domain.FilterInquiry) ([]*domain.Inquiry, error) {
+	s := r.psql.Select("id", "motorcycle_id", "user_id", "text", "answer", "answered_by", "answered_at", "created_at").
+		From(`"inquiry"`).
+		OrderBy("created_at DESC", "id DESC")
+
+	if filter.ID != nil {
+		s = s.Where(sq.Eq{"id": *filter.ID})
+	}
+	if filter.MotorcycleID != nil {
+		s = s.Where(sq.Eq{"motorcycle_id": *filter.MotorcycleID})
+	}
+	if filter.UserID != nil {
+		s = s.Where(sq.Eq{"user_id": *filter.UserID})
+	}
+
+	sql, args, err := s.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query inquiries: %w", err)
+	}
+	defer rows.Close()
+
+	inquiries := []*domain.Inquiry{}
+	for rows.Next() {
+		var inquiry domain.Inquiry
+		err := rows.Scan(
+			&inquiry.ID,
+			&inquiry.MotorcycleID,
+			&inquiry.UserID,
+			&inquiry.Text,
+			&inquiry.Answer,
+			&inquiry.AnsweredBy,
+			&inquiry.AnsweredAt,
+			&inquiry.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan inquiry: %w", err)
+		}
+		inquiries = append(inquiries, &inquiry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read inquiries: %w", err)
+	}
+	return inquiries, nil
+}
+
+func (r *InquiryRepo) Answer(ctx context.Context, id, answeredBy, answer string) error {
+	s, args, err := r.psql.Update(`"inquiry"`).
+		Set("answer", answer).
+		Set("answered_by", answeredBy).
+		Set("answered_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	tag, err := r.db.Exec(ctx, s, args...)
+	if err != nil {
+		return fmt.Errorf("failed to answer inquiry: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
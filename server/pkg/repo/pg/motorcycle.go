@@ -9,6 +9,7 @@ import (
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
 )
 
 type MotorcycleRepo struct {
@@ -23,7 +24,7 @@ func NewMotorcycleRepo(db *pgxpool.Pool) *MotorcycleRepo {
 	}
 }
 
-func (r *MotorcycleRepo) Create(ctx context.Context, motorcycle *domain.CreateMotorcycle) (string, error) {
+func (r *MotorcycleRepo) Create(ctx context.Context, motorcycle *domain.CreateMotorcycle, audit repo.AuditFunc) (string, error) {
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return "", fmt.Errorf("failed to begin transaction: %w", err)
@@ -41,8 +42,8 @@ func (r *MotorcycleRepo) Create(ctx context.Context, motorcycle *domain.CreateMo
 	}
 	
 	s := r.psql.Insert(`"motorcycle"`).
-		Columns("title", "price", "currency", "data", "status", "source_url").
-		Values(motorcycle.Title, motorcycle.Price, motorcycle.Currency, dataJSON, motorcycle.Status, motorcycle.SourceURL).
+		Columns("title", "price", "currency", "year", "brand", "model", "brand_id", "model_id", "mileage_km", "engine_cc", "data", "status", "source_url", "import_job_id").
+		Values(motorcycle.Title, motorcycle.Price, motorcycle.Currency, motorcycle.Year, motorcycle.Brand, motorcycle.Model, motorcycle.BrandID, motorcycle.ModelID, motorcycle.MileageKm, motorcycle.EngineCC, dataJSON, motorcycle.Status, motorcycle.SourceURL, motorcycle.ImportJobID).
 		Suffix("RETURNING id")
 
 	sql, args, err := s.ToSql()
@@ -53,13 +54,18 @@ func (r *MotorcycleRepo) Create(ctx context.Context, motorcycle *domain.CreateMo
 	var id string
 	err = tx.QueryRow(ctx, sql, args...).Scan(&id)
 	if err != nil {
-		return "", fmt.Errorf("failed to create motorcycle: %w", err)
+		return "", fmt.Errorf("failed to create motorcycle: %w", uniqueViolation(err))
+	}
+
+	// История статусов начинается с создания
+	if err := r.insertStatusChange(ctx, tx, id, nil, motorcycle.Status, nil, time.Now()); err != nil {
+		return "", err
 	}
 
 	// Создаем фотографии
 	for i, photoURL := range motorcycle.PhotoURLs {
 		photoSQL := r.psql.Insert(`"motorcycle_photo"`).
-			Columns("motorcycle_id", "s3_url", "\"order\"").
+			Columns("motorcycle_id", "object_key", "\"order\"").
 			Values(id, photoURL, i).
 			Suffix("RETURNING id")
 
@@ -75,6 +81,16 @@ func (r *MotorcycleRepo) Create(ctx context.Context, motorcycle *domain.CreateMo
 		}
 	}
 
+	if audit != nil {
+		created, err := r.lockMotorcycle(ctx, tx, id)
+		if err != nil {
+			return "", err
+		}
+		if err := r.writeAudit(ctx, tx, audit, nil, created); err != nil {
+			return "", err
+		}
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return "", fmt.Errorf("failed to commit transaction: %w", err)
 	}
@@ -82,10 +98,27 @@ func (r *MotorcycleRepo) Create(ctx context.Context, motorcycle *domain.CreateMo
 	return id, nil
 }
 
-func (r *MotorcycleRepo) Patch(ctx context.Context, id string, motorcycle *domain.PatchMotorcycle) error {
+func (r *MotorcycleRepo) Patch(ctx context.Context, id string, motorcycle *domain.PatchMotorcycle, version int, changedBy *string, audit repo.AuditFunc) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Блокировка держит статус, от которого пишется переход в историю, до конца транзакции
+	before, err := r.lockMotorcycle(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if before.Version != version {
+		return repo.ErrConflict
+	}
+
+	now := time.Now()
 	s := r.psql.Update(`"motorcycle"`).
 		Where(sq.Eq{"id": id}).
-		Set("updated_at", time.Now())
+		Set("version", sq.Expr("version + 1")).
+		Set("updated_at", now)
 
 	if motorcycle.Title != nil {
 		s = s.Set("title", *motorcycle.Title)
@@ -96,171 +129,236 @@ func (r *MotorcycleRepo) Patch(ctx context.Context, id string, motorcycle *domai
 	if motorcycle.Currency != nil {
 		s = s.Set("currency", *motorcycle.Currency)
 	}
-	if motorcycle.Data != nil {
-		dataJSON, err := json.Marshal(motorcycle.Data)
+	if motorcycle.Year != nil {
+		s = s.Set("year", *motorcycle.Year)
+	}
+	if motorcycle.Brand != nil {
+		s = s.Set("brand", *motorcycle.Brand)
+	}
+	if motorcycle.Model != nil {
+		s = s.Set("model", *motorcycle.Model)
+	}
+	if motorcycle.BrandID != nil {
+		s = s.Set("brand_id", nullIfEmpty(*motorcycle.BrandID))
+	}
+	if motorcycle.ModelID != nil {
+		s = s.Set("model_id", nullIfEmpty(*motorcycle.ModelID))
+	}
+	if motorcycle.MileageKm != nil {
+		s = s.Set("mileage_km", *motorcycle.MileageKm)
+	}
+	if motorcycle.EngineCC != nil {
+		s = s.Set("engine_cc", *motorcycle.EngineCC)
+	}
+	if len(motorcycle.Data) > 0 {
+		// Merge patch: новые значения накладываются на текущие, ключи с null удаляются
+		set, removed := motorcycle.Data.Split()
+		setJSON, err := json.Marshal(set)
 		if err != nil {
 			return fmt.Errorf("failed to marshal data: %w", err)
 		}
-		s = s.Set("data", dataJSON)
+		s = s.Set("data", sq.Expr("(COALESCE(data, '{}'::jsonb) || ?::jsonb) - ?::text[]", setJSON, removed))
 	}
 	if motorcycle.Status != nil {
 		s = s.Set("status", *motorcycle.Status)
 	}
 
-	sql, args, err := s.ToSql()
+	sql, args, err := s.Suffix(returningMotorcycle).ToSql()
 	if err != nil {
 		return fmt.Errorf("failed to build SQL: %w", err)
 	}
+	after, err := r.queryMotorcycle(ctx, tx, sql, args)
+	if err != nil {
+		return fmt.Errorf("failed to patch motorcycle: %w", err)
+	}
 
-	_, err = r.db.Exec(ctx, sql, args...)
-	return err
+	if after.Status != before.Status {
+		if err := r.insertStatusChange(ctx, tx, id, &before.Status, after.Status, changedBy, now); err != nil {
+			return err
+		}
+	}
+	if before.Status == domain.MotorcycleStatusReserved && after.Status != domain.MotorcycleStatusReserved {
+		if err := closeReservations(ctx, tx, r.psql, id); err != nil {
+			return err
+		}
+	}
+	if err := r.writeAudit(ctx, tx, audit, before, after); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
 }
 
 func (r *MotorcycleRepo) Filter(ctx context.Context, filter *domain.FilterMotorcycle) ([]*domain.Motorcycle, error) {
-	s := r.psql.Select("m.id", "m.title", "m.price", "m.currency", "m.data", "m.status", "m.source_url", "m.created_at", "m.updated_at").
-		From(`"motorcycle" m`)
+	motorcycles, _, err := r.FilterPage(ctx, filter)
+	return motorcycles, err
+}
 
-	if filter.ID != nil {
-		s = s.Where(sq.Eq{"m.id": *filter.ID})
-	}
-	if filter.Status != nil {
-		s = s.Where(sq.Eq{"m.status": *filter.Status})
-	}
-	if filter.Title != nil {
-		// Используем ILIKE для поиска без учета регистра
-		s = s.Where(sq.Expr("LOWER(m.title) LIKE LOWER(?)", "%"+*filter.Title+"%"))
+// FilterPage возвращает не больше filter.Limit мотоциклов, начиная с filter.Cursor,
+// и курсор следующей страницы (пустой, если страниц больше нет)
+func (r *MotorcycleRepo) FilterPage(ctx context.Context, filter *domain.FilterMotorcycle) ([]*domain.Motorcycle, string, error) {
+	sortColumns, err := motorcycleSortColumns(filter.Sort, filter.Order)
+	if err != nil {
+		return nil, "", err
 	}
-	if filter.MinPrice != nil {
-		s = s.Where(sq.GtOrEq{"m.price": *filter.MinPrice})
+
+	s := r.psql.Select(prefixColumns("m", motorcycleColumns)...).
+		From(`"motorcycle" m`)
+	s = applyMotorcycleFilter(s, filter)
+	s = applyMotorcycleSort(s, sortColumns)
+
+	if filter.Cursor != "" {
+		keys, err := decodeMotorcycleCursor(filter.Cursor, filter.Sort, filter.Order, len(sortColumns))
+		if err != nil {
+			return nil, "", err
+		}
+		s = s.Where(keysetCondition(sortColumns, keys))
 	}
-	if filter.MaxPrice != nil {
-		s = s.Where(sq.LtOrEq{"m.price": *filter.MaxPrice})
+	if filter.Limit > 0 {
+		// Берем на одну запись больше, чтобы понять, есть ли следующая страница
+		s = s.Limit(uint64(filter.Limit) + 1)
 	}
 
-	// Сортировка: available -> reserved -> sold
-	s = s.OrderBy(`
-		CASE 
-			WHEN m.status = 'available' THEN 1
-			WHEN m.status = 'reserved' THEN 2
-			WHEN m.status = 'sold' THEN 3
-			ELSE 4
-		END,
-		m.created_at DESC
-	`)
-
 	sql, args, err := s.ToSql()
 	if err != nil {
-		return nil, fmt.Errorf("failed to build SQL: %w", err)
+		return nil, "", fmt.Errorf("failed to build SQL: %w", err)
 	}
 
 	rows, err := r.db.Query(ctx, sql, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute SQL: %w", err)
+		return nil, "", fmt.Errorf("failed to execute SQL: %w", err)
 	}
 	defer rows.Close()
 
 	motorcycles := []*domain.Motorcycle{}
-	motorcycleMap := make(map[string]*domain.Motorcycle)
+	sortKeys := [][]string{}
 
 	for rows.Next() {
-		var m domain.Motorcycle
-		var dataJSON []byte
-		err := rows.Scan(
-			&m.ID,
-			&m.Title,
-			&m.Price,
-			&m.Currency,
-			&dataJSON,
-			&m.Status,
-			&m.SourceURL,
-			&m.CreatedAt,
-			&m.UpdatedAt,
-		)
+		var row motorcycleRow
+		keys := make([]string, len(sortColumns))
+		dest := row.dest()
+		for i := range keys {
+			dest = append(dest, &keys[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, "", fmt.Errorf("failed to scan row: %w", err)
+		}
+		m, err := row.decode()
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan row: %w", err)
+			return nil, "", err
 		}
-		
-		// Десериализуем JSON данные
-		if len(dataJSON) > 0 {
-			var data domain.MotorcycleData
-			if err := json.Unmarshal(dataJSON, &data); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal data: %w", err)
-			}
-			m.Data = &data
+
+		motorcycles = append(motorcycles, m)
+		sortKeys = append(sortKeys, keys)
+	}
+
+	nextCursor := ""
+	if filter.Limit > 0 && len(motorcycles) > filter.Limit {
+		motorcycles = motorcycles[:filter.Limit]
+		nextCursor, err = encodeMotorcycleCursor(filter.Sort, filter.Order, sortKeys[filter.Limit-1])
+		if err != nil {
+			return nil, "", err
 		}
-		
-		motorcycles = append(motorcycles, &m)
-		motorcycleMap[m.ID] = &m
 	}
 
 	// Загружаем фотографии, если нужно
-	if filter.IncludePhotos && len(motorcycles) > 0 {
-		motorcycleIDs := make([]string, 0, len(motorcycles))
-		for _, m := range motorcycles {
-			motorcycleIDs = append(motorcycleIDs, m.ID)
+	if filter.IncludePhotos {
+		if err := r.loadPhotos(ctx, r.db, motorcycles); err != nil {
+			return nil, "", err
 		}
+	}
 
-		photoSQL := r.psql.Select("id", "motorcycle_id", "s3_url", "\"order\"", "created_at").
-			From(`"motorcycle_photo"`).
-			Where(sq.Eq{"motorcycle_id": motorcycleIDs}).
-			OrderBy("\"order\" ASC")
+	return motorcycles, nextCursor, nil
+}
 
-		photoSQLStr, photoArgs, err := photoSQL.ToSql()
-		if err != nil {
-			return nil, fmt.Errorf("failed to build photo SQL: %w", err)
-		}
+func (r *MotorcycleRepo) Delete(ctx context.Context, id string, audit repo.AuditFunc) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
 
-		photoRows, err := r.db.Query(ctx, photoSQLStr, photoArgs...)
-		if err != nil {
-			return nil, fmt.Errorf("failed to query photos: %w", err)
-		}
-		defer photoRows.Close()
-
-		for photoRows.Next() {
-			var photo domain.MotorcyclePhoto
-			err := photoRows.Scan(
-				&photo.ID,
-				&photo.MotorcycleID,
-				&photo.S3URL,
-				&photo.Order,
-				&photo.CreatedAt,
-			)
-			if err != nil {
-				return nil, fmt.Errorf("failed to scan photo row: %w", err)
-			}
-
-			if m, ok := motorcycleMap[photo.MotorcycleID]; ok {
-				if m.Photos == nil {
-					m.Photos = []*domain.MotorcyclePhoto{}
-				}
-				m.Photos = append(m.Photos, &photo)
-			}
-		}
+	before, err := r.lockMotorcycle(ctx, tx, id)
+	if err != nil {
+		return err
 	}
 
-	return motorcycles, nil
+	sql, args, err := r.psql.Delete(`"motorcycle"`).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL: %w", err)
+	}
+	if _, err := tx.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("failed to delete motorcycle: %w", err)
+	}
+	if err := r.writeAudit(ctx, tx, audit, before, nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
 }
 
-func (r *MotorcycleRepo) Delete(ctx context.Context, id string) error {
-	s := r.psql.Delete(`"motorcycle"`).
-		Where(sq.Eq{"id": id})
+func (r *MotorcycleRepo) SetArchived(ctx context.Context, id string, archived bool, audit repo.AuditFunc) error {
+	var archivedAt any
+	if archived {
+		archivedAt = time.Now()
+	}
 
-	sql, args, err := s.ToSql()
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := r.lockMotorcycle(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	s, args, err := r.psql.Update(`"motorcycle"`).
+		Set("archived_at", archivedAt).
+		Set("version", sq.Expr("version + 1")).
+		Set("updated_at", time.Now()).
+		Where(sq.Eq{"id": id}).
+		Suffix(returningMotorcycle).
+		ToSql()
 	if err != nil {
 		return fmt.Errorf("failed to build SQL: %w", err)
 	}
+	after, err := r.queryMotorcycle(ctx, tx, s, args)
+	if err != nil {
+		return fmt.Errorf("failed to update archived_at: %w", err)
+	}
+	if err := r.writeAudit(ctx, tx, audit, before, after); err != nil {
+		return err
+	}
 
-	_, err = r.db.Exec(ctx, sql, args...)
-	return err
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
 }
 
-func (r *MotorcycleRepo) AddPhotos(ctx context.Context, motorcycleID string, photoURLs []string) error {
+func (r *MotorcycleRepo) AddPhotos(ctx context.Context, motorcycleID string, photos []*domain.CreateMotorcyclePhoto, audit repo.AuditFunc) error {
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback(ctx)
 
+	// Без блокировки две загрузки одновременно получили бы одинаковый MAX("order")
+	before, err := r.lockMotorcycle(ctx, tx, motorcycleID)
+	if err != nil {
+		return err
+	}
+
 	// Получаем текущий максимальный порядок фотографий
 	maxOrderSQL := r.psql.Select("COALESCE(MAX(\"order\"), -1)").
 		From(`"motorcycle_photo"`).
@@ -278,11 +376,16 @@ func (r *MotorcycleRepo) AddPhotos(ctx context.Context, motorcycleID string, pho
 	}
 
 	// Добавляем новые фотографии
-	for i, photoURL := range photoURLs {
+	for i, photo := range photos {
 		order := maxOrder + 1 + i
+		variantsJSON, err := photoVariantsJSON(photo.Variants)
+		if err != nil {
+			return err
+		}
+
 		photoSQL := r.psql.Insert(`"motorcycle_photo"`).
-			Columns("motorcycle_id", "s3_url", "\"order\"").
-			Values(motorcycleID, photoURL, order).
+			Columns("motorcycle_id", "object_key", "variants", "storage_key", "\"order\"").
+			Values(motorcycleID, photo.ObjectKey, variantsJSON, nullIfEmpty(photo.StorageKey), order).
 			Suffix("RETURNING id")
 
 		photoSQLStr, photoArgs, err := photoSQL.ToSql()
@@ -297,6 +400,13 @@ func (r *MotorcycleRepo) AddPhotos(ctx context.Context, motorcycleID string, pho
 		}
 	}
 
+	if err := r.bumpVersion(ctx, tx, motorcycleID); err != nil {
+		return err
+	}
+	if err := r.auditPhotos(ctx, tx, audit, before); err != nil {
+		return err
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
@@ -304,3 +414,10 @@ func (r *MotorcycleRepo) AddPhotos(ctx context.Context, motorcycleID string, pho
 	return nil
 }
 
+// nullIfEmpty позволяет сбросить ссылку пустой строкой в PATCH
+func nullIfEmpty(id string) *string {
+	if id == "" {
+		return nil
+	}
+	return &id
+}
@@ -0,0 +1,81 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+)
+
+// returningMotorcycle - суффикс UPDATE, возвращающий строку для queryMotorcycle
+var returningMotorcycle = "RETURNING " + strings.Join(motorcycleColumns, ", ")
+
+// lockMotorcycle блокирует мотоцикл до конца транзакции и возвращает его с фотографиями.
+// Блокировка выстраивает в очередь параллельные изменения одного мотоцикла (альбом
+// из бота приходит отдельными сообщениями), а снимок - состояние "до" для журнала
+func (r *MotorcycleRepo) lockMotorcycle(ctx context.Context, tx pgx.Tx, id string) (*domain.Motorcycle, error) {
+	s, args, err := r.psql.Select(motorcycleColumns...).
+		From(`"motorcycle"`).
+		Where(sq.Eq{"id": id}).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL: %w", err)
+	}
+	return r.queryMotorcycle(ctx, tx, s, args)
+}
+
+// queryMotorcycle читает в транзакции одну строку motorcycleColumns (SELECT или
+// UPDATE ... RETURNING) и фотографии мотоцикла
+func (r *MotorcycleRepo) queryMotorcycle(ctx context.Context, tx pgx.Tx, s string, args []any) (*domain.Motorcycle, error) {
+	var row motorcycleRow
+	err := tx.QueryRow(ctx, s, args...).Scan(row.dest()...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repo.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query motorcycle: %w", err)
+	}
+	motorcycle, err := row.decode()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadPhotos(ctx, tx, []*domain.Motorcycle{motorcycle}); err != nil {
+		return nil, err
+	}
+	return motorcycle, nil
+}
+
+// auditPhotos пишет в журнал изменение галереи: мотоцикл перечитывается в той же транзакции
+func (r *MotorcycleRepo) auditPhotos(ctx context.Context, tx pgx.Tx, audit repo.AuditFunc, before *domain.Motorcycle) error {
+	if audit == nil {
+		return nil
+	}
+	after, err := r.lockMotorcycle(ctx, tx, before.ID)
+	if err != nil {
+		return err
+	}
+	return r.writeAudit(ctx, tx, audit, before, after)
+}
+
+// writeAudit пишет в транзакцию записи журнала, которые audit строит по before и after
+func (r *MotorcycleRepo) writeAudit(ctx context.Context, tx pgx.Tx, audit repo.AuditFunc, before, after *domain.Motorcycle) error {
+	if audit == nil {
+		return nil
+	}
+	entries, err := audit(before, after)
+	if err != nil {
+		return fmt.Errorf("failed to build audit entries: %w", err)
+	}
+	for _, entry := range entries {
+		if err := insertAuditEntry(ctx, tx, r.psql, entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+)
+
+// claimMotorcycleEventsSQL берет необработанные события, аренда которых свободна или истекла
+const claimMotorcycleEventsSQL = `
+UPDATE "motorcycle_event" SET
+    attempts = attempts + 1,
+    locked_until = NOW() + make_interval(secs => $1)
+WHERE id IN (
+    SELECT id FROM "motorcycle_event"
+    WHERE processed_at IS NULL
+      AND attempts < $2
+      AND (locked_until IS NULL OR locked_until < NOW())
+    ORDER BY created_at
+    LIMIT $3
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, motorcycle_id, kind, old_price, price, attempts, created_at`
+
+type MotorcycleEventRepo struct {
+	db   *pgxpool.Pool
+	psql sq.StatementBuilderType
+}
+
+func NewMotorcycleEventRepo(db *pgxpool.Pool) *MotorcycleEventRepo {
+	return &MotorcycleEventRepo{
+		db:   db,
+		psql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (r *MotorcycleEventRepo) Create(ctx context.Context, event *domain.CreateMotorcycleEvent) error {
+	s, args, err := r.psql.Insert(`"motorcycle_event"`).
+		Columns("motorcycle_id", "kind", "old_price", "price").
+		Values(event.MotorcycleID, event.Kind, event.OldPrice, event.Price).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	if _, err := r.db.Exec(ctx, s, args...); err != nil {
+		return fmt.Errorf("failed to create motorcycle event: %w", err)
+	}
+	return nil
+}
+
+func (r *MotorcycleEventRepo) Claim(ctx context.Context, limit, maxAttempts int, lease time.Duration) ([]*domain.MotorcycleEvent, error) {
+	rows, err := r.db.Query(ctx, claimMotorcycleEventsSQL, lease.Seconds(), maxAttempts, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim motorcycle events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []*domain.MotorcycleEvent{}
+	for rows.Next() {
+		var event domain.MotorcycleEvent
+		err := rows.Scan(
+			&event.ID,
+			&event.MotorcycleID,
+			&event.Kind,
+			&event.OldPrice,
+			&event.Price,
+			&event.Attempts,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan motorcycle event: %w", err)
+		}
+		events = append(events, &event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read motorcycle events: %w", err)
+	}
+	return events, nil
+}
+
+func (r *MotorcycleEventRepo) Done(ctx context.Context, id string, attempt int, notifications []*domain.CreateNotification) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// attempts меняется при каждом Claim: если аренда истекла и событие взял другой
+	// матчер, его уведомления уже не ставим
+	s, args, err := r.psql.Update(`"motorcycle_event"`).
+		Set("processed_at", sq.Expr("NOW()")).
+		Set("locked_until", nil).
+		Where(sq.Eq{"id": id, "attempts": attempt, "processed_at": nil}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL: %w", err)
+	}
+	tag, err := tx.Exec(ctx, s, args...)
+	if err != nil {
+		return fmt.Errorf("failed to complete motorcycle event: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repo.ErrConflict
+	}
+	if err := insertNotifications(ctx, tx, r.psql, notifications); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package pg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+)
+
+func (r *MotorcycleRepo) DeletePhoto(ctx context.Context, motorcycleID, photoID string, audit repo.AuditFunc) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := r.lockMotorcycle(ctx, tx, motorcycleID)
+	if err != nil {
+		return err
+	}
+
+	deleteSQL, args, err := r.psql.Delete(`"motorcycle_photo"`).
+		Where(sq.Eq{"id": photoID, "motorcycle_id": motorcycleID}).
+		Suffix(`RETURNING "order"`).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	var order int
+	err = tx.QueryRow(ctx, deleteSQL, args...).Scan(&order)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repo.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete photo: %w", err)
+	}
+
+	// Сдвигаем следующие фотографии на освободившееся место
+	shiftSQL, args, err := r.psql.Update(`"motorcycle_photo"`).
+		Set(`"order"`, sq.Expr(`"order" - 1`)).
+		Where(sq.Eq{"motorcycle_id": motorcycleID}).
+		Where(sq.Gt{`"order"`: order}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL: %w", err)
+	}
+	if _, err := tx.Exec(ctx, shiftSQL, args...); err != nil {
+		return fmt.Errorf("failed to shift photos: %w", err)
+	}
+	if err := r.bumpVersion(ctx, tx, motorcycleID); err != nil {
+		return err
+	}
+	if err := r.auditPhotos(ctx, tx, audit, before); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *MotorcycleRepo) ReorderPhotos(ctx context.Context, motorcycleID string, reorder func(photoIDs []string) ([]string, error), audit repo.AuditFunc) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := r.lockMotorcycle(ctx, tx, motorcycleID)
+	if err != nil {
+		return err
+	}
+	current := make([]string, 0, len(before.Photos))
+	for _, photo := range before.Photos {
+		current = append(current, photo.ID)
+	}
+	photoIDs, err := reorder(current)
+	if err != nil {
+		return err
+	}
+
+	for i, photoID := range photoIDs {
+		s, args, err := r.psql.Update(`"motorcycle_photo"`).
+			Set(`"order"`, i).
+			Where(sq.Eq{"id": photoID, "motorcycle_id": motorcycleID}).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build SQL: %w", err)
+		}
+
+		tag, err := tx.Exec(ctx, s, args...)
+		if err != nil {
+			return fmt.Errorf("failed to update photo order: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return repo.ErrNotFound
+		}
+	}
+	if err := r.bumpVersion(ctx, tx, motorcycleID); err != nil {
+		return err
+	}
+	if err := r.auditPhotos(ctx, tx, audit, before); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *MotorcycleRepo) ReplacePhoto(ctx context.Context, motorcycleID, photoID string, photo *domain.CreateMotorcyclePhoto, audit repo.AuditFunc) error {
+	variantsJSON, err := photoVariantsJSON(photo.Variants)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := r.lockMotorcycle(ctx, tx, motorcycleID)
+	if err != nil {
+		return err
+	}
+
+	s, args, err := r.psql.Update(`"motorcycle_photo"`).
+		Set("object_key", photo.ObjectKey).
+		Set("variants", variantsJSON).
+		Set("storage_key", nullIfEmpty(photo.StorageKey)).
+		Where(sq.Eq{"id": photoID, "motorcycle_id": motorcycleID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, s, args...)
+	if err != nil {
+		return fmt.Errorf("failed to replace photo: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+	if err := r.bumpVersion(ctx, tx, motorcycleID); err != nil {
+		return err
+	}
+	if err := r.auditPhotos(ctx, tx, audit, before); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// bumpVersion увеличивает версию мотоцикла: изменение галереи тоже меняет его ETag
+func (r *MotorcycleRepo) bumpVersion(ctx context.Context, tx pgx.Tx, id string) error {
+	s, args, err := r.psql.Update(`"motorcycle"`).
+		Set("version", sq.Expr("version + 1")).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL: %w", err)
+	}
+	if _, err := tx.Exec(ctx, s, args...); err != nil {
+		return fmt.Errorf("failed to bump motorcycle version: %w", err)
+	}
+	return nil
+}
+
+// photoVariantRow - вариант фотографии в колонке variants: вместо URL хранится ключ
+type photoVariantRow struct {
+	Name   string `json:"name"`
+	Format string `json:"format"`
+	Key    string `json:"key"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// photoVariantsJSON сериализует варианты фотографии, nil сохраняется как пустой массив
+func photoVariantsJSON(variants []domain.PhotoVariant) ([]byte, error) {
+	rows := make([]photoVariantRow, 0, len(variants))
+	for _, v := range variants {
+		rows = append(rows, photoVariantRow{Name: v.Name, Format: v.Format, Key: v.Key, Width: v.Width, Height: v.Height})
+	}
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal photo variants: %w", err)
+	}
+	return data, nil
+}
+
+func photoVariantsFromRows(rows []photoVariantRow) []domain.PhotoVariant {
+	variants := make([]domain.PhotoVariant, 0, len(rows))
+	for _, row := range rows {
+		variants = append(variants, domain.PhotoVariant{Name: row.Name, Format: row.Format, Key: row.Key, Width: row.Width, Height: row.Height})
+	}
+	return variants
+}
@@ -0,0 +1,101 @@
+package pg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+)
+
+// motorcycleColumns - колонки мотоцикла в порядке motorcycleRow.dest
+var motorcycleColumns = []string{
+	"id", "title", "price", "currency", "year", "brand", "model", "brand_id", "model_id",
+	"mileage_km", "engine_cc", "data", "status", "source_url", "archived_at", "version",
+	"created_at", "updated_at",
+}
+
+func prefixColumns(alias string, columns []string) []string {
+	prefixed := make([]string, 0, len(columns))
+	for _, column := range columns {
+		prefixed = append(prefixed, alias+"."+column)
+	}
+	return prefixed
+}
+
+// motorcycleRow - строка motorcycleColumns, data разбирается в decode
+type motorcycleRow struct {
+	motorcycle domain.Motorcycle
+	data       []byte
+}
+
+func (row *motorcycleRow) dest() []any {
+	m := &row.motorcycle
+	return []any{
+		&m.ID, &m.Title, &m.Price, &m.Currency, &m.Year, &m.Brand, &m.Model, &m.BrandID, &m.ModelID,
+		&m.MileageKm, &m.EngineCC, &row.data, &m.Status, &m.SourceURL, &m.ArchivedAt, &m.Version,
+		&m.CreatedAt, &m.UpdatedAt,
+	}
+}
+
+func (row *motorcycleRow) decode() (*domain.Motorcycle, error) {
+	m := row.motorcycle
+	if len(row.data) > 0 {
+		var data domain.MotorcycleData
+		if err := json.Unmarshal(row.data, &data); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal data: %w", err)
+		}
+		m.Data = &data
+	}
+	return &m, nil
+}
+
+// loadPhotos заполняет фотографии мотоциклов в порядке галереи
+func (r *MotorcycleRepo) loadPhotos(ctx context.Context, q querier, motorcycles []*domain.Motorcycle) error {
+	if len(motorcycles) == 0 {
+		return nil
+	}
+	motorcycleMap := make(map[string]*domain.Motorcycle, len(motorcycles))
+	motorcycleIDs := make([]string, 0, len(motorcycles))
+	for _, m := range motorcycles {
+		motorcycleMap[m.ID] = m
+		motorcycleIDs = append(motorcycleIDs, m.ID)
+	}
+
+	s, args, err := r.psql.Select("id", "motorcycle_id", "object_key", "variants", "COALESCE(storage_key, '')", `"order"`, "created_at").
+		From(`"motorcycle_photo"`).
+		Where(sq.Eq{"motorcycle_id": motorcycleIDs}).
+		OrderBy(`"order" ASC`).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build photo SQL: %w", err)
+	}
+
+	rows, err := q.Query(ctx, s, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query photos: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var photo domain.MotorcyclePhoto
+		var variants []photoVariantRow
+		err := rows.Scan(&photo.ID, &photo.MotorcycleID, &photo.ObjectKey, &variants, &photo.StorageKey, &photo.Order, &photo.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to scan photo row: %w", err)
+		}
+		photo.Variants = photoVariantsFromRows(variants)
+
+		if m, ok := motorcycleMap[photo.MotorcycleID]; ok {
+			if m.Photos == nil {
+				m.Photos = []*domain.MotorcyclePhoto{}
+			}
+			m.Photos = append(m.Photos, &photo)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read photos: %w", err)
+	}
+	return nil
+}
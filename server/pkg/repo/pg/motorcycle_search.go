@@ -0,0 +1,177 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+)
+
+// Колонки для фильтров, фасетов и сортировки
+const (
+	volumeExpr  = `m.engine_cc`
+	mileageExpr = `m.mileage_km`
+	yearExpr    = `m.year`
+)
+
+// facet описывает группировку для подсчета фасета
+type facet struct {
+	expr string
+	// order задает порядок значений в ответе
+	order string
+}
+
+var (
+	brandFacet = facet{
+		expr:  `NULLIF(m.brand, '')`,
+		order: "count DESC, value",
+	}
+	volumeFacet = facet{
+		expr: `CASE
+			WHEN ` + volumeExpr + ` IS NULL THEN NULL
+			WHEN ` + volumeExpr + ` <= 125 THEN '0-125'
+			WHEN ` + volumeExpr + ` <= 250 THEN '126-250'
+			WHEN ` + volumeExpr + ` <= 400 THEN '251-400'
+			WHEN ` + volumeExpr + ` <= 750 THEN '401-750'
+			WHEN ` + volumeExpr + ` <= 1000 THEN '751-1000'
+			ELSE '1000+'
+		END`,
+		order: "MIN(" + volumeExpr + ")",
+	}
+	mileageFacet = facet{
+		expr: `CASE
+			WHEN ` + mileageExpr + ` IS NULL THEN NULL
+			WHEN ` + mileageExpr + ` < 10000 THEN '0-10000'
+			WHEN ` + mileageExpr + ` < 30000 THEN '10000-30000'
+			WHEN ` + mileageExpr + ` < 50000 THEN '30000-50000'
+			ELSE '50000+'
+		END`,
+		order: "MIN(" + mileageExpr + ")",
+	}
+	yearFacet = facet{
+		expr:  yearExpr + `::text`,
+		order: "value DESC",
+	}
+)
+
+func applyMotorcycleFilter(s sq.SelectBuilder, filter *domain.FilterMotorcycle) sq.SelectBuilder {
+	if filter.ID != nil {
+		s = s.Where(sq.Eq{"m.id": *filter.ID})
+	}
+	if filter.IDs != nil {
+		s = s.Where(sq.Eq{"m.id": filter.IDs})
+	}
+	if filter.Status != nil {
+		s = s.Where(sq.Eq{"m.status": *filter.Status})
+	}
+	if filter.ImportJobID != nil {
+		s = s.Where(sq.Eq{"m.import_job_id": *filter.ImportJobID})
+	}
+	if filter.Title != nil {
+		// Используем ILIKE для поиска без учета регистра
+		s = s.Where(sq.Expr("LOWER(m.title) LIKE LOWER(?)", "%"+*filter.Title+"%"))
+	}
+	if filter.Query != nil {
+		if query := toTSQuery(*filter.Query); query != "" {
+			s = s.Where(sq.Expr("m.search_vector @@ to_tsquery('simple', ?)", query))
+		}
+	}
+	if filter.Brand != nil {
+		s = s.Where(sq.Expr("LOWER(m.brand) = LOWER(?)", *filter.Brand))
+	}
+	if filter.MinPrice != nil {
+		s = s.Where(sq.GtOrEq{"m.price": *filter.MinPrice})
+	}
+	if filter.MaxPrice != nil {
+		s = s.Where(sq.LtOrEq{"m.price": *filter.MaxPrice})
+	}
+	if filter.MinVolume != nil {
+		s = s.Where(sq.Expr(volumeExpr+" >= ?", *filter.MinVolume))
+	}
+	if filter.MaxVolume != nil {
+		s = s.Where(sq.Expr(volumeExpr+" <= ?", *filter.MaxVolume))
+	}
+	if filter.MaxMileage != nil {
+		s = s.Where(sq.Expr(mileageExpr+" <= ?", *filter.MaxMileage))
+	}
+	if filter.MinYear != nil {
+		s = s.Where(sq.Expr(yearExpr+" >= ?", *filter.MinYear))
+	}
+	if filter.MaxYear != nil {
+		s = s.Where(sq.Expr(yearExpr+" <= ?", *filter.MaxYear))
+	}
+	if filter.Archived != nil {
+		if *filter.Archived {
+			s = s.Where(sq.NotEq{"m.archived_at": nil})
+		} else {
+			s = s.Where(sq.Eq{"m.archived_at": nil})
+		}
+	}
+	return s
+}
+
+// toTSQuery превращает пользовательский ввод в префиксный tsquery:
+// "honda cb4" -> "honda:* & cb4:*"
+func toTSQuery(query string) string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, w := range words {
+		words[i] = w + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+func (r *MotorcycleRepo) Facets(ctx context.Context, filter *domain.FilterMotorcycle) (*domain.MotorcycleFacets, error) {
+	facets := &domain.MotorcycleFacets{}
+	for _, f := range []struct {
+		facet  facet
+		result *[]domain.FacetCount
+	}{
+		{brandFacet, &facets.Brands},
+		{volumeFacet, &facets.Volumes},
+		{mileageFacet, &facets.Mileages},
+		{yearFacet, &facets.Years},
+	} {
+		counts, err := r.countFacet(ctx, f.facet, filter)
+		if err != nil {
+			return nil, err
+		}
+		*f.result = counts
+	}
+	return facets, nil
+}
+
+func (r *MotorcycleRepo) countFacet(ctx context.Context, f facet, filter *domain.FilterMotorcycle) ([]domain.FacetCount, error) {
+	s := r.psql.Select(f.expr+" AS value", "COUNT(*) AS count").
+		From(`"motorcycle" m`)
+	s = applyMotorcycleFilter(s, filter).
+		// Мотоциклы без значения не попадают ни в одну группу
+		Where(sq.Expr(f.expr + " IS NOT NULL")).
+		GroupBy("value").
+		OrderBy(f.order)
+
+	sql, args, err := s.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count facet: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []domain.FacetCount{}
+	for rows.Next() {
+		var c domain.FacetCount
+		if err := rows.Scan(&c.Value, &c.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan facet row: %w", err)
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
@@ -0,0 +1,158 @@
+package pg
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+)
+
+// Порядок статусов в выдаче по умолчанию: available -> reserved -> sold
+const statusRankExpr = `CASE
+	WHEN m.status = 'available' THEN 1
+	WHEN m.status = 'reserved' THEN 2
+	WHEN m.status = 'sold' THEN 3
+	ELSE 4
+END`
+
+// Значения для мотоциклов без пробега/объема/года, чтобы они шли в конце выдачи
+const (
+	nullsLastAsc  = "2147483647"
+	nullsLastDesc = "-1"
+)
+
+// sortColumn - одна колонка ключа сортировки. Значение ключа передается
+// в курсоре текстом и приводится обратно к cast при сравнении
+type sortColumn struct {
+	expr string
+	cast string
+	desc bool
+}
+
+// idColumn завершает любой ключ, чтобы порядок был однозначным
+func idColumn(desc bool) sortColumn {
+	return sortColumn{expr: "m.id", cast: "text", desc: desc}
+}
+
+func motorcycleSortColumns(sort domain.MotorcycleSort, order domain.SortOrder) ([]sortColumn, error) {
+	desc := order == domain.SortOrderDesc
+	if order == "" {
+		// Новые и свежие по году - по убыванию, остальное - по возрастанию
+		desc = sort == domain.MotorcycleSortNewest || sort == domain.MotorcycleSortYear
+	}
+
+	nullable := func(expr string) string {
+		if desc {
+			return fmt.Sprintf("COALESCE(%s, %s)", expr, nullsLastDesc)
+		}
+		return fmt.Sprintf("COALESCE(%s, %s)", expr, nullsLastAsc)
+	}
+
+	switch sort {
+	case domain.MotorcycleSortDefault:
+		return []sortColumn{
+			{expr: statusRankExpr, cast: "int"},
+			{expr: "m.created_at", cast: "timestamp", desc: true},
+			idColumn(true),
+		}, nil
+	case domain.MotorcycleSortNewest:
+		return []sortColumn{
+			{expr: "m.created_at", cast: "timestamp", desc: desc},
+			idColumn(desc),
+		}, nil
+	case domain.MotorcycleSortPrice:
+		return []sortColumn{
+			{expr: "m.price", cast: "numeric", desc: desc},
+			idColumn(desc),
+		}, nil
+	case domain.MotorcycleSortMileage:
+		return []sortColumn{
+			{expr: nullable(mileageExpr), cast: "int", desc: desc},
+			idColumn(desc),
+		}, nil
+	case domain.MotorcycleSortVolume:
+		return []sortColumn{
+			{expr: nullable(volumeExpr), cast: "int", desc: desc},
+			idColumn(desc),
+		}, nil
+	case domain.MotorcycleSortYear:
+		return []sortColumn{
+			{expr: nullable(yearExpr), cast: "int", desc: desc},
+			idColumn(desc),
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown sort %q", sort)
+}
+
+// applyMotorcycleSort добавляет сортировку и выбирает значения ключа
+// колонками после основных, чтобы из последней строки собрать курсор
+func applyMotorcycleSort(s sq.SelectBuilder, columns []sortColumn) sq.SelectBuilder {
+	for _, c := range columns {
+		s = s.Column(fmt.Sprintf("(%s)::text", c.expr))
+		if c.desc {
+			s = s.OrderBy(c.expr + " DESC")
+		} else {
+			s = s.OrderBy(c.expr + " ASC")
+		}
+	}
+	return s
+}
+
+// keysetCondition выбирает строки строго после ключа keys:
+// (c1 > k1) OR (c1 = k1 AND c2 > k2) OR ...
+// Направление сравнения учитывается для каждой колонки отдельно
+func keysetCondition(columns []sortColumn, keys []string) sq.Or {
+	or := sq.Or{}
+	for i, c := range columns {
+		and := sq.And{}
+		for j := range i {
+			and = append(and, sq.Expr(fmt.Sprintf("%s = ?::%s", columns[j].expr, columns[j].cast), keys[j]))
+		}
+		op := ">"
+		if c.desc {
+			op = "<"
+		}
+		and = append(and, sq.Expr(fmt.Sprintf("%s %s ?::%s", c.expr, op, c.cast), keys[i]))
+		or = append(or, and)
+	}
+	return or
+}
+
+type motorcycleCursor struct {
+	// Sort защищает от использования курсора с другой сортировкой
+	Sort string   `json:"s"`
+	Keys []string `json:"k"`
+}
+
+func cursorSort(sort domain.MotorcycleSort, order domain.SortOrder) string {
+	return fmt.Sprintf("%s:%s", sort, order)
+}
+
+func encodeMotorcycleCursor(sort domain.MotorcycleSort, order domain.SortOrder, keys []string) (string, error) {
+	raw, err := json.Marshal(motorcycleCursor{
+		Sort: cursorSort(sort, order),
+		Keys: keys,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decodeMotorcycleCursor(cursor string, sort domain.MotorcycleSort, order domain.SortOrder, keysLen int) ([]string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, repo.ErrInvalidCursor
+	}
+	var c motorcycleCursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, repo.ErrInvalidCursor
+	}
+	if c.Sort != cursorSort(sort, order) || len(c.Keys) != keysLen {
+		return nil, repo.ErrInvalidCursor
+	}
+	return c.Keys, nil
+}
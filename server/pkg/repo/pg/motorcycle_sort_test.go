@@ -0,0 +1,160 @@
+package pg
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+)
+
+func TestMotorcycleCursorRoundTrip(t *testing.T) {
+	keys := []string{"1", "2026-01-02 03:04:05", "id-1"}
+	cursor, err := encodeMotorcycleCursor(domain.MotorcycleSortDefault, "", keys)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	got, err := decodeMotorcycleCursor(cursor, domain.MotorcycleSortDefault, "", len(keys))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !reflect.DeepEqual(got, keys) {
+		t.Errorf("keys = %v, want %v", got, keys)
+	}
+}
+
+func TestDecodeMotorcycleCursorInvalid(t *testing.T) {
+	priceAsc, err := encodeMotorcycleCursor(domain.MotorcycleSortPrice, domain.SortOrderAsc, []string{"100", "id-1"})
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		cursor  string
+		sort    domain.MotorcycleSort
+		order   domain.SortOrder
+		keysLen int
+	}{
+		{name: "not base64", cursor: "!!!", sort: domain.MotorcycleSortPrice, order: domain.SortOrderAsc, keysLen: 2},
+		{name: "not json", cursor: "bm90LWpzb24", sort: domain.MotorcycleSortPrice, order: domain.SortOrderAsc, keysLen: 2},
+		{name: "other sort", cursor: priceAsc, sort: domain.MotorcycleSortYear, order: domain.SortOrderAsc, keysLen: 2},
+		{name: "other order", cursor: priceAsc, sort: domain.MotorcycleSortPrice, order: domain.SortOrderDesc, keysLen: 2},
+		{name: "keys length", cursor: priceAsc, sort: domain.MotorcycleSortPrice, order: domain.SortOrderAsc, keysLen: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeMotorcycleCursor(tt.cursor, tt.sort, tt.order, tt.keysLen)
+			if !errors.Is(err, repo.ErrInvalidCursor) {
+				t.Errorf("err = %v, want %v", err, repo.ErrInvalidCursor)
+			}
+		})
+	}
+}
+
+func TestMotorcycleSortColumns(t *testing.T) {
+	tests := []struct {
+		sort  domain.MotorcycleSort
+		order domain.SortOrder
+		desc  []bool
+	}{
+		{sort: domain.MotorcycleSortDefault, desc: []bool{false, true, true}},
+		{sort: domain.MotorcycleSortNewest, desc: []bool{true, true}},
+		{sort: domain.MotorcycleSortNewest, order: domain.SortOrderAsc, desc: []bool{false, false}},
+		{sort: domain.MotorcycleSortPrice, desc: []bool{false, false}},
+		{sort: domain.MotorcycleSortPrice, order: domain.SortOrderDesc, desc: []bool{true, true}},
+		{sort: domain.MotorcycleSortYear, desc: []bool{true, true}},
+		{sort: domain.MotorcycleSortMileage, desc: []bool{false, false}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.sort)+":"+string(tt.order), func(t *testing.T) {
+			columns, err := motorcycleSortColumns(tt.sort, tt.order)
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			desc := make([]bool, 0, len(columns))
+			for _, c := range columns {
+				desc = append(desc, c.desc)
+			}
+			if !reflect.DeepEqual(desc, tt.desc) {
+				t.Errorf("desc = %v, want %v", desc, tt.desc)
+			}
+			if last := columns[len(columns)-1]; last.expr != "m.id" {
+				t.Errorf("last column = %q, want m.id", last.expr)
+			}
+		})
+	}
+
+	if _, err := motorcycleSortColumns("color", ""); err == nil {
+		t.Error("unknown sort: expected error")
+	}
+}
+
+func TestMotorcycleSortColumnsNullsLast(t *testing.T) {
+	asc, _ := motorcycleSortColumns(domain.MotorcycleSortMileage, domain.SortOrderAsc)
+	desc, _ := motorcycleSortColumns(domain.MotorcycleSortMileage, domain.SortOrderDesc)
+	if want := "COALESCE(" + mileageExpr + ", " + nullsLastAsc + ")"; asc[0].expr != want {
+		t.Errorf("asc expr = %q, want %q", asc[0].expr, want)
+	}
+	if want := "COALESCE(" + mileageExpr + ", " + nullsLastDesc + ")"; desc[0].expr != want {
+		t.Errorf("desc expr = %q, want %q", desc[0].expr, want)
+	}
+}
+
+func TestKeysetCondition(t *testing.T) {
+	tests := []struct {
+		name    string
+		columns []sortColumn
+		keys    []string
+		sql     string
+	}{
+		{
+			name:    "single column asc",
+			columns: []sortColumn{idColumn(false)},
+			keys:    []string{"a"},
+			sql:     "((m.id > ?::text))",
+		},
+		{
+			name: "mixed directions",
+			columns: []sortColumn{
+				{expr: "m.price", cast: "numeric"},
+				idColumn(true),
+			},
+			keys: []string{"100", "b"},
+			sql:  "((m.price > ?::numeric) OR (m.price = ?::numeric AND m.id < ?::text))",
+		},
+		{
+			name: "three columns",
+			columns: []sortColumn{
+				{expr: "r", cast: "int"},
+				{expr: "c", cast: "timestamp", desc: true},
+				idColumn(true),
+			},
+			keys: []string{"1", "t", "x"},
+			sql:  "((r > ?::int) OR (r = ?::int AND c < ?::timestamp) OR (r = ?::int AND c = ?::timestamp AND m.id < ?::text))",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := keysetCondition(tt.columns, tt.keys).ToSql()
+			if err != nil {
+				t.Fatalf("ToSql: %v", err)
+			}
+			if sql != tt.sql {
+				t.Errorf("sql = %q\nwant  %q", sql, tt.sql)
+			}
+			// Каждое сравнение получает значение своей колонки
+			wantArgs := []any{}
+			for i := range tt.columns {
+				for j := range i + 1 {
+					wantArgs = append(wantArgs, tt.keys[j])
+				}
+			}
+			if !reflect.DeepEqual(args, wantArgs) {
+				t.Errorf("args = %v, want %v", args, wantArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+)
+
+func (r *MotorcycleRepo) StatusHistory(ctx context.Context, id string) ([]*domain.MotorcycleStatusChange, error) {
+	s, args, err := r.psql.Select("id", "motorcycle_id", "from_status", "to_status", "changed_by", "changed_at").
+		From(`"motorcycle_status_history"`).
+		Where(sq.Eq{"motorcycle_id": id}).
+		OrderBy("changed_at ASC", "id ASC").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, s, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query status history: %w", err)
+	}
+	defer rows.Close()
+
+	history := []*domain.MotorcycleStatusChange{}
+	for rows.Next() {
+		var change domain.MotorcycleStatusChange
+		if err := rows.Scan(&change.ID, &change.MotorcycleID, &change.From, &change.To, &change.ChangedBy, &change.ChangedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan status change: %w", err)
+		}
+		history = append(history, &change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read status history: %w", err)
+	}
+	return history, nil
+}
+
+func (r *MotorcycleRepo) insertStatusChange(ctx context.Context, tx pgx.Tx, id string, from *domain.MotorcycleStatus, to domain.MotorcycleStatus, changedBy *string, at time.Time) error {
+	s, args, err := r.psql.Insert(`"motorcycle_status_history"`).
+		Columns("motorcycle_id", "from_status", "to_status", "changed_by", "changed_at").
+		Values(id, from, to, changedBy, at).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL: %w", err)
+	}
+	if _, err := tx.Exec(ctx, s, args...); err != nil {
+		return fmt.Errorf("failed to record status change: %w", err)
+	}
+	return nil
+}
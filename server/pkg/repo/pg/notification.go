@@ -0,0 +1,145 @@
+package pg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+)
+
+// claimNotificationsSQL берет готовые к отправке сообщения и сдвигает их send_at на аренду:
+// если отправитель упадет, сообщения снова станут доступны после ее окончания
+const claimNotificationsSQL = `
+UPDATE "notification" SET
+    attempts = attempts + 1,
+    send_at = NOW() + make_interval(secs => $1)
+WHERE id IN (
+    SELECT id FROM "notification"
+    WHERE status = 'pending' AND send_at <= NOW()
+    ORDER BY send_at
+    LIMIT $2
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, chat_id, text, photo_url, buttons, attempts`
+
+type NotificationRepo struct {
+	db   *pgxpool.Pool
+	psql sq.StatementBuilderType
+}
+
+func NewNotificationRepo(db *pgxpool.Pool) *NotificationRepo {
+	return &NotificationRepo{
+		db:   db,
+		psql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (r *NotificationRepo) Create(ctx context.Context, notifications []*domain.CreateNotification) error {
+	return insertNotifications(ctx, r.db, r.psql, notifications)
+}
+
+// insertNotifications ставит сообщения в очередь, в том числе в транзакции другого изменения
+func insertNotifications(ctx context.Context, db execer, psql sq.StatementBuilderType, notifications []*domain.CreateNotification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	s := psql.Insert(`"notification"`).Columns("chat_id", "text", "photo_url", "buttons")
+	for _, notification := range notifications {
+		buttons := notification.Buttons
+		if buttons == nil {
+			buttons = []domain.NotificationButton{}
+		}
+		buttonsJSON, err := json.Marshal(buttons)
+		if err != nil {
+			return fmt.Errorf("failed to marshal buttons: %w", err)
+		}
+		s = s.Values(notification.ChatID, notification.Text, notification.PhotoURL, buttonsJSON)
+	}
+
+	sql, args, err := s.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL: %w", err)
+	}
+	if _, err := db.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("failed to create notifications: %w", err)
+	}
+	return nil
+}
+
+func (r *NotificationRepo) Claim(ctx context.Context, limit int, lease time.Duration) ([]*domain.Notification, error) {
+	rows, err := r.db.Query(ctx, claimNotificationsSQL, lease.Seconds(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim notifications: %w", err)
+	}
+	defer rows.Close()
+
+	notifications := []*domain.Notification{}
+	for rows.Next() {
+		var notification domain.Notification
+		var buttonsJSON []byte
+		err := rows.Scan(
+			&notification.ID,
+			&notification.ChatID,
+			&notification.Text,
+			&notification.PhotoURL,
+			&buttonsJSON,
+			&notification.Attempts,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		if err := json.Unmarshal(buttonsJSON, &notification.Buttons); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal buttons: %w", err)
+		}
+		notifications = append(notifications, &notification)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read notifications: %w", err)
+	}
+	return notifications, nil
+}
+
+func (r *NotificationRepo) Sent(ctx context.Context, id string) error {
+	s := r.psql.Update(`"notification"`).
+		Where(sq.Eq{"id": id}).
+		Set("status", "sent").
+		Set("last_error", "").
+		Set("sent_at", sq.Expr("NOW()"))
+
+	return r.exec(ctx, s, "failed to mark notification sent")
+}
+
+func (r *NotificationRepo) Retry(ctx context.Context, id, lastError string, delay time.Duration) error {
+	s := r.psql.Update(`"notification"`).
+		Where(sq.Eq{"id": id}).
+		Set("last_error", lastError).
+		Set("send_at", sq.Expr("NOW() + make_interval(secs => ?)", delay.Seconds()))
+
+	return r.exec(ctx, s, "failed to reschedule notification")
+}
+
+func (r *NotificationRepo) Fail(ctx context.Context, id, lastError string) error {
+	s := r.psql.Update(`"notification"`).
+		Where(sq.Eq{"id": id}).
+		Set("status", "failed").
+		Set("last_error", lastError)
+
+	return r.exec(ctx, s, "failed to fail notification")
+}
+
+func (r *NotificationRepo) exec(ctx context.Context, s sq.UpdateBuilder, msg string) error {
+	sql, args, err := s.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	if _, err := r.db.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	return nil
+}
@@ -1,9 +1,24 @@
 package pg
 
-import "github.com/shampsdev/go-telegram-template/pkg/repo"
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+)
 
 // to ensure pg implement the repo interfaces
 var (
 	_ repo.User       = &UserRepo{}
 	_ repo.Motorcycle = &MotorcycleRepo{}
 )
+
+// querier и execer - пул соединений или транзакция, чтобы один запрос работал в обоих
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
@@ -0,0 +1,339 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+)
+
+var reservationColumns = []string{
+	"id", "motorcycle_id", "user_id", "status", "comment", "decided_by", "decided_at", "expires_at", "created_at",
+}
+
+type ReservationRepo struct {
+	db   *pgxpool.Pool
+	psql sq.StatementBuilderType
+	// motorcycles пишет смену статуса мотоцикла в ту же транзакцию, что и решение по заявке
+	motorcycles *MotorcycleRepo
+}
+
+func NewReservationRepo(db *pgxpool.Pool) *ReservationRepo {
+	return &ReservationRepo{
+		db:          db,
+		psql:        sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+		motorcycles: NewMotorcycleRepo(db),
+	}
+}
+
+func (r *ReservationRepo) Create(ctx context.Context, reservation *domain.CreateReservation) (string, error) {
+	s, args, err := r.psql.Insert(`"reservation"`).
+		Columns("motorcycle_id", "user_id", "comment").
+		Values(reservation.MotorcycleID, reservation.UserID, reservation.Comment).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return "", fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	var id string
+	if err := r.db.QueryRow(ctx, s, args...).Scan(&id); err != nil {
+		return "", fmt.Errorf("failed to create reservation: %w", uniqueViolation(err))
+	}
+	return id, nil
+}
+
+func (r *ReservationRepo) Filter(ctx context.Context, filter *domain.FilterReservation) ([]*domain.Reservation, error) {
+	s := r.psql.Select(reservationColumns...).
+		From(`"reservation"`).
+		OrderBy("created_at DESC", "id DESC")
+
+	if filter.ID != nil {
+		s = s.Where(sq.Eq{"id": *filter.ID})
+	}
+	if filter.MotorcycleID != nil {
+		s = s.Where(sq.Eq{"motorcycle_id": *filter.MotorcycleID})
+	}
+	if filter.UserID != nil {
+		s = s.Where(sq.Eq{"user_id": *filter.UserID})
+	}
+	if filter.Status != nil {
+		s = s.Where(sq.Eq{"status": *filter.Status})
+	}
+
+	sql, args, err := s.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reservations: %w", err)
+	}
+	defer rows.Close()
+
+	reservations := []*domain.Reservation{}
+	for rows.Next() {
+		reservation, err := scanReservation(rows)
+		if err != nil {
+			return nil, err
+		}
+		reservations = append(reservations, reservation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read reservations: %w", err)
+	}
+	return reservations, nil
+}
+
+func (r *ReservationRepo) Approve(ctx context.Context, id, decidedBy string, expiresAt time.Time, audit repo.AuditFunc) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	motorcycleID, err := r.lockPending(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	before, err := r.motorcycles.lockMotorcycle(ctx, tx, motorcycleID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	// Переводим мотоцикл, только если он все еще available
+	updateSQL, args, err := r.psql.Update(`"motorcycle"`).
+		Set("status", domain.MotorcycleStatusReserved).
+		Set("version", sq.Expr("version + 1")).
+		Set("updated_at", now).
+		Where(sq.Eq{"id": motorcycleID, "status": domain.MotorcycleStatusAvailable, "archived_at": nil}).
+		Suffix(returningMotorcycle).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL: %w", err)
+	}
+	after, err := r.motorcycles.queryMotorcycle(ctx, tx, updateSQL, args)
+	if errors.Is(err, repo.ErrNotFound) {
+		return repo.ErrConflict
+	}
+	if err != nil {
+		return fmt.Errorf("failed to reserve motorcycle: %w", err)
+	}
+	if err := r.motorcycles.insertStatusChange(ctx, tx, motorcycleID, &before.Status, after.Status, &decidedBy, now); err != nil {
+		return err
+	}
+	if err := r.motorcycles.writeAudit(ctx, tx, audit, before, after); err != nil {
+		return err
+	}
+
+	// Мотоцикл был available, значит прошлая бронь на него уже не действует
+	if err := closeReservations(ctx, tx, r.psql, motorcycleID); err != nil {
+		return err
+	}
+
+	if err := r.decide(ctx, tx, id, domain.ReservationStatusApproved, decidedBy, now, &expiresAt); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *ReservationRepo) Decline(ctx context.Context, id, decidedBy string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := r.lockPending(ctx, tx, id); err != nil {
+		return err
+	}
+	if err := r.decide(ctx, tx, id, domain.ReservationStatusDeclined, decidedBy, time.Now(), nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *ReservationRepo) Expire(ctx context.Context, now time.Time, audit repo.AuditFunc) ([]*domain.Reservation, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// SKIP LOCKED: если запущено несколько серверов, каждую бронь снимает кто-то один
+	selectSQL, args, err := r.psql.Select(reservationColumns...).
+		From(`"reservation"`).
+		Where(sq.Eq{"status": domain.ReservationStatusApproved}).
+		Where(sq.LtOrEq{"expires_at": now}).
+		Suffix("FOR UPDATE SKIP LOCKED").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL: %w", err)
+	}
+	rows, err := tx.Query(ctx, selectSQL, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expired reservations: %w", err)
+	}
+	expired := []*domain.Reservation{}
+	for rows.Next() {
+		reservation, err := scanReservation(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		expired = append(expired, reservation)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read expired reservations: %w", err)
+	}
+
+	released := []*domain.Reservation{}
+	for _, reservation := range expired {
+		reservation.Status = domain.ReservationStatusExpired
+		s, args, err := r.psql.Update(`"reservation"`).
+			Set("status", reservation.Status).
+			Where(sq.Eq{"id": reservation.ID}).
+			ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build SQL: %w", err)
+		}
+		if _, err := tx.Exec(ctx, s, args...); err != nil {
+			return nil, fmt.Errorf("failed to expire reservation: %w", err)
+		}
+
+		// Ручная смена статуса закрывает бронь (см. closeReservations), условие на reserved -
+		// страховка для броней, подтвержденных до этого
+		before, err := r.motorcycles.lockMotorcycle(ctx, tx, reservation.MotorcycleID)
+		if errors.Is(err, repo.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		s, args, err = r.psql.Update(`"motorcycle"`).
+			Set("status", domain.MotorcycleStatusAvailable).
+			Set("version", sq.Expr("version + 1")).
+			Set("updated_at", now).
+			Where(sq.Eq{"id": reservation.MotorcycleID, "status": domain.MotorcycleStatusReserved}).
+			Suffix(returningMotorcycle).
+			ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build SQL: %w", err)
+		}
+		after, err := r.motorcycles.queryMotorcycle(ctx, tx, s, args)
+		if errors.Is(err, repo.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to release motorcycle: %w", err)
+		}
+		if err := r.motorcycles.insertStatusChange(ctx, tx, reservation.MotorcycleID, &before.Status, after.Status, nil, now); err != nil {
+			return nil, err
+		}
+		if err := r.motorcycles.writeAudit(ctx, tx, audit, before, after); err != nil {
+			return nil, err
+		}
+		released = append(released, reservation)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return released, nil
+}
+
+// closeReservations закрывает действующую бронь мотоцикла, чтобы ее истечение
+// не вернуло в каталог мотоцикл, который уже продали или забронировали заново
+func closeReservations(ctx context.Context, tx pgx.Tx, psql sq.StatementBuilderType, motorcycleID string) error {
+	s, args, err := psql.Update(`"reservation"`).
+		Set("status", domain.ReservationStatusClosed).
+		Where(sq.Eq{"motorcycle_id": motorcycleID, "status": domain.ReservationStatusApproved}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL: %w", err)
+	}
+	if _, err := tx.Exec(ctx, s, args...); err != nil {
+		return fmt.Errorf("failed to close reservation: %w", err)
+	}
+	return nil
+}
+
+// lockPending блокирует заявку до конца транзакции и возвращает ее мотоцикл.
+// Если заявка уже рассмотрена - ErrConflict
+func (r *ReservationRepo) lockPending(ctx context.Context, tx pgx.Tx, id string) (string, error) {
+	s, args, err := r.psql.Select("motorcycle_id", "status").
+		From(`"reservation"`).
+		Where(sq.Eq{"id": id}).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return "", fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	var motorcycleID string
+	var status domain.ReservationStatus
+	err = tx.QueryRow(ctx, s, args...).Scan(&motorcycleID, &status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", repo.ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to lock reservation: %w", err)
+	}
+	if status != domain.ReservationStatusPending {
+		return "", repo.ErrConflict
+	}
+	return motorcycleID, nil
+}
+
+func (r *ReservationRepo) decide(ctx context.Context, tx pgx.Tx, id string, status domain.ReservationStatus, decidedBy string, at time.Time, expiresAt *time.Time) error {
+	s, args, err := r.psql.Update(`"reservation"`).
+		Set("status", status).
+		Set("decided_by", decidedBy).
+		Set("decided_at", at).
+		Set("expires_at", expiresAt).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL: %w", err)
+	}
+	if _, err := tx.Exec(ctx, s, args...); err != nil {
+		return fmt.Errorf("failed to update reservation: %w", err)
+	}
+	return nil
+}
+
+func scanReservation(row pgx.Row) (*domain.Reservation, error) {
+	var reservation domain.Reservation
+	err := row.Scan(
+		&reservation.ID,
+		&reservation.MotorcycleID,
+		&reservation.UserID,
+		&reservation.Status,
+		&reservation.Comment,
+		&reservation.DecidedBy,
+		&reservation.DecidedAt,
+		&reservation.ExpiresAt,
+		&reservation.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan reservation: %w", err)
+	}
+	return &reservation, nil
+}
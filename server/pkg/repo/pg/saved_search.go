@@ -0,0 +1,141 @@
+package pg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+)
+
+type SavedSearchRepo struct {
+	db   *pgxpool.Pool
+	psql sq.StatementBuilderType
+}
+
+func NewSavedSearchRepo(db *pgxpool.Pool) *SavedSearchRepo {
+	return &SavedSearchRepo{
+		db:   db,
+		psql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (r *SavedSearchRepo) Create(ctx context.Context, search *domain.CreateSavedSearch) (string, error) {
+	filterJSON, err := json.Marshal(search.Filter)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal filter: %w", err)
+	}
+
+	s, args, err := r.psql.Insert(`"saved_search"`).
+		Columns("user_id", "name", "filter").
+		Values(search.UserID, search.Name, filterJSON).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return "", fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	var id string
+	if err := r.db.QueryRow(ctx, s, args...).Scan(&id); err != nil {
+		return "", fmt.Errorf("failed to create saved search: %w", uniqueViolation(err))
+	}
+	return id, nil
+}
+
+func (r *SavedSearchRepo) Filter(ctx context.Context, filter *domain.FilterSavedSearch) ([]*domain.SavedSearch, error) {
+	s := r.psql.Select("s.id", "s.user_id", "s.name", "s.filter", "u.telegram_id", "s.created_at", "s.updated_at").
+		From(`"saved_search" s`).
+		Join(`"user" u ON u.id = s.user_id`).
+		OrderBy("s.created_at", "s.id")
+
+	if filter.ID != nil {
+		s = s.Where(sq.Eq{"s.id": *filter.ID})
+	}
+	if filter.UserID != nil {
+		s = s.Where(sq.Eq{"s.user_id": *filter.UserID})
+	}
+
+	sql, args, err := s.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query saved searches: %w", err)
+	}
+	defer rows.Close()
+
+	searches := []*domain.SavedSearch{}
+	for rows.Next() {
+		var search domain.SavedSearch
+		var filterJSON []byte
+		err := rows.Scan(
+			&search.ID,
+			&search.UserID,
+			&search.Name,
+			&filterJSON,
+			&search.TelegramID,
+			&search.CreatedAt,
+			&search.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan saved search: %w", err)
+		}
+		if err := json.Unmarshal(filterJSON, &search.Filter); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal filter: %w", err)
+		}
+		searches = append(searches, &search)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read saved searches: %w", err)
+	}
+	return searches, nil
+}
+
+func (r *SavedSearchRepo) Update(ctx context.Context, id, userID string, search *domain.UpdateSavedSearch) error {
+	filterJSON, err := json.Marshal(search.Filter)
+	if err != nil {
+		return fmt.Errorf("failed to marshal filter: %w", err)
+	}
+
+	s, args, err := r.psql.Update(`"saved_search"`).
+		Set("name", search.Name).
+		Set("filter", filterJSON).
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": id, "user_id": userID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	tag, err := r.db.Exec(ctx, s, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update saved search: %w", uniqueViolation(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func (r *SavedSearchRepo) Delete(ctx context.Context, id, userID string) error {
+	s, args, err := r.psql.Delete(`"saved_search"`).
+		Where(sq.Eq{"id": id, "user_id": userID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	tag, err := r.db.Exec(ctx, s, args...)
+	if err != nil {
+		return fmt.Errorf("failed to delete saved search: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
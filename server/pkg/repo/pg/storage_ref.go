@@ -0,0 +1,37 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type StorageRefRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewStorageRefRepo(db *pgxpool.Pool) *StorageRefRepo {
+	return &StorageRefRepo{db: db}
+}
+
+// referencedKeysSQL собирает все ключи изображений, на которые ссылается БД
+const referencedKeysSQL = `
+SELECT object_key FROM "motorcycle_photo"
+UNION
+SELECT v->>'key' FROM "motorcycle_photo", jsonb_array_elements(variants) AS v
+UNION
+SELECT avatar FROM "user" WHERE avatar IS NOT NULL AND avatar <> ''`
+
+func (r *StorageRefRepo) ReferencedKeys(ctx context.Context) ([]string, error) {
+	rows, err := r.db.Query(ctx, referencedKeysSQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query referenced keys: %w", err)
+	}
+	keys, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan referenced keys: %w", err)
+	}
+	return keys, nil
+}
@@ -0,0 +1,104 @@
+package pg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+)
+
+type SubscriptionRepo struct {
+	db   *pgxpool.Pool
+	psql sq.StatementBuilderType
+}
+
+func NewSubscriptionRepo(db *pgxpool.Pool) *SubscriptionRepo {
+	return &SubscriptionRepo{
+		db:   db,
+		psql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (r *SubscriptionRepo) Filter(ctx context.Context, filter *domain.FilterSubscription) ([]*domain.Subscription, error) {
+	s := r.psql.Select("s.user_id", "s.filter", "u.telegram_id", "s.created_at", "s.updated_at").
+		From(`"subscription" s`).
+		Join(`"user" u ON u.id = s.user_id`).
+		OrderBy("s.created_at")
+
+	if filter.UserID != nil {
+		s = s.Where(sq.Eq{"s.user_id": *filter.UserID})
+	}
+
+	sql, args, err := s.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := []*domain.Subscription{}
+	for rows.Next() {
+		var subscription domain.Subscription
+		var filterJSON []byte
+		err := rows.Scan(
+			&subscription.UserID,
+			&filterJSON,
+			&subscription.TelegramID,
+			&subscription.CreatedAt,
+			&subscription.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan subscription: %w", err)
+		}
+		if err := json.Unmarshal(filterJSON, &subscription.SavedSearchFilter); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal filter: %w", err)
+		}
+		subscriptions = append(subscriptions, &subscription)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read subscriptions: %w", err)
+	}
+	return subscriptions, nil
+}
+
+func (r *SubscriptionRepo) Set(ctx context.Context, userID string, subscription *domain.SetSubscription) error {
+	filterJSON, err := json.Marshal(subscription.SavedSearchFilter)
+	if err != nil {
+		return fmt.Errorf("failed to marshal filter: %w", err)
+	}
+
+	s, args, err := r.psql.Insert(`"subscription"`).
+		Columns("user_id", "filter").
+		Values(userID, filterJSON).
+		Suffix("ON CONFLICT (user_id) DO UPDATE SET filter = EXCLUDED.filter, updated_at = NOW()").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	if _, err := r.db.Exec(ctx, s, args...); err != nil {
+		return fmt.Errorf("failed to set subscription: %w", err)
+	}
+	return nil
+}
+
+func (r *SubscriptionRepo) Delete(ctx context.Context, userID string) error {
+	s, args, err := r.psql.Delete(`"subscription"`).
+		Where(sq.Eq{"user_id": userID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	if _, err := r.db.Exec(ctx, s, args...); err != nil {
+		return fmt.Errorf("failed to delete subscription: %w", err)
+	}
+	return nil
+}
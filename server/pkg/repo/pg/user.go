@@ -51,6 +51,10 @@ func (r *UserRepo) Filter(ctx context.Context, filter *domain.FilterUser) ([]*do
 		s = s.Where(sq.Eq{"telegram_id": *filter.TelegramID})
 	}
 
+	if filter.IsAdmin != nil {
+		s = s.Where(sq.Eq{"is_admin": *filter.IsAdmin})
+	}
+
 	sql, args, err := s.ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build SQL: %w", err)
@@ -3,12 +3,18 @@ package repo
 import (
 	"context"
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/shampsdev/go-telegram-template/pkg/domain"
 )
 
 var (
-	ErrNotFound = errors.New("not found")
+	ErrNotFound      = errors.New("not found")
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrConflict - запись изменилась с момента чтения
+	ErrConflict = errors.New("conflict")
 )
 
 type User interface {
@@ -18,17 +24,207 @@ type User interface {
 	Delete(ctx context.Context, id string) error
 }
 
+// AuditFunc строит записи журнала по мотоциклу до и после изменения (nil - мотоцикла нет).
+// Репозиторий читает оба состояния и пишет записи в транзакции самого изменения,
+// поэтому изменение без записи в журнале не сохраняется. nil - журнал не пишется
+type AuditFunc func(before, after *domain.Motorcycle) ([]*domain.CreateAuditEntry, error)
+
+// Методы Motorcycle, меняющие мотоцикл, пишут журнал через audit
 type Motorcycle interface {
-	Create(ctx context.Context, motorcycle *domain.CreateMotorcycle) (string, error)
-	Patch(ctx context.Context, id string, motorcycle *domain.PatchMotorcycle) error
+	Create(ctx context.Context, motorcycle *domain.CreateMotorcycle, audit AuditFunc) (string, error)
+	// Patch одной транзакцией применяет изменения, увеличивает версию и, если меняется статус,
+	// пишет переход в историю от имени changedBy; уход из reserved закрывает действующую бронь.
+	// Если версия мотоцикла уже не version, ничего не меняет и возвращает ErrConflict
+	Patch(ctx context.Context, id string, motorcycle *domain.PatchMotorcycle, version int, changedBy *string, audit AuditFunc) error
 	Filter(ctx context.Context, filter *domain.FilterMotorcycle) ([]*domain.Motorcycle, error)
+	// FilterPage возвращает страницу мотоциклов и курсор следующей страницы
+	FilterPage(ctx context.Context, filter *domain.FilterMotorcycle) ([]*domain.Motorcycle, string, error)
+	Facets(ctx context.Context, filter *domain.FilterMotorcycle) (*domain.MotorcycleFacets, error)
+	Delete(ctx context.Context, id string, audit AuditFunc) error
+	StatusHistory(ctx context.Context, id string) ([]*domain.MotorcycleStatusChange, error)
+	// SetArchived переносит мотоцикл в архив или возвращает из него и увеличивает версию,
+	// чтобы PATCH с If-Match, прочитанным до архивации, получил ErrConflict
+	SetArchived(ctx context.Context, id string, archived bool, audit AuditFunc) error
+	AddPhotos(ctx context.Context, motorcycleID string, photos []*domain.CreateMotorcyclePhoto, audit AuditFunc) error
+	// DeletePhoto удаляет фотографию и сдвигает следующие за ней, чтобы порядок оставался сплошным
+	DeletePhoto(ctx context.Context, motorcycleID, photoID string, audit AuditFunc) error
+	// ReorderPhotos расставляет фотографии в порядке, который reorder строит по текущему.
+	// reorder вызывается под блокировкой мотоцикла, его ошибка возвращается как есть.
+	// Первая фотография становится обложкой
+	ReorderPhotos(ctx context.Context, motorcycleID string, reorder func(photoIDs []string) ([]string, error), audit AuditFunc) error
+	// ReplacePhoto подменяет файлы фотографии, сохраняя ее место в порядке
+	ReplacePhoto(ctx context.Context, motorcycleID, photoID string, photo *domain.CreateMotorcyclePhoto, audit AuditFunc) error
+}
+
+// Audit - журнал изменений мотоциклов. Записи только добавляются, и только
+// вместе с изменением (см. AuditFunc)
+type Audit interface {
+	// Filter возвращает записи от старых к новым
+	Filter(ctx context.Context, filter *domain.FilterAuditEntry) ([]*domain.AuditEntry, error)
+}
+
+// Favorite - избранное пользователей
+type Favorite interface {
+	// Add добавляет мотоцикл в избранное, повторное добавление ничего не меняет
+	Add(ctx context.Context, userID, motorcycleID string) error
+	Remove(ctx context.Context, userID, motorcycleID string) error
+	// Filter возвращает избранное пользователя, последние добавленные первыми
+	Filter(ctx context.Context, userID string) ([]*domain.Favorite, error)
+	// Counts возвращает, сколько пользователей добавили в избранное каждый мотоцикл
+	Counts(ctx context.Context, motorcycleIDs []string) (map[string]int, error)
+}
+
+// Reservation - заявки покупателей на бронь мотоциклов
+type Reservation interface {
+	// Create создает заявку; если у покупателя уже есть ожидающая заявка на мотоцикл - ErrAlreadyExists
+	Create(ctx context.Context, reservation *domain.CreateReservation) (string, error)
+	// Filter возвращает заявки, новые первыми
+	Filter(ctx context.Context, filter *domain.FilterReservation) ([]*domain.Reservation, error)
+	// Approve в одной транзакции подтверждает заявку до expiresAt и переводит мотоцикл
+	// из available в reserved с записью в историю статусов и журнал. Если заявка уже рассмотрена
+	// или мотоцикл не available, возвращает ErrConflict
+	Approve(ctx context.Context, id, decidedBy string, expiresAt time.Time, audit AuditFunc) error
+	// Decline отклоняет ожидающую заявку, иначе возвращает ErrConflict
+	Decline(ctx context.Context, id, decidedBy string) error
+	// Expire завершает брони, срок которых вышел к now, и возвращает их мотоциклы
+	// из reserved в available с записью в историю статусов и журнал. Возвращает только брони,
+	// чей мотоцикл вернулся в каталог
+	Expire(ctx context.Context, now time.Time, audit AuditFunc) ([]*domain.Reservation, error)
+}
+
+// Inquiry - вопросы покупателей о мотоциклах
+type Inquiry interface {
+	// Create сохраняет вопрос и в той же транзакции ставит в очередь сообщения,
+	// которые notify строит по ID вопроса
+	Create(ctx context.Context, inquiry *domain.CreateInquiry, notify func(id string) []*domain.CreateNotification) (string, error)
+	// Filter возвращает вопросы, новые первыми
+	Filter(ctx context.Context, filter *domain.FilterInquiry) ([]*domain.Inquiry, error)
+	// Answer сохраняет ответ админа, заменяя предыдущий. Если вопроса нет - ErrNotFound
+	Answer(ctx context.Context, id, answeredBy, answer string) error
+}
+
+// Subscription - подписки покупателей на уведомления, у пользователя не больше одной
+type Subscription interface {
+	// Filter возвращает подписки вместе с Telegram ID подписчиков
+	Filter(ctx context.Context, filter *domain.FilterSubscription) ([]*domain.Subscription, error)
+	// Set создает подписку пользователя или заменяет ее фильтр
+	Set(ctx context.Context, userID string, subscription *domain.SetSubscription) error
+	Delete(ctx context.Context, userID string) error
+}
+
+// SavedSearch - сохраненные поиски покупателей
+type SavedSearch interface {
+	// Create сохраняет поиск; если у пользователя уже есть поиск с таким названием - ErrAlreadyExists
+	Create(ctx context.Context, search *domain.CreateSavedSearch) (string, error)
+	// Filter возвращает поиски от старых к новым вместе с Telegram ID владельцев
+	Filter(ctx context.Context, filter *domain.FilterSavedSearch) ([]*domain.SavedSearch, error)
+	// Update заменяет поиск пользователя userID. Чужой или несуществующий поиск - ErrNotFound
+	Update(ctx context.Context, id, userID string, search *domain.UpdateSavedSearch) error
+	// Delete удаляет поиск пользователя userID. Чужой или несуществующий поиск - ErrNotFound
+	Delete(ctx context.Context, id, userID string) error
+}
+
+// MotorcycleEvent - очередь событий каталога для рассылки подписчикам
+type MotorcycleEvent interface {
+	Create(ctx context.Context, event *domain.CreateMotorcycleEvent) error
+	// Claim берет до limit необработанных событий не больше чем с maxAttempts попытками
+	// и откладывает их на lease; если обработчик упадет, события вернутся в очередь
+	Claim(ctx context.Context, limit, maxAttempts int, lease time.Duration) ([]*domain.MotorcycleEvent, error)
+	// Done отмечает событие обработанным и в той же транзакции ставит в очередь уведомления.
+	// Если событие уже обработано или после попытки attempt его взял другой обработчик,
+	// возвращает ErrConflict и ничего не ставит
+	Done(ctx context.Context, id string, attempt int, notifications []*domain.CreateNotification) error
+}
+
+// Notification - очередь сообщений в Telegram. Сообщения забираются
+// через SELECT ... FOR UPDATE SKIP LOCKED, как задачи импорта
+type Notification interface {
+	Create(ctx context.Context, notifications []*domain.CreateNotification) error
+	// Claim берет до limit готовых к отправке сообщений и откладывает их на lease,
+	// чтобы их не взял другой отправитель
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]*domain.Notification, error)
+	Sent(ctx context.Context, id string) error
+	// Retry возвращает сообщение в очередь не раньше чем через delay
+	Retry(ctx context.Context, id, lastError string, delay time.Duration) error
+	Fail(ctx context.Context, id, lastError string) error
+}
+
+// Brand - справочник марок и моделей. Filter возвращает марки вместе с моделями
+type Brand interface {
+	Create(ctx context.Context, brand *domain.CreateBrand) (string, error)
+	Patch(ctx context.Context, id string, brand *domain.PatchBrand) error
+	Filter(ctx context.Context, filter *domain.FilterBrand) ([]*domain.Brand, error)
 	Delete(ctx context.Context, id string) error
-	AddPhotos(ctx context.Context, motorcycleID string, photoURLs []string) error
+	CreateModel(ctx context.Context, brandID string, model *domain.CreateBrandModel) (string, error)
+	PatchModel(ctx context.Context, brandID, id string, model *domain.PatchBrandModel) error
+	DeleteModel(ctx context.Context, brandID, id string) error
+}
+
+// ImportJob - очередь задач импорта. Задачи забираются воркерами
+// через SELECT ... FOR UPDATE SKIP LOCKED, поэтому воркеров может быть несколько
+type ImportJob interface {
+	Create(ctx context.Context, job *domain.CreateImportJob) (string, error)
+	Filter(ctx context.Context, filter *domain.FilterImportJob) ([]*domain.ImportJob, error)
+	// Claim берет в работу ближайшую готовую задачу и продлевает ее аренду на lease.
+	// Если готовых задач нет, возвращает ErrNotFound
+	Claim(ctx context.Context, lease time.Duration) (*domain.ImportJob, error)
+	Progress(ctx context.Context, id string, stage domain.ImportJobStage, done, total int, lease time.Duration) error
+	Succeed(ctx context.Context, id, motorcycleID string) error
+	// Retry возвращает задачу в очередь не раньше чем через delay
+	Retry(ctx context.Context, id, lastError string, delay time.Duration) error
+	Fail(ctx context.Context, id, lastError string) error
+}
+
+// ImageSource - изображение для загрузки в хранилище
+type ImageSource struct {
+	URL string
+	Key string
 }
 
+// SavedImage - результат загрузки одного изображения: ключ объекта в хранилище
+// с вариантами размеров или ошибка
+type SavedImage struct {
+	Key      string
+	Variants []domain.PhotoVariant
+	Err      error
+}
+
+// ImageStorage хранит изображения. Методы сохранения возвращают ключ объекта,
+// в БД попадает только он, а ссылка строится методом URL при чтении
 type ImageStorage interface {
 	SaveImageByURL(ctx context.Context, url, key string) (string, error)
+	// SaveImagesByURL загружает изображения параллельно, результаты - в порядке images
+	SaveImagesByURL(ctx context.Context, images []ImageSource, progress func(done, total int)) []SavedImage
 	SaveImageByBytes(ctx context.Context, bytes []byte, key string) (string, error)
+	// URL возвращает ссылку на объект по настроенной стратегии (прямая, CDN или подписанная)
+	URL(key string) (string, error)
+	// SaveImageVariants сохраняет изображение, как при загрузке по URL: с вариантами размеров
+	SaveImageVariants(ctx context.Context, bytes []byte, key string) SavedImage
+	// DeletePrefix удаляет все объекты, ключ которых начинается с prefix
+	DeletePrefix(ctx context.Context, prefix string) error
+	// List возвращает все объекты, ключ которых начинается с prefix
+	List(ctx context.Context, prefix string) ([]StoredObject, error)
+	// Delete удаляет объекты по ключам из List
+	Delete(ctx context.Context, keys []string) error
+}
+
+// StoredObject - объект в хранилище изображений
+type StoredObject struct {
+	Key        string
+	Size       int64
+	ModifiedAt time.Time
+}
+
+// StorageRefs - ссылки на изображения, которые хранятся в БД
+type StorageRefs interface {
+	// ReferencedKeys возвращает ключи фотографий мотоциклов (с вариантами) и аватаров пользователей
+	ReferencedKeys(ctx context.Context) ([]string, error)
+}
+
+// IsAbsoluteURL сообщает, что вместо ключа хранится внешняя ссылка.
+// Так остаются записи, которые миграция не смогла перевести в ключи
+func IsAbsoluteURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
 }
 
 type Analytics interface {
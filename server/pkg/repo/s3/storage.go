@@ -4,28 +4,48 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"io"
 	"log/slog"
 	"mime"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/google/uuid"
 	"github.com/shampsdev/go-telegram-template/pkg/config"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+	"github.com/shampsdev/go-telegram-template/pkg/repo/ingest"
 	"github.com/shampsdev/go-telegram-template/pkg/utils/slogx"
 )
 
+// objectCacheControl - ключи объектов не переиспользуются, поэтому CDN и браузеры
+// могут хранить их сколько угодно
+const objectCacheControl = "public, max-age=31536000, immutable"
+
 type Storage struct {
 	cfg      config.S3Config
+	urls     config.StorageConfig
 	session  *session.Session
 	s3Client *s3.S3
 	rootDir  string
+	fetcher  *ingest.Fetcher
 }
 
-func NewStorage(cfg config.S3Config) (*Storage, error) {
+func NewStorage(cfg config.S3Config, urls config.StorageConfig, fetcher *ingest.Fetcher) (*Storage, error) {
+	switch urls.URLStrategy {
+	case config.StorageURLPublic, config.StorageURLPresigned:
+	case config.StorageURLCDN:
+		if urls.CDNURL == "" {
+			return nil, fmt.Errorf("STORAGE_CDN_URL is required for cdn url strategy")
+		}
+	default:
+		return nil, fmt.Errorf("unknown url strategy: %q", urls.URLStrategy)
+	}
+
 	sess, err := session.NewSession(&aws.Config{
 		Region:      &cfg.Region,
 		Endpoint:    &cfg.EndpointUrl,
@@ -39,26 +59,39 @@ func NewStorage(cfg config.S3Config) (*Storage, error) {
 
 	return &Storage{
 		cfg:      cfg,
+		urls:     urls,
 		session:  sess,
 		s3Client: s3Client,
 		rootDir:  cfg.RootDirectory,
+		fetcher:  fetcher,
 	}, nil
 }
 
 func (s *Storage) SaveImageByURL(ctx context.Context, imageURL, key string) (string, error) {
-	imageData, err := downloadFromURL(imageURL)
+	imageData, err := s.fetcher.Fetch(ctx, imageURL)
 	if err != nil {
 		return "", fmt.Errorf("failed to download image: %w", err)
 	}
 
-	fileURL, err := s.SaveImageByBytes(ctx, imageData, key)
+	objectKey, err := s.SaveImageByBytes(ctx, imageData, key)
 	if err != nil {
 		return "", fmt.Errorf("failed to save image: %w", err)
 	}
 
-	return fileURL, nil
+	return objectKey, nil
+}
+
+// SaveImagesByURL загружает изображения пулом воркеров ingest.Fetcher
+func (s *Storage) SaveImagesByURL(ctx context.Context, images []repo.ImageSource, progress func(done, total int)) []repo.SavedImage {
+	return s.fetcher.SaveAll(ctx, images, s.SaveImageByBytes, progress)
 }
 
+// SaveImageVariants сохраняет полученное изображение с вариантами размеров
+func (s *Storage) SaveImageVariants(ctx context.Context, data []byte, key string) repo.SavedImage {
+	return s.fetcher.Save(ctx, data, key, s.SaveImageByBytes)
+}
+
+// SaveImageByBytes загружает изображение и возвращает ключ объекта относительно rootDir
 func (s *Storage) SaveImageByBytes(ctx context.Context, imageData []byte, uid string) (string, error) {
 	if uid == "" {
 		uid = uuid.New().String()
@@ -69,36 +102,151 @@ func (s *Storage) SaveImageByBytes(ctx context.Context, imageData []byte, uid st
 		fileExtension = []string{".jpeg"}
 	}
 
-	key := fmt.Sprintf("%s/%s%s", s.rootDir, uid, fileExtension[0])
+	key := uid + fileExtension[0]
 
-	_, err := s.s3Client.PutObject(&s3.PutObjectInput{
-		Bucket:      &s.cfg.Bucket,
-		Key:         aws.String(key),
-		Body:        bytes.NewReader(imageData),
-		ContentType: aws.String(mimeType),
+	_, err := s.s3Client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:       &s.cfg.Bucket,
+		Key:          aws.String(s.fullKey(key)),
+		Body:         bytes.NewReader(imageData),
+		ContentType:  aws.String(mimeType),
+		CacheControl: aws.String(objectCacheControl),
 	})
 	if err != nil {
 		return "", fmt.Errorf("failed to upload image to S3: %w", err)
 	}
 
-	fileURL := fmt.Sprintf("%s/%s/%s", s.cfg.EndpointUrl, s.cfg.Bucket, key)
-	slogx.Info(ctx, "Succesfully upload image", slog.String("file_url", fileURL))
+	slogx.Info(ctx, "Succesfully upload image", slog.String("key", key))
+
+	return key, nil
+}
+
+// URL строит ссылку на объект по стратегии STORAGE_URL_STRATEGY
+func (s *Storage) URL(key string) (string, error) {
+	if repo.IsAbsoluteURL(key) {
+		return key, nil
+	}
 
-	return fileURL, nil
+	fullKey := s.fullKey(key)
+	switch s.urls.URLStrategy {
+	case config.StorageURLCDN:
+		return fmt.Sprintf("%s/%s", strings.TrimSuffix(s.urls.CDNURL, "/"), fullKey), nil
+	case config.StorageURLPresigned:
+		return s.presign(fullKey)
+	default:
+		return fmt.Sprintf("%s/%s/%s", s.cfg.EndpointUrl, s.cfg.Bucket, fullKey), nil
+	}
 }
 
-func downloadFromURL(imageURL string) ([]byte, error) {
-	resp, err := http.Get(imageURL)
+// presign подписывает ссылку на PresignTTL. Время подписи округляется до половины TTL,
+// чтобы ссылка на один и тот же объект не менялась на каждый запрос и кэшировалась браузером
+func (s *Storage) presign(fullKey string) (string, error) {
+	req, _ := s.s3Client.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: &s.cfg.Bucket,
+		Key:    aws.String(fullKey),
+	})
+	if err := req.Build(); err != nil {
+		return "", fmt.Errorf("failed to build presign request: %w", err)
+	}
+
+	signer := v4.NewSigner(s.session.Config.Credentials, func(signer *v4.Signer) {
+		// S3 не допускает повторного экранирования пути
+		signer.DisableURIPathEscaping = true
+	})
+	signTime := time.Now().Truncate(max(s.urls.PresignTTL/2, time.Minute))
+	if _, err := signer.Presign(req.HTTPRequest, nil, s3.ServiceName, s.cfg.Region, s.urls.PresignTTL, signTime); err != nil {
+		return "", fmt.Errorf("failed to presign url: %w", err)
+	}
+	return req.HTTPRequest.URL.String(), nil
+}
+
+// DeletePrefix удаляет все объекты под rootDir/prefix пачками по 1000 ключей
+func (s *Storage) DeletePrefix(ctx context.Context, prefix string) error {
+	fullPrefix := s.fullKey(prefix)
+
+	var deleteErr error
+	err := s.s3Client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: &s.cfg.Bucket,
+		Prefix: aws.String(fullPrefix),
+	}, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		if len(page.Contents) == 0 {
+			return true
+		}
+
+		objects := make([]*s3.ObjectIdentifier, 0, len(page.Contents))
+		for _, obj := range page.Contents {
+			objects = append(objects, &s3.ObjectIdentifier{Key: obj.Key})
+		}
+		deleteErr = s.deleteObjects(ctx, objects)
+		return deleteErr == nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch image from URL: %w", err)
+		return fmt.Errorf("failed to list objects under %s: %w", fullPrefix, err)
+	}
+	if deleteErr != nil {
+		return deleteErr
 	}
-	defer resp.Body.Close()
 
-	buf := new(bytes.Buffer)
-	_, err = io.Copy(buf, resp.Body)
+	slogx.Info(ctx, "Deleted images by prefix", slog.String("prefix", fullPrefix))
+	return nil
+}
+
+// List возвращает все объекты под rootDir/prefix, ключи - относительно rootDir
+func (s *Storage) List(ctx context.Context, prefix string) ([]repo.StoredObject, error) {
+	fullPrefix := s.fullKey(prefix)
+
+	var objects []repo.StoredObject
+	err := s.s3Client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: &s.cfg.Bucket,
+		Prefix: aws.String(fullPrefix),
+	}, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		for _, obj := range page.Contents {
+			key := aws.StringValue(obj.Key)
+			objects = append(objects, repo.StoredObject{
+				Key:        strings.TrimPrefix(key, s.rootDir+"/"),
+				Size:       aws.Int64Value(obj.Size),
+				ModifiedAt: aws.TimeValue(obj.LastModified),
+			})
+		}
+		return true
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to read image data: %w", err)
+		return nil, fmt.Errorf("failed to list objects under %s: %w", fullPrefix, err)
+	}
+	return objects, nil
+}
+
+// Delete удаляет объекты по ключам, полученным из List
+func (s *Storage) Delete(ctx context.Context, keys []string) error {
+	for start := 0; start < len(keys); start += deleteBatchSize {
+		batch := keys[start:min(start+deleteBatchSize, len(keys))]
+		objects := make([]*s3.ObjectIdentifier, 0, len(batch))
+		for _, key := range batch {
+			objects = append(objects, &s3.ObjectIdentifier{Key: aws.String(s.fullKey(key))})
+		}
+		if err := s.deleteObjects(ctx, objects); err != nil {
+			return err
+		}
 	}
+	return nil
+}
+
+// deleteBatchSize - предел ключей в одном DeleteObjects
+const deleteBatchSize = 1000
+
+func (s *Storage) deleteObjects(ctx context.Context, objects []*s3.ObjectIdentifier) error {
+	out, err := s.s3Client.DeleteObjectsWithContext(ctx, &s3.DeleteObjectsInput{
+		Bucket: &s.cfg.Bucket,
+		Delete: &s3.Delete{Objects: objects, Quiet: aws.Bool(true)},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete objects: %w", err)
+	}
+	if len(out.Errors) > 0 {
+		return fmt.Errorf("failed to delete %d objects, first: %s", len(out.Errors), aws.StringValue(out.Errors[0].Message))
+	}
+	return nil
+}
 
-	return buf.Bytes(), nil
+func (s *Storage) fullKey(key string) string {
+	return fmt.Sprintf("%s/%s", s.rootDir, key)
 }
@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+	"github.com/shampsdev/go-telegram-template/pkg/utils/slogx"
+)
+
+// brandRefreshInterval - как часто перечитывать справочник: изменения из админки
+// должны доходить до всех экземпляров сервера, а не только до принявшего запрос
+const brandRefreshInterval = 5 * time.Minute
+
+// ErrEmptyBrandDictionary - в базе нет ни одной марки. Начальный справочник
+// создает миграция, поэтому пустой справочник означает ненакаченные миграции
+var ErrEmptyBrandDictionary = errors.New("brand dictionary is empty")
+
+// BrandDictionary - получатель справочника марок, например реестр парсеров
+type BrandDictionary interface {
+	SetBrands(brands []*domain.Brand)
+}
+
+type Brand struct {
+	brandRepo repo.Brand
+	dict      BrandDictionary
+}
+
+// NewBrand загружает справочник в dict. Справочник хранится только в базе,
+// поэтому без него процесс не запускается
+func NewBrand(ctx context.Context, brandRepo repo.Brand, dict BrandDictionary) (*Brand, error) {
+	b := &Brand{
+		brandRepo: brandRepo,
+		dict:      dict,
+	}
+
+	brands, err := brandRepo.Filter(ctx, &domain.FilterBrand{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load brand dictionary: %w", err)
+	}
+	if len(brands) == 0 {
+		return nil, ErrEmptyBrandDictionary
+	}
+	dict.SetBrands(brands)
+	return b, nil
+}
+
+func (b *Brand) ListBrands(ctx context.Context) ([]*domain.Brand, error) {
+	return b.brandRepo.Filter(ctx, &domain.FilterBrand{})
+}
+
+func (b *Brand) GetBrand(ctx context.Context, id string) (*domain.Brand, error) {
+	return repo.First(b.brandRepo.Filter)(ctx, &domain.FilterBrand{ID: &id})
+}
+
+func (b *Brand) CreateBrand(ctx Context, brand *domain.CreateBrand) (*domain.Brand, error) {
+	id, err := b.brandRepo.Create(ctx, brand)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create brand: %w", err)
+	}
+	b.reload(ctx)
+	return b.GetBrand(ctx, id)
+}
+
+func (b *Brand) PatchBrand(ctx Context, id string, brand *domain.PatchBrand) (*domain.Brand, error) {
+	if err := b.brandRepo.Patch(ctx, id, brand); err != nil {
+		return nil, fmt.Errorf("failed to patch brand: %w", err)
+	}
+	b.reload(ctx)
+	return b.GetBrand(ctx, id)
+}
+
+func (b *Brand) DeleteBrand(ctx Context, id string) error {
+	if err := b.brandRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete brand: %w", err)
+	}
+	b.reload(ctx)
+	return nil
+}
+
+func (b *Brand) CreateModel(ctx Context, brandID string, model *domain.CreateBrandModel) (*domain.Brand, error) {
+	if _, err := b.GetBrand(ctx, brandID); err != nil {
+		return nil, err
+	}
+	if _, err := b.brandRepo.CreateModel(ctx, brandID, model); err != nil {
+		return nil, fmt.Errorf("failed to create model: %w", err)
+	}
+	b.reload(ctx)
+	return b.GetBrand(ctx, brandID)
+}
+
+func (b *Brand) PatchModel(ctx Context, brandID, id string, model *domain.PatchBrandModel) (*domain.Brand, error) {
+	if err := b.brandRepo.PatchModel(ctx, brandID, id, model); err != nil {
+		return nil, fmt.Errorf("failed to patch model: %w", err)
+	}
+	b.reload(ctx)
+	return b.GetBrand(ctx, brandID)
+}
+
+func (b *Brand) DeleteModel(ctx Context, brandID, id string) error {
+	if err := b.brandRepo.DeleteModel(ctx, brandID, id); err != nil {
+		return fmt.Errorf("failed to delete model: %w", err)
+	}
+	b.reload(ctx)
+	return nil
+}
+
+// reload передает актуальный справочник парсеру. Ошибку только логируем:
+// парсер продолжит работать с прошлой версией справочника
+func (b *Brand) reload(ctx context.Context) {
+	brands, err := b.brandRepo.Filter(ctx, &domain.FilterBrand{})
+	if err != nil {
+		slogx.FromCtx(ctx).Error("failed to load brand dictionary", slogx.Err(err))
+		return
+	}
+	b.dict.SetBrands(brands)
+}
+
+// RunRefresher перечитывает справочник каждые brandRefreshInterval до отмены ctx.
+// Справочник нужен воркерам импорта, поэтому запускается только в REST-сервере (cmd/server)
+func (b *Brand) RunRefresher(ctx context.Context) {
+	ticker := time.NewTicker(brandRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b.reload(ctx)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+)
+
+type Favorite struct {
+	favoriteRepo   repo.Favorite
+	motorcycleCase *Motorcycle
+}
+
+func NewFavorite(favoriteRepo repo.Favorite, motorcycleCase *Motorcycle) *Favorite {
+	return &Favorite{
+		favoriteRepo:   favoriteRepo,
+		motorcycleCase: motorcycleCase,
+	}
+}
+
+// AddFavorite добавляет мотоцикл в избранное пользователя из ctx. Архивные мотоциклы
+// покупателям не видны, поэтому для них, как и для несуществующих, - ErrNotFound
+func (f *Favorite) AddFavorite(ctx Context, motorcycleID string) error {
+	motorcycle, err := f.motorcycleCase.GetMotorcycle(ctx, motorcycleID)
+	if err != nil {
+		return err
+	}
+	if motorcycle.ArchivedAt != nil {
+		return repo.ErrNotFound
+	}
+
+	if err := f.favoriteRepo.Add(ctx, ctx.User.ID, motorcycleID); err != nil {
+		return fmt.Errorf("failed to add favorite: %w", err)
+	}
+	return nil
+}
+
+// RemoveFavorite убирает мотоцикл из избранного; если его там не было, ничего не делает
+func (f *Favorite) RemoveFavorite(ctx Context, motorcycleID string) error {
+	if err := f.favoriteRepo.Remove(ctx, ctx.User.ID, motorcycleID); err != nil {
+		return fmt.Errorf("failed to remove favorite: %w", err)
+	}
+	return nil
+}
+
+// ListFavorites возвращает избранное пользователя с данными мотоциклов, последние
+// добавленные первыми. Мотоциклы, убранные в архив, не показываются
+func (f *Favorite) ListFavorites(ctx Context) ([]*domain.Favorite, error) {
+	favorites, err := f.favoriteRepo.Filter(ctx, ctx.User.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get favorites: %w", err)
+	}
+	if len(favorites) == 0 {
+		return favorites, nil
+	}
+
+	ids := make([]string, 0, len(favorites))
+	for _, favorite := range favorites {
+		ids = append(ids, favorite.MotorcycleID)
+	}
+	motorcycles, err := f.motorcycleCase.GetMotorcycles(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*domain.Motorcycle, len(motorcycles))
+	for _, motorcycle := range motorcycles {
+		byID[motorcycle.ID] = motorcycle
+	}
+
+	result := make([]*domain.Favorite, 0, len(favorites))
+	for _, favorite := range favorites {
+		if motorcycle, ok := byID[favorite.MotorcycleID]; ok {
+			favorite.Motorcycle = motorcycle
+			result = append(result, favorite)
+		}
+	}
+	return result, nil
+}
+
+// FillFavoriteCounts проставляет мотоциклам, сколько пользователей добавили их в избранное.
+// Счетчик видят только админы, поэтому вызывается только для них
+func (f *Favorite) FillFavoriteCounts(ctx context.Context, motorcycles ...*domain.Motorcycle) error {
+	ids := make([]string, 0, len(motorcycles))
+	for _, motorcycle := range motorcycles {
+		ids = append(ids, motorcycle.ID)
+	}
+	counts, err := f.favoriteRepo.Counts(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to count favorites: %w", err)
+	}
+	for _, motorcycle := range motorcycles {
+		count := counts[motorcycle.ID]
+		motorcycle.FavoriteCount = &count
+	}
+	return nil
+}
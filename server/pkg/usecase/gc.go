@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/shampsdev/go-telegram-template/pkg/config"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+	"github.com/shampsdev/go-telegram-template/pkg/utils/slogx"
+)
+
+// ErrGCUnsafe - ни один файл не совпал со ссылками из БД. Скорее всего, поменялись
+// настройки хранилища (бакет, S3_ROOT_DIRECTORY, STORAGE_IMAGES_PATH), и удалять ничего нельзя
+var ErrGCUnsafe = errors.New("no stored object matches a referenced key, refusing to collect")
+
+// GC удаляет из хранилища файлы, на которые не ссылаются фотографии мотоциклов
+// и аватары: остатки удаленных мотоциклов, неудачных импортов и старых аватаров
+type GC struct {
+	refs    repo.StorageRefs
+	storage repo.ImageStorage
+	cfg     config.GCConfig
+}
+
+func NewGC(refs repo.StorageRefs, storage repo.ImageStorage, cfg config.GCConfig) *GC {
+	return &GC{
+		refs:    refs,
+		storage: storage,
+		cfg:     cfg,
+	}
+}
+
+// Run сравнивает файлы хранилища со ссылками из БД и удаляет лишние, если не dryRun
+func (g *GC) Run(ctx context.Context, dryRun bool) (*domain.GCReport, error) {
+	report := &domain.GCReport{
+		DryRun:    dryRun,
+		StartedAt: time.Now(),
+		Orphaned:  []domain.GCObject{},
+	}
+
+	refs, err := g.refs.ReferencedKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load referenced keys: %w", err)
+	}
+	referenced := make(map[string]bool, len(refs))
+	for _, ref := range refs {
+		referenced[ref] = true
+		if repo.IsAbsoluteURL(ref) {
+			// Ссылку, которую миграция не перевела в ключ, считаем ссылкой на любой
+			// ключ, которым она заканчивается: лучше оставить лишний файл, чем удалить нужный
+			for i, c := range ref {
+				if c == '/' {
+					referenced[ref[i+1:]] = true
+				}
+			}
+		}
+	}
+
+	// Ссылки читаем до листинга: файл, загруженный между этими шагами, защищает MinAge
+	objects, err := g.storage.List(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list storage: %w", err)
+	}
+	report.Scanned = len(objects)
+
+	keys := make([]string, 0)
+	for _, obj := range objects {
+		switch {
+		case referenced[obj.Key]:
+			report.Referenced++
+		case report.StartedAt.Sub(obj.ModifiedAt) < g.cfg.MinAge:
+			report.SkippedYoung++
+		default:
+			keys = append(keys, obj.Key)
+			report.OrphanedSize += obj.Size
+			report.Orphaned = append(report.Orphaned, domain.GCObject{
+				Key:        obj.Key,
+				Size:       obj.Size,
+				ModifiedAt: obj.ModifiedAt,
+			})
+		}
+	}
+
+	if len(refs) > 0 && len(objects) > 0 && report.Referenced == 0 {
+		return nil, ErrGCUnsafe
+	}
+
+	if !dryRun && len(keys) > 0 {
+		if err := g.storage.Delete(ctx, keys); err != nil {
+			return nil, fmt.Errorf("failed to delete orphaned objects: %w", err)
+		}
+		report.Deleted = len(keys)
+	}
+
+	report.Duration = time.Since(report.StartedAt)
+	slogx.Info(ctx, "storage gc finished",
+		slog.Bool("dry_run", dryRun),
+		slog.Int("scanned", report.Scanned),
+		slog.Int("referenced", report.Referenced),
+		slog.Int("orphaned", len(report.Orphaned)),
+		slog.Int64("orphaned_bytes", report.OrphanedSize),
+		slog.Int("deleted", report.Deleted),
+	)
+	return report, nil
+}
+
+// RunScheduler запускает сборку каждые cfg.Interval до отмены ctx; при Interval 0 сразу выходит.
+// Запускается только в REST-сервере (cmd/server), чтобы бот не собирал мусор параллельно
+func (g *GC) RunScheduler(ctx context.Context) {
+	if g.cfg.Interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(g.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := g.Run(ctx, g.cfg.DryRun); err != nil {
+				slogx.FromCtxWithErr(ctx, err).Error("storage gc failed")
+			}
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/shampsdev/go-telegram-template/pkg/config"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/parser"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+	"github.com/shampsdev/go-telegram-template/pkg/utils/slogx"
+)
+
+const (
+	// importLease - на сколько воркер арендует задачу; каждое обновление прогресса продлевает аренду
+	importLease = 10 * time.Minute
+	// maxImportBackoff ограничивает экспоненциальную задержку между попытками
+	maxImportBackoff = 30 * time.Minute
+
+	defaultImportListLimit = 20
+	maxImportListLimit     = 100
+)
+
+var ErrUnsupportedSource = errors.New("unsupported source")
+
+// Import ставит импорт по ссылке в очередь и выполняет задачи очереди
+// в фоновых воркерах, см. RunWorkers
+type Import struct {
+	importRepo  repo.ImportJob
+	userRepo    repo.User
+	motorcycles *Motorcycle
+	cfg         config.ImportConfig
+}
+
+func NewImport(importRepo repo.ImportJob, userRepo repo.User, motorcycles *Motorcycle, cfg config.ImportConfig) *Import {
+	return &Import{
+		importRepo:  importRepo,
+		userRepo:    userRepo,
+		motorcycles: motorcycles,
+		cfg:         cfg,
+	}
+}
+
+// RunWorkers запускает cfg.Workers воркеров и ждет, пока все они не остановятся по отмене ctx.
+// Запускается только в REST-сервере (cmd/server)
+func (i *Import) RunWorkers(ctx context.Context) {
+	var wg sync.WaitGroup
+	for n := range i.cfg.Workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			i.worker(ctx, n)
+		}()
+	}
+	wg.Wait()
+}
+
+// Enqueue ставит задачу импорта в очередь и сразу ее возвращает
+func (i *Import) Enqueue(ctx Context, url string) (*domain.ImportJob, error) {
+	if !i.motorcycles.IsSupportedSource(url) {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedSource, url)
+	}
+
+	job := &domain.CreateImportJob{
+		SourceURL:   url,
+		MaxAttempts: max(i.cfg.MaxAttempts, 1),
+	}
+	if ctx.User != nil {
+		job.CreatedBy = &ctx.User.ID
+	}
+
+	id, err := i.importRepo.Create(ctx, job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enqueue import: %w", err)
+	}
+	return i.GetJob(ctx, id)
+}
+
+func (i *Import) GetJob(ctx context.Context, id string) (*domain.ImportJob, error) {
+	return repo.First(i.importRepo.Filter)(ctx, &domain.FilterImportJob{ID: &id})
+}
+
+func (i *Import) ListJobs(ctx context.Context, filter *domain.FilterImportJob) ([]*domain.ImportJob, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultImportListLimit
+	}
+	filter.Limit = min(filter.Limit, maxImportListLimit)
+	return i.importRepo.Filter(ctx, filter)
+}
+
+func (i *Import) worker(ctx context.Context, n int) {
+	log := slogx.FromCtx(ctx).With("worker", n)
+	log.Info("import worker started")
+	for {
+		job, err := i.importRepo.Claim(ctx, importLease)
+		if err == nil {
+			i.run(ctx, job)
+			continue
+		}
+		if !errors.Is(err, repo.ErrNotFound) {
+			log.Error("failed to claim import job", slogx.Err(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(i.cfg.PollInterval):
+		}
+	}
+}
+
+func (i *Import) run(ctx context.Context, job *domain.ImportJob) {
+	log := slogx.FromCtx(ctx).With("job_id", job.ID, "url", job.SourceURL, "attempt", job.Attempts)
+
+	// Задача могла вернуться по истекшей аренде уже после последней попытки
+	if job.Attempts > job.MaxAttempts {
+		i.fail(ctx, job, "attempts exhausted")
+		return
+	}
+
+	progress := func(stage domain.ImportJobStage, done, total int) {
+		if err := i.importRepo.Progress(ctx, job.ID, stage, done, total, importLease); err != nil {
+			log.Warn("failed to update import progress", slogx.Err(err))
+		}
+	}
+
+	motorcycle, err := i.motorcycles.importFromURL(i.jobContext(ctx, job), job.ID, job.SourceURL, progress)
+	if err == nil {
+		if err := i.importRepo.Succeed(ctx, job.ID, motorcycle.ID); err != nil {
+			log.Error("failed to complete import job", slogx.Err(err))
+			return
+		}
+		log.Info("import job succeeded", "motorcycle_id", motorcycle.ID)
+		return
+	}
+
+	// Неподдерживаемый сайт не начнет поддерживаться от повтора
+	if errors.Is(err, parser.ErrUnsupportedHost) || job.Attempts >= job.MaxAttempts {
+		i.fail(ctx, job, err.Error())
+		return
+	}
+
+	delay := importBackoff(i.cfg.RetryBackoff, job.Attempts)
+	log.Warn("import job failed, will retry", slogx.Err(err), "delay", delay)
+	if err := i.importRepo.Retry(ctx, job.ID, err.Error(), delay); err != nil {
+		log.Error("failed to reschedule import job", slogx.Err(err))
+	}
+}
+
+func (i *Import) fail(ctx context.Context, job *domain.ImportJob, reason string) {
+	log := slogx.FromCtx(ctx).With("job_id", job.ID, "url", job.SourceURL)
+	log.Error("import job failed", "reason", reason)
+	if err := i.importRepo.Fail(ctx, job.ID, reason); err != nil {
+		log.Error("failed to mark import job failed", slogx.Err(err))
+	}
+}
+
+// jobContext выполняет задачу от имени админа, который ее поставил
+func (i *Import) jobContext(ctx context.Context, job *domain.ImportJob) Context {
+	jobCtx := Context{Context: ctx}
+	if job.CreatedBy == nil {
+		return jobCtx
+	}
+	user, err := repo.First(i.userRepo.Filter)(ctx, &domain.FilterUser{ID: job.CreatedBy})
+	if err != nil {
+		slogx.FromCtx(ctx).Warn("failed to load import job author", slogx.Err(err), "job_id", job.ID)
+		return jobCtx
+	}
+	jobCtx.User = user
+	return jobCtx
+}
+
+// importBackoff возвращает задержку перед следующей попыткой: base, 2*base, 4*base...
+func importBackoff(base time.Duration, attempt int) time.Duration {
+	delay := base
+	for range attempt - 1 {
+		delay *= 2
+		if delay >= maxImportBackoff {
+			return maxImportBackoff
+		}
+	}
+	return delay
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+)
+
+func TestImportBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 0, want: time.Second},
+		{attempt: 1, want: time.Second},
+		{attempt: 2, want: 2 * time.Second},
+		{attempt: 3, want: 4 * time.Second},
+		{attempt: 100, want: maxImportBackoff},
+	}
+	for _, tt := range tests {
+		if got := importBackoff(time.Second, tt.attempt); got != tt.want {
+			t.Errorf("importBackoff(1s, %d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+type countingParser struct {
+	MotorcycleParser
+	parsed int
+}
+
+func (p *countingParser) ParseMotorcycle(context.Context, string) (*domain.ParsedMotorcycleData, error) {
+	p.parsed++
+	return &domain.ParsedMotorcycleData{Name: "Honda CB400", Images: []string{"https://example.com/1.jpg"}}, nil
+}
+
+func TestImportFromURLRepeatedJob(t *testing.T) {
+	tests := []struct {
+		name string
+		// leftover - мотоцикл, оставшийся от прошлой попытки задачи
+		leftover    *domain.Motorcycle
+		wantParsed  int
+		wantCreates int
+		wantDeleted bool
+	}{
+		{name: "first attempt", wantParsed: 1, wantCreates: 1},
+		{
+			name:     "created before the job was completed",
+			leftover: &domain.Motorcycle{ID: "m1", Photos: []*domain.MotorcyclePhoto{{ID: "p1", ObjectKey: "motorcycles/m1/a"}}},
+		},
+		{
+			name:        "interrupted before photos were added",
+			leftover:    &domain.Motorcycle{ID: "m1", Photos: []*domain.MotorcyclePhoto{}},
+			wantParsed:  1,
+			wantCreates: 1,
+			wantDeleted: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &createRecorder{created: tt.leftover, audits: map[string][]*domain.CreateAuditEntry{}}
+			parser := &countingParser{}
+			m := &Motorcycle{motorcycleRepo: recorder, storage: &savedImagesStorage{}, parser: parser}
+			noProgress := func(domain.ImportJobStage, int, int) {}
+
+			motorcycle, err := m.importFromURL(Context{Context: context.Background()}, "job1", "https://example.com/lot/1", noProgress)
+			if err != nil {
+				t.Fatalf("importFromURL() error = %v", err)
+			}
+			if motorcycle.ID != "m1" || len(motorcycle.Photos) == 0 {
+				t.Errorf("importFromURL() = %+v, want m1 with photos", motorcycle)
+			}
+			if parser.parsed != tt.wantParsed || recorder.creates != tt.wantCreates {
+				t.Errorf("parsed %d, created %d times, want %d and %d", parser.parsed, recorder.creates, tt.wantParsed, tt.wantCreates)
+			}
+			if deleted := recorder.deletes > 0; deleted != tt.wantDeleted {
+				t.Errorf("leftover deleted = %v, want %v", deleted, tt.wantDeleted)
+			}
+		})
+	}
+}
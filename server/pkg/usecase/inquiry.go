@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+)
+
+// InquiryReplyData - префикс данных кнопки «Ответить», за ним следует ID вопроса
+const InquiryReplyData = "inq:reply:"
+
+// Inquiry - вопросы покупателей о мотоциклах. Вопрос уходит админам в бот,
+// ответ админа бот отправляет покупателю
+type Inquiry struct {
+	inquiryRepo   repo.Inquiry
+	userRepo      repo.User
+	motorcycles   *Motorcycle
+	notifications *Notification
+}
+
+func NewInquiry(inquiryRepo repo.Inquiry, userRepo repo.User, motorcycles *Motorcycle, notifications *Notification) *Inquiry {
+	return &Inquiry{
+		inquiryRepo:   inquiryRepo,
+		userRepo:      userRepo,
+		motorcycles:   motorcycles,
+		notifications: notifications,
+	}
+}
+
+// Ask сохраняет вопрос пользователя из ctx и пересылает его админам. Вопрос без доставки
+// бесполезен, поэтому он сохраняется в одной транзакции с сообщениями админам:
+// после ошибки повтор не создаст дубль
+func (i *Inquiry) Ask(ctx Context, motorcycleID, text string) (*domain.Inquiry, error) {
+	motorcycle, err := i.motorcycles.GetMotorcycle(ctx, motorcycleID)
+	if err != nil {
+		return nil, err
+	}
+	if motorcycle.ArchivedAt != nil {
+		return nil, repo.ErrNotFound
+	}
+
+	forward, err := i.notifications.ForAdmins(ctx, domain.CreateNotification{
+		Text: fmt.Sprintf("❓ Вопрос о мотоцикле\n\n%s\nПокупатель: %s\n\n%s", motorcycle.Title, customerName(ctx.User), text),
+	})
+	if err != nil {
+		return nil, err
+	}
+	id, err := i.inquiryRepo.Create(ctx, &domain.CreateInquiry{
+		MotorcycleID: motorcycleID,
+		UserID:       ctx.User.ID,
+		Text:         text,
+	}, func(id string) []*domain.CreateNotification {
+		for _, notification := range forward {
+			notification.Buttons = []domain.NotificationButton{
+				{Text: "💬 Ответить", Data: InquiryReplyData + id},
+			}
+		}
+		return forward
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create inquiry: %w", err)
+	}
+	return i.GetInquiry(ctx, id)
+}
+
+func (i *Inquiry) GetInquiry(ctx context.Context, id string) (*domain.Inquiry, error) {
+	return repo.First(i.inquiryRepo.Filter)(ctx, &domain.FilterInquiry{ID: &id})
+}
+
+// Answer сохраняет ответ админа из ctx и отправляет его покупателю.
+// Отвечать можно несколько раз, в вопросе хранится последний ответ
+func (i *Inquiry) Answer(ctx Context, id, answer string) (*domain.Inquiry, error) {
+	inquiry, err := i.GetInquiry(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	customer, err := repo.First(i.userRepo.Filter)(ctx, &domain.FilterUser{ID: &inquiry.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+	title := inquiry.MotorcycleID
+	if motorcycle, err := i.motorcycles.GetMotorcycle(ctx, inquiry.MotorcycleID); err == nil {
+		title = motorcycle.Title
+	}
+
+	if err := i.inquiryRepo.Answer(ctx, id, ctx.User.ID, answer); err != nil {
+		return nil, fmt.Errorf("failed to save answer: %w", err)
+	}
+	err = i.notifications.Enqueue(ctx, &domain.CreateNotification{
+		ChatID: customer.TelegramID,
+		Text:   fmt.Sprintf("💬 Ответ на ваш вопрос о «%s»:\n\n%s", title, answer),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return i.GetInquiry(ctx, id)
+}
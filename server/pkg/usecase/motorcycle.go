@@ -2,36 +2,91 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	"github.com/shampsdev/go-telegram-template/pkg/config"
 	"github.com/shampsdev/go-telegram-template/pkg/domain"
 	"github.com/shampsdev/go-telegram-template/pkg/repo"
+	"github.com/shampsdev/go-telegram-template/pkg/utils/slogx"
 )
 
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+)
+
+// ErrInvalidData - патч меняет поля, которых нет в domain.MotorcycleData
+var ErrInvalidData = errors.New("invalid motorcycle data")
+
 type Motorcycle struct {
 	motorcycleRepo repo.Motorcycle
+	auditRepo      repo.Audit
+	eventRepo      repo.MotorcycleEvent
 	storage        repo.ImageStorage
 	parser         MotorcycleParser
+	photos         config.PhotosConfig
 }
 
 type MotorcycleParser interface {
-	ParseMotorcycle(url string) (*domain.ParsedMotorcycleData, error)
+	ParseMotorcycle(ctx context.Context, url string) (*domain.ParsedMotorcycleData, error)
+	// Supports сообщает, есть ли парсер для хоста ссылки
+	Supports(url string) bool
+	// Hosts возвращает список поддерживаемых сайтов
+	Hosts() []string
 }
 
-func NewMotorcycle(motorcycleRepo repo.Motorcycle, storage repo.ImageStorage, parser MotorcycleParser) *Motorcycle {
+func NewMotorcycle(motorcycleRepo repo.Motorcycle, auditRepo repo.Audit, eventRepo repo.MotorcycleEvent, storage repo.ImageStorage, parser MotorcycleParser, photos config.PhotosConfig) *Motorcycle {
 	return &Motorcycle{
 		motorcycleRepo: motorcycleRepo,
+		auditRepo:      auditRepo,
+		eventRepo:      eventRepo,
 		storage:        storage,
 		parser:         parser,
+		photos:         photos,
 	}
 }
 
-func (m *Motorcycle) ListMotorcycles(ctx context.Context, filter *domain.FilterMotorcycle) ([]*domain.Motorcycle, error) {
+// ListMotorcycles возвращает страницу мотоциклов по фильтру вместе с фасетами для уточнения поиска
+func (m *Motorcycle) ListMotorcycles(ctx context.Context, filter *domain.FilterMotorcycle) (*domain.MotorcycleList, error) {
 	if filter == nil {
 		filter = &domain.FilterMotorcycle{}
 	}
+	if filter.Query != nil {
+		applySearchQuery(filter, *filter.Query)
+	}
 	filter.IncludePhotos = true
-	return m.motorcycleRepo.Filter(ctx, filter)
+	if filter.Archived == nil {
+		// Архив по умолчанию скрыт, его показывают только по явному запросу
+		notArchived := false
+		filter.Archived = &notArchived
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultListLimit
+	}
+	filter.Limit = min(filter.Limit, maxListLimit)
+
+	items, nextCursor, err := m.motorcycleRepo.FilterPage(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter motorcycles: %w", err)
+	}
+	if err := m.resolvePhotoURLs(items...); err != nil {
+		return nil, err
+	}
+	// Фасеты зависят только от фильтра, поэтому считаются один раз - для первой страницы
+	var facets *domain.MotorcycleFacets
+	if filter.Cursor == "" {
+		facets, err = m.motorcycleRepo.Facets(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count facets: %w", err)
+		}
+	}
+
+	return &domain.MotorcycleList{
+		Items:      items,
+		Facets:     facets,
+		NextCursor: nextCursor,
+	}, nil
 }
 
 func (m *Motorcycle) GetMotorcycle(ctx context.Context, id string) (*domain.Motorcycle, error) {
@@ -39,100 +94,353 @@ func (m *Motorcycle) GetMotorcycle(ctx context.Context, id string) (*domain.Moto
 		ID:            &id,
 		IncludePhotos: true,
 	}
-	return repo.First(m.motorcycleRepo.Filter)(ctx, filter)
+	motorcycle, err := repo.First(m.motorcycleRepo.Filter)(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.resolvePhotoURLs(motorcycle); err != nil {
+		return nil, err
+	}
+	return motorcycle, nil
+}
+
+// GetMotorcycles возвращает неархивные мотоциклы с указанными ID в порядке каталога
+func (m *Motorcycle) GetMotorcycles(ctx context.Context, ids []string) ([]*domain.Motorcycle, error) {
+	notArchived := false
+	filter := &domain.FilterMotorcycle{
+		IDs:           ids,
+		Archived:      &notArchived,
+		IncludePhotos: true,
+	}
+	motorcycles, err := m.motorcycleRepo.Filter(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter motorcycles: %w", err)
+	}
+	if err := m.resolvePhotoURLs(motorcycles...); err != nil {
+		return nil, err
+	}
+	return motorcycles, nil
+}
+
+// resolvePhotoURLs заполняет ссылки фотографий по ключам: в БД хранятся только ключи,
+// а вид ссылки зависит от STORAGE_URL_STRATEGY
+func (m *Motorcycle) resolvePhotoURLs(motorcycles ...*domain.Motorcycle) error {
+	for _, motorcycle := range motorcycles {
+		for _, photo := range motorcycle.Photos {
+			url, err := m.storage.URL(photo.ObjectKey)
+			if err != nil {
+				return fmt.Errorf("failed to resolve photo url: %w", err)
+			}
+			photo.S3URL = url
+
+			for i := range photo.Variants {
+				url, err := m.storage.URL(photo.Variants[i].Key)
+				if err != nil {
+					return fmt.Errorf("failed to resolve photo variant url: %w", err)
+				}
+				photo.Variants[i].URL = url
+			}
+		}
+	}
+	return nil
 }
 
 func (m *Motorcycle) CreateMotorcycle(ctx Context, createMotorcycle *domain.CreateMotorcycle) (*domain.Motorcycle, error) {
+	return m.createMotorcycle(ctx, createMotorcycle, nil)
+}
+
+// importProgress сообщает о ходе импорта: шаг и сколько фотографий из total уже загружено
+type importProgress func(stage domain.ImportJobStage, done, total int)
+
+// createMotorcycle создает мотоцикл вместе с фотографиями по принципу "все или ничего":
+// если фотографий не удалось загрузить больше, чем допускает политика, запись
+// мотоцикла и уже загруженные объекты motorcycles/<id>/ удаляются
+func (m *Motorcycle) createMotorcycle(ctx Context, createMotorcycle *domain.CreateMotorcycle, progress importProgress) (*domain.Motorcycle, error) {
+	if progress == nil {
+		progress = func(domain.ImportJobStage, int, int) {}
+	}
+
 	// Сохраняем исходные URL фотографий
 	originalPhotoURLs := createMotorcycle.PhotoURLs
 	
-	// Сначала создаем мотоцикл с пустым массивом фотографий, чтобы получить ID
+	// Сначала создаем мотоцикл с пустым массивом фотографий, чтобы получить ID.
+	// Запись о создании пишет последний шаг: без фотографий - сам Create, иначе AddPhotos
 	createMotorcycle.PhotoURLs = []string{}
-	id, err := m.motorcycleRepo.Create(ctx, createMotorcycle)
+	var createAudit repo.AuditFunc
+	if len(originalPhotoURLs) == 0 {
+		createAudit = m.auditCreateFunc(ctx)
+	}
+	id, err := m.motorcycleRepo.Create(ctx, createMotorcycle, createAudit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create motorcycle: %w", err)
 	}
 
-	// Теперь загружаем фотографии в S3 с правильным ключом на основе ID мотоцикла
-	photoURLs := make([]string, 0, len(originalPhotoURLs))
-	for i, photoURL := range originalPhotoURLs {
-		// Используем ID мотоцикла и индекс фотографии для создания уникального ключа
-		key := fmt.Sprintf("motorcycles/%s/%d", id, i)
-		s3URL, err := m.storage.SaveImageByURL(ctx, photoURL, key)
-		if err != nil {
-			return nil, fmt.Errorf("failed to save photo %d: %w", i, err)
+	// Теперь загружаем фотографии в хранилище под префиксом мотоцикла.
+	// Результаты SaveImagesByURL идут в порядке images, поэтому порядок фотографий сохраняется
+	progress(domain.ImportJobStagePhotos, 0, len(originalPhotoURLs))
+	images := make([]repo.ImageSource, 0, len(originalPhotoURLs))
+	for _, photoURL := range originalPhotoURLs {
+		images = append(images, repo.ImageSource{
+			URL: photoURL,
+			Key: newPhotoKey(id),
+		})
+	}
+	saved := m.storage.SaveImagesByURL(ctx, images, func(done, total int) {
+		progress(domain.ImportJobStagePhotos, done, total)
+	})
+
+	photos := make([]*domain.CreateMotorcyclePhoto, 0, len(saved))
+	var photoErrs []error
+	for i, image := range saved {
+		if image.Err != nil {
+			photoErrs = append(photoErrs, fmt.Errorf("failed to save photo %d: %w", i, image.Err))
+			continue
 		}
-		photoURLs = append(photoURLs, s3URL)
+		photos = append(photos, &domain.CreateMotorcyclePhoto{ObjectKey: image.Key, StorageKey: images[i].Key, Variants: image.Variants})
+	}
+
+	if err := m.checkPhotoFailures(len(originalPhotoURLs), photoErrs); err != nil {
+		return nil, m.rollbackCreate(ctx, id, err)
+	}
+	if len(photoErrs) > 0 {
+		slogx.FromCtx(ctx).Warn("some photos were skipped", "motorcycle_id", id, slogx.Err(errors.Join(photoErrs...)))
 	}
 
 	// Добавляем фотографии в БД
-	if len(photoURLs) > 0 {
-		err = m.motorcycleRepo.AddPhotos(ctx, id, photoURLs)
+	if len(photos) > 0 {
+		err = m.motorcycleRepo.AddPhotos(ctx, id, photos, m.auditCreateFunc(ctx))
 		if err != nil {
-			return nil, fmt.Errorf("failed to add photos: %w", err)
+			return nil, m.rollbackCreate(ctx, id, fmt.Errorf("failed to add photos: %w", err))
 		}
 	}
 
 	return m.GetMotorcycle(ctx, id)
 }
 
-func (m *Motorcycle) CreateMotorcycleFromURL(ctx Context, url string) (*domain.Motorcycle, error) {
+// checkPhotoFailures применяет политику допустимых ошибок загрузки:
+// не больше MaxFailed неудачных фотографий и хотя бы одна загруженная
+func (m *Motorcycle) checkPhotoFailures(total int, photoErrs []error) error {
+	if len(photoErrs) == 0 {
+		return nil
+	}
+	if len(photoErrs) > m.photos.MaxFailed || len(photoErrs) == total {
+		return fmt.Errorf("failed to save %d of %d photos: %w", len(photoErrs), total, errors.Join(photoErrs...))
+	}
+	return nil
+}
+
+// rollbackCreate удаляет недосозданный мотоцикл и его фотографии в хранилище.
+// Откат выполняется и при отмене ctx, чтобы не оставлять мусор. В журнал он не пишется:
+// запись о создании появляется только у полностью созданного мотоцикла
+func (m *Motorcycle) rollbackCreate(ctx Context, id string, cause error) error {
+	if err := m.discardMotorcycle(ctx, id); err != nil {
+		slogx.FromCtx(ctx).Error("failed to roll back motorcycle creation", "motorcycle_id", id, slogx.Err(err))
+		return fmt.Errorf("%w (rollback failed: %v)", cause, err)
+	}
+	return cause
+}
+
+// discardMotorcycle удаляет недосозданный мотоцикл вместе с объектами в хранилище, без журнала
+func (m *Motorcycle) discardMotorcycle(ctx Context, id string) error {
+	ctx.Context = context.WithoutCancel(ctx.Context)
+
+	var errs []error
+	if err := m.storage.DeletePrefix(ctx, motorcyclePhotoPrefix(id)); err != nil {
+		errs = append(errs, fmt.Errorf("failed to delete uploaded photos: %w", err))
+	}
+	if err := m.motorcycleRepo.Delete(ctx, id, nil); err != nil {
+		errs = append(errs, fmt.Errorf("failed to delete motorcycle: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// motorcyclePhotoPrefix - префикс ключей всех фотографий мотоцикла в хранилище
+func motorcyclePhotoPrefix(id string) string {
+	return fmt.Sprintf("motorcycles/%s/", id)
+}
+
+// IsSupportedSource сообщает, можно ли импортировать мотоцикл по ссылке
+func (m *Motorcycle) IsSupportedSource(url string) bool {
+	return m.parser.Supports(url)
+}
+
+// SupportedSources возвращает сайты, с которых поддерживается импорт
+func (m *Motorcycle) SupportedSources() []string {
+	return m.parser.Hosts()
+}
+
+// importFromURL разбирает страницу объявления и создает по ней черновик мотоцикла.
+// Вызывается воркером импорта, см. Import. Повтор задачи jobID не создает второй мотоцикл:
+// созданный прошлой попыткой возвращается как есть, а недосозданный удаляется и создается заново
+func (m *Motorcycle) importFromURL(ctx Context, jobID, url string, progress importProgress) (*domain.Motorcycle, error) {
+	imported, err := repo.First(m.motorcycleRepo.Filter)(ctx, &domain.FilterMotorcycle{ImportJobID: &jobID, IncludePhotos: true})
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+	case err != nil:
+		return nil, fmt.Errorf("failed to find imported motorcycle: %w", err)
+	// Фотографии добавляются последним шагом создания, без них попытка оборвалась на середине
+	case len(imported.Photos) > 0:
+		return m.GetMotorcycle(ctx, imported.ID)
+	default:
+		if err := m.discardMotorcycle(ctx, imported.ID); err != nil {
+			return nil, fmt.Errorf("failed to discard interrupted import: %w", err)
+		}
+	}
+
 	// Парсим страницу
-	data, err := m.parser.ParseMotorcycle(url)
+	progress(domain.ImportJobStageParse, 0, 0)
+	data, err := m.parser.ParseMotorcycle(ctx, url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse motorcycle page: %w", err)
 	}
 
-	// Формируем название из данных парсера
-	title := data.Name
-	if data.Year > 0 {
-		title = fmt.Sprintf("%s %d", title, data.Year)
-	}
-
-	// Создаем мотоцикл со статусом draft
+	// Создаем мотоцикл со статусом draft. Название, марка и модель уже
+	// нормализованы парсером по справочнику, год и характеристики храним отдельно
 	createMotorcycle := &domain.CreateMotorcycle{
-		Title:     title,
+		Title:     data.Name,
 		Price:     0, // Цену нужно будет ввести позже
 		Currency:  "RUB",
+		Brand:     data.Brand,
+		Model:     data.Model,
 		Status:    domain.MotorcycleStatusDraft,
-		SourceURL: url,
-		PhotoURLs: data.Images,
+		SourceURL:   url,
+		PhotoURLs:   data.Images,
+		ImportJobID: &jobID,
+	}
+	if data.BrandID != "" {
+		createMotorcycle.BrandID = &data.BrandID
+	}
+	if data.ModelID != "" {
+		createMotorcycle.ModelID = &data.ModelID
+	}
+	if data.Year > 0 {
+		createMotorcycle.Year = &data.Year
+	}
+	if data.Mileage > 0 {
+		createMotorcycle.MileageKm = &data.Mileage
+	}
+	if data.Volume > 0 {
+		createMotorcycle.EngineCC = &data.Volume
 	}
 
-	// Добавляем структурированные данные, если есть
-	if data.Mileage > 0 || data.Volume > 0 || data.FrameNum != "" {
-		motorcycleData := &domain.MotorcycleData{}
-		
-		if data.Mileage > 0 {
-			motorcycleData.Mileage = &data.Mileage
-			motorcycleData.MileageUnit = "км"
-		}
-		if data.Volume > 0 {
-			motorcycleData.Volume = &data.Volume
-			motorcycleData.VolumeUnit = "сс"
+	// Добавляем свободные данные, если есть
+	if data.FrameNum != "" {
+		createMotorcycle.Data = &domain.MotorcycleData{
+			FrameNumber: data.FrameNum,
 		}
-		if data.FrameNum != "" {
-			motorcycleData.FrameNumber = data.FrameNum
-		}
-		
-		createMotorcycle.Data = motorcycleData
 	}
 
-	return m.CreateMotorcycle(ctx, createMotorcycle)
+	return m.createMotorcycle(ctx, createMotorcycle, progress)
 }
 
+// PatchMotorcycle обновляет поля мотоцикла. Смена статуса проходит те же проверки,
+// что и UpdateMotorcycleStatus, и попадает в историю. Черновик публикуется, только
+// если заполнен с учетом самого patchMotorcycle (см. CheckPublish); иначе возвращается
+// PublishError и не сохраняется ничего.
+// С patchMotorcycle.Version изменения применяются только к этой версии (иначе
+// ErrVersionMismatch), без нее - к текущей, см. UpdateMotorcycle
 func (m *Motorcycle) PatchMotorcycle(ctx Context, id string, patchMotorcycle *domain.PatchMotorcycle) (*domain.Motorcycle, error) {
-	err := m.motorcycleRepo.Patch(ctx, id, patchMotorcycle)
+	if patchMotorcycle.Version == nil {
+		return m.UpdateMotorcycle(ctx, id, func(*domain.Motorcycle) (*domain.PatchMotorcycle, error) {
+			return patchMotorcycle, nil
+		})
+	}
+
+	before, err := m.GetMotorcycle(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to patch motorcycle: %w", err)
+		return nil, err
 	}
-	return m.GetMotorcycle(ctx, id)
+	if before.Version != *patchMotorcycle.Version {
+		return nil, ErrVersionMismatch
+	}
+	return m.applyPatch(ctx, before, patchMotorcycle)
 }
 
-func (m *Motorcycle) UpdateMotorcycleStatus(ctx Context, id string, status domain.MotorcycleStatus) (*domain.Motorcycle, error) {
-	patch := &domain.PatchMotorcycle{
-		Status: &status,
+// applyPatch применяет изменения к прочитанной версии мотоцикла before. Поля и статус
+// сохраняются одной транзакцией: если переход не прошел, не меняется ничего
+func (m *Motorcycle) applyPatch(ctx Context, before *domain.Motorcycle, patchMotorcycle *domain.PatchMotorcycle) (*domain.Motorcycle, error) {
+	id := before.ID
+	if err := patchMotorcycle.Data.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidData, err)
 	}
-	return m.PatchMotorcycle(ctx, id, patch)
+	if status := patchMotorcycle.Status; status != nil {
+		if err := checkStatusTransition(before.Status, *status); err != nil {
+			return nil, err
+		}
+		// Черновик проверяем уже с примененными полями: цену и дату часто задают вместе со статусом
+		if before.Status == domain.MotorcycleStatusDraft && *status == domain.MotorcycleStatusAvailable {
+			patched, err := patchedMotorcycle(before, patchMotorcycle)
+			if err != nil {
+				return nil, err
+			}
+			if problems := m.publishProblems(patched); len(problems) > 0 {
+				return nil, &PublishError{Problems: problems}
+			}
+		}
+	}
+
+	patch := *patchMotorcycle
+	patch.Version = nil
+	var changedBy *string
+	if ctx.User != nil {
+		changedBy = &ctx.User.ID
+	}
+	err := m.motorcycleRepo.Patch(ctx, id, &patch, before.Version, changedBy, m.auditFunc(ctx, domain.AuditActionPatch))
+	if errors.Is(err, repo.ErrConflict) {
+		return nil, ErrVersionMismatch
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch motorcycle: %w", err)
+	}
+
+	updated, err := m.GetMotorcycle(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	m.recordEvent(ctx, before, updated)
+	return updated, nil
 }
 
+// patchedMotorcycle возвращает копию before с примененным patch, ничего не сохраняя
+func patchedMotorcycle(before *domain.Motorcycle, patch *domain.PatchMotorcycle) (*domain.Motorcycle, error) {
+	patched := *before
+	if patch.Title != nil {
+		patched.Title = *patch.Title
+	}
+	if patch.Price != nil {
+		patched.Price = *patch.Price
+	}
+	if patch.Currency != nil {
+		patched.Currency = *patch.Currency
+	}
+	if patch.Year != nil {
+		patched.Year = patch.Year
+	}
+	if patch.Brand != nil {
+		patched.Brand = *patch.Brand
+	}
+	if patch.Model != nil {
+		patched.Model = *patch.Model
+	}
+	if patch.BrandID != nil {
+		patched.BrandID = patch.BrandID
+	}
+	if patch.ModelID != nil {
+		patched.ModelID = patch.ModelID
+	}
+	if patch.MileageKm != nil {
+		patched.MileageKm = patch.MileageKm
+	}
+	if patch.EngineCC != nil {
+		patched.EngineCC = patch.EngineCC
+	}
+	if patch.Status != nil {
+		patched.Status = *patch.Status
+	}
+	data, err := patch.Data.Apply(before.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply data patch: %w", err)
+	}
+	patched.Data = data
+	return &patched, nil
+}
@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/utils/slogx"
+)
+
+// ErrNotArchived - удалить навсегда можно только мотоцикл из архива
+var ErrNotArchived = errors.New("motorcycle must be archived before deletion")
+
+// ArchiveMotorcycle убирает мотоцикл из каталога, не удаляя данные и фотографии
+func (m *Motorcycle) ArchiveMotorcycle(ctx Context, id string) (*domain.Motorcycle, error) {
+	return m.setArchived(ctx, id, true)
+}
+
+// RestoreMotorcycle возвращает мотоцикл из архива в каталог
+func (m *Motorcycle) RestoreMotorcycle(ctx Context, id string) (*domain.Motorcycle, error) {
+	return m.setArchived(ctx, id, false)
+}
+
+// DeleteMotorcycle удаляет архивный мотоцикл из БД и все его фотографии из хранилища
+func (m *Motorcycle) DeleteMotorcycle(ctx Context, id string) error {
+	motorcycle, err := m.GetMotorcycle(ctx, id)
+	if err != nil {
+		return err
+	}
+	if motorcycle.ArchivedAt == nil {
+		return ErrNotArchived
+	}
+
+	if err := m.motorcycleRepo.Delete(ctx, id, m.auditFunc(ctx, domain.AuditActionDelete)); err != nil {
+		return fmt.Errorf("failed to delete motorcycle: %w", err)
+	}
+
+	// Запись уже удалена, поэтому ошибку хранилища только логируем: оставшиеся файлы уберет GC
+	if err := m.storage.DeletePrefix(context.WithoutCancel(ctx), motorcyclePhotoPrefix(id)); err != nil {
+		slogx.FromCtxWithErr(ctx, err).Warn("failed to delete motorcycle photos", "motorcycle_id", id)
+	}
+	return nil
+}
+
+func (m *Motorcycle) setArchived(ctx Context, id string, archived bool) (*domain.Motorcycle, error) {
+	action := domain.AuditActionRestore
+	if archived {
+		action = domain.AuditActionArchive
+	}
+	if err := m.motorcycleRepo.SetArchived(ctx, id, archived, m.auditFunc(ctx, action)); err != nil {
+		return nil, fmt.Errorf("failed to update archive: %w", err)
+	}
+	return m.GetMotorcycle(ctx, id)
+}
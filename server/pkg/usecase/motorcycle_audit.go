@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+)
+
+// auditIgnoredFields меняются сами или пишутся в журнал отдельно
+var auditIgnoredFields = []string{"id", "version", "createdAt", "updatedAt", "photos"}
+
+// AuditLog возвращает журнал изменений мотоцикла, в том числе уже удаленного
+func (m *Motorcycle) AuditLog(ctx Context, id string) ([]*domain.AuditEntry, error) {
+	entries, err := m.auditRepo.Filter(ctx, &domain.FilterAuditEntry{MotorcycleID: &id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit log: %w", err)
+	}
+	return entries, nil
+}
+
+// auditFunc строит записи журнала action от имени админа из ctx. Записи пишутся
+// в транзакции изменения, поэтому ошибка журнала отменяет и само изменение
+func (m *Motorcycle) auditFunc(ctx Context, action domain.AuditAction) repo.AuditFunc {
+	var actorID *string
+	if ctx.User != nil {
+		actorID = &ctx.User.ID
+	}
+	return func(before, after *domain.Motorcycle) ([]*domain.CreateAuditEntry, error) {
+		return auditEntries(actorID, action, before, after)
+	}
+}
+
+// auditCreateFunc пишет запись о создании по итоговому мотоциклу. Ее получает последний шаг
+// создания, поэтому откат недосозданного мотоцикла не оставляет в журнале создание и удаление
+func (m *Motorcycle) auditCreateFunc(ctx Context) repo.AuditFunc {
+	audit := m.auditFunc(ctx, domain.AuditActionCreate)
+	return func(_, after *domain.Motorcycle) ([]*domain.CreateAuditEntry, error) {
+		return audit(nil, after)
+	}
+}
+
+// auditEntries возвращает запись о разнице между before и after, если она есть.
+// Смена статуса при правке полей пишется отдельной записью AuditActionStatus
+func auditEntries(actorID *string, action domain.AuditAction, before, after *domain.Motorcycle) ([]*domain.CreateAuditEntry, error) {
+	changes, err := auditDiff(before, after)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff motorcycle: %w", err)
+	}
+	motorcycle := after
+	if motorcycle == nil {
+		motorcycle = before
+	}
+
+	entries := []*domain.CreateAuditEntry{}
+	add := func(action domain.AuditAction, changes map[string]domain.AuditChange) {
+		if len(changes) == 0 {
+			return
+		}
+		entries = append(entries, &domain.CreateAuditEntry{
+			MotorcycleID: motorcycle.ID,
+			ActorID:      actorID,
+			Action:       action,
+			Changes:      changes,
+		})
+	}
+	if status, ok := changes["status"]; ok && action == domain.AuditActionPatch {
+		delete(changes, "status")
+		add(action, changes)
+		add(domain.AuditActionStatus, map[string]domain.AuditChange{"status": status})
+		return entries, nil
+	}
+	add(action, changes)
+	return entries, nil
+}
+
+// auditDiff сравнивает мотоциклы по JSON-полям, фотографии - по ID и ключу файла
+func auditDiff(before, after *domain.Motorcycle) (map[string]domain.AuditChange, error) {
+	beforeFields, err := auditSnapshot(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := auditSnapshot(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]domain.AuditChange{}
+	for name, value := range beforeFields {
+		if !reflect.DeepEqual(value, afterFields[name]) {
+			changes[name] = domain.AuditChange{Before: value, After: afterFields[name]}
+		}
+	}
+	for name, value := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			changes[name] = domain.AuditChange{After: value}
+		}
+	}
+	return changes, nil
+}
+
+func auditSnapshot(motorcycle *domain.Motorcycle) (map[string]any, error) {
+	fields := map[string]any{}
+	if motorcycle == nil {
+		return fields, nil
+	}
+
+	data, err := json.Marshal(motorcycle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal motorcycle: %w", err)
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal motorcycle: %w", err)
+	}
+	for _, name := range auditIgnoredFields {
+		delete(fields, name)
+	}
+
+	// Ссылки на фото зависят от настроек хранилища, поэтому сравниваем ключи
+	photos := make([]any, 0, len(motorcycle.Photos))
+	for _, photo := range motorcycle.Photos {
+		photos = append(photos, map[string]any{"id": photo.ID, "key": photo.ObjectKey})
+	}
+	if len(photos) > 0 {
+		fields["photos"] = photos
+	}
+	return fields, nil
+}
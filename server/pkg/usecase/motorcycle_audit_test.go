@@ -0,0 +1,323 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+)
+
+func auditTestMotorcycle(change func(m *domain.Motorcycle)) *domain.Motorcycle {
+	m := &domain.Motorcycle{
+		ID:        "m1",
+		Title:     "Honda CB400",
+		Price:     450000,
+		Currency:  "RUB",
+		Status:    domain.MotorcycleStatusDraft,
+		SourceURL: "https://example.com/lot/1",
+		Photos: []*domain.MotorcyclePhoto{
+			{ID: "p1", ObjectKey: "motorcycles/m1/a", S3URL: "https://cdn/a"},
+			{ID: "p2", ObjectKey: "motorcycles/m1/b", S3URL: "https://cdn/b"},
+		},
+		Version:   1,
+		CreatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if change != nil {
+		change(m)
+	}
+	return m
+}
+
+func TestAuditDiff(t *testing.T) {
+	before := auditTestMotorcycle(nil)
+	tests := []struct {
+		name   string
+		change func(m *domain.Motorcycle)
+		want   map[string]domain.AuditChange
+	}{
+		{
+			name: "only service fields",
+			change: func(m *domain.Motorcycle) {
+				m.Version = 2
+				m.UpdatedAt = m.UpdatedAt.Add(time.Hour)
+			},
+			want: map[string]domain.AuditChange{},
+		},
+		{
+			name:   "title",
+			change: func(m *domain.Motorcycle) { m.Title = "Honda CB400 Super Four" },
+			want: map[string]domain.AuditChange{
+				"title": {Before: "Honda CB400", After: "Honda CB400 Super Four"},
+			},
+		},
+		{
+			name: "price and status",
+			change: func(m *domain.Motorcycle) {
+				m.Price = 430000
+				m.Status = domain.MotorcycleStatusAvailable
+			},
+			want: map[string]domain.AuditChange{
+				"price":  {Before: 450000.0, After: 430000.0},
+				"status": {Before: "draft", After: "available"},
+			},
+		},
+		{
+			name:   "field added",
+			change: func(m *domain.Motorcycle) { m.Data = &domain.MotorcycleData{ArrivalDate: "2026-02-01"} },
+			want: map[string]domain.AuditChange{
+				"data": {After: map[string]any{"arrival_date": "2026-02-01"}},
+			},
+		},
+		{
+			// Ссылка зависит от стратегии хранилища и изменением не считается
+			name:   "photo url only",
+			change: func(m *domain.Motorcycle) { m.Photos[0].S3URL = "https://other-cdn/a" },
+			want:   map[string]domain.AuditChange{},
+		},
+		{
+			name: "photos reordered",
+			change: func(m *domain.Motorcycle) {
+				m.Photos[0], m.Photos[1] = m.Photos[1], m.Photos[0]
+			},
+			want: map[string]domain.AuditChange{
+				"photos": {
+					Before: []any{map[string]any{"id": "p1", "key": "motorcycles/m1/a"}, map[string]any{"id": "p2", "key": "motorcycles/m1/b"}},
+					After:  []any{map[string]any{"id": "p2", "key": "motorcycles/m1/b"}, map[string]any{"id": "p1", "key": "motorcycles/m1/a"}},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := auditDiff(before, auditTestMotorcycle(tt.change))
+			if err != nil {
+				t.Fatalf("auditDiff: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("auditDiff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuditDiffCreateAndDelete(t *testing.T) {
+	motorcycle := auditTestMotorcycle(nil)
+
+	created, err := auditDiff(nil, motorcycle)
+	if err != nil {
+		t.Fatalf("auditDiff(nil, m): %v", err)
+	}
+	if got, want := created["title"], (domain.AuditChange{After: "Honda CB400"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("created title = %v, want %v", got, want)
+	}
+
+	deleted, err := auditDiff(motorcycle, nil)
+	if err != nil {
+		t.Fatalf("auditDiff(m, nil): %v", err)
+	}
+	if got, want := deleted["status"], (domain.AuditChange{Before: "draft"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("deleted status = %v, want %v", got, want)
+	}
+
+	for _, name := range auditIgnoredFields {
+		if name == "photos" {
+			continue
+		}
+		if _, ok := created[name]; ok {
+			t.Errorf("created changes contain ignored field %q", name)
+		}
+	}
+}
+
+func TestAuditEntries(t *testing.T) {
+	actorID := "admin"
+	before := auditTestMotorcycle(nil)
+	tests := []struct {
+		name        string
+		action      domain.AuditAction
+		change      func(m *domain.Motorcycle)
+		wantActions []domain.AuditAction
+	}{
+		{name: "nothing changed", action: domain.AuditActionPatch, wantActions: []domain.AuditAction{}},
+		{
+			name:        "fields",
+			action:      domain.AuditActionPatch,
+			change:      func(m *domain.Motorcycle) { m.Title = "Honda CB400 Revo" },
+			wantActions: []domain.AuditAction{domain.AuditActionPatch},
+		},
+		{
+			name:        "status only",
+			action:      domain.AuditActionPatch,
+			change:      func(m *domain.Motorcycle) { m.Status = domain.MotorcycleStatusAvailable },
+			wantActions: []domain.AuditAction{domain.AuditActionStatus},
+		},
+		{
+			// Смена статуса при правке полей идет отдельной записью после полей
+			name:   "fields and status",
+			action: domain.AuditActionPatch,
+			change: func(m *domain.Motorcycle) {
+				m.Price = 1
+				m.Status = domain.MotorcycleStatusAvailable
+			},
+			wantActions: []domain.AuditAction{domain.AuditActionPatch, domain.AuditActionStatus},
+		},
+		{
+			name:   "other actions keep status",
+			action: domain.AuditActionStatus,
+			change: func(m *domain.Motorcycle) {
+				m.Status = domain.MotorcycleStatusAvailable
+			},
+			wantActions: []domain.AuditAction{domain.AuditActionStatus},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := auditEntries(&actorID, tt.action, before, auditTestMotorcycle(tt.change))
+			if err != nil {
+				t.Fatalf("auditEntries: %v", err)
+			}
+			actions := []domain.AuditAction{}
+			for _, entry := range entries {
+				actions = append(actions, entry.Action)
+				if entry.MotorcycleID != "m1" || entry.ActorID != &actorID {
+					t.Errorf("entry = %+v, want motorcycle m1 by %s", entry, actorID)
+				}
+				if _, ok := entry.Changes["status"]; ok && entry.Action == domain.AuditActionPatch {
+					t.Errorf("patch entry contains status: %v", entry.Changes)
+				}
+			}
+			if !reflect.DeepEqual(actions, tt.wantActions) {
+				t.Errorf("actions = %v, want %v", actions, tt.wantActions)
+			}
+		})
+	}
+}
+
+// createRecorder запоминает, с каким журналом вызывались шаги создания мотоцикла
+type createRecorder struct {
+	repo.Motorcycle
+	created  *domain.Motorcycle
+	creates  int
+	deletes  int
+	audits   map[string][]*domain.CreateAuditEntry
+	deleted  bool
+	addedErr error
+}
+
+func (r *createRecorder) record(step string, audit repo.AuditFunc, before, after *domain.Motorcycle) {
+	if audit == nil {
+		return
+	}
+	entries, err := audit(before, after)
+	if err != nil {
+		panic(err)
+	}
+	r.audits[step] = entries
+}
+
+func (r *createRecorder) Create(_ context.Context, motorcycle *domain.CreateMotorcycle, audit repo.AuditFunc) (string, error) {
+	r.creates++
+	r.deleted = false
+	r.created = &domain.Motorcycle{ID: "m1", Title: motorcycle.Title, Status: motorcycle.Status, Photos: []*domain.MotorcyclePhoto{}}
+	r.record("create", audit, nil, r.created)
+	return r.created.ID, nil
+}
+
+func (r *createRecorder) AddPhotos(_ context.Context, _ string, photos []*domain.CreateMotorcyclePhoto, audit repo.AuditFunc) error {
+	if r.addedErr != nil {
+		return r.addedErr
+	}
+	before := *r.created
+	for i, photo := range photos {
+		r.created.Photos = append(r.created.Photos, &domain.MotorcyclePhoto{ID: fmt.Sprintf("p%d", i), ObjectKey: photo.ObjectKey})
+	}
+	r.record("photos", audit, &before, r.created)
+	return nil
+}
+
+func (r *createRecorder) Delete(_ context.Context, _ string, audit repo.AuditFunc) error {
+	r.deleted = true
+	r.deletes++
+	r.record("delete", audit, r.created, nil)
+	return nil
+}
+
+func (r *createRecorder) Filter(context.Context, *domain.FilterMotorcycle) ([]*domain.Motorcycle, error) {
+	if r.created == nil || r.deleted {
+		return []*domain.Motorcycle{}, nil
+	}
+	return []*domain.Motorcycle{r.created}, nil
+}
+
+type savedImagesStorage struct {
+	repo.ImageStorage
+	err error
+}
+
+func (s *savedImagesStorage) SaveImagesByURL(_ context.Context, images []repo.ImageSource, _ func(done, total int)) []repo.SavedImage {
+	saved := make([]repo.SavedImage, 0, len(images))
+	for _, image := range images {
+		saved = append(saved, repo.SavedImage{Key: image.Key, Err: s.err})
+	}
+	return saved
+}
+
+func (s *savedImagesStorage) URL(key string) (string, error) {
+	return "https://cdn/" + key, nil
+}
+
+func (s *savedImagesStorage) DeletePrefix(context.Context, string) error {
+	return nil
+}
+
+func TestCreateMotorcycleAudit(t *testing.T) {
+	admin := &domain.User{ID: "admin"}
+	tests := []struct {
+		name      string
+		photoURLs []string
+		saveErr   error
+		addErr    error
+		// wantStep - шаг, который пишет запись о создании; пусто - журнал не пишется
+		wantStep string
+	}{
+		{name: "without photos", wantStep: "create"},
+		{name: "with photos", photoURLs: []string{"https://example.com/1.jpg"}, wantStep: "photos"},
+		{name: "photos not saved", photoURLs: []string{"https://example.com/1.jpg"}, saveErr: errors.New("timeout")},
+		{name: "photos not added", photoURLs: []string{"https://example.com/1.jpg"}, addErr: errors.New("db down")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &createRecorder{audits: map[string][]*domain.CreateAuditEntry{}, addedErr: tt.addErr}
+			m := &Motorcycle{motorcycleRepo: recorder, storage: &savedImagesStorage{err: tt.saveErr}}
+			ctx := Context{Context: context.Background(), User: admin}
+
+			_, err := m.createMotorcycle(ctx, &domain.CreateMotorcycle{
+				Title:     "Honda CB400",
+				Status:    domain.MotorcycleStatusDraft,
+				PhotoURLs: tt.photoURLs,
+			}, nil)
+			if (err != nil) != (tt.wantStep == "") {
+				t.Fatalf("createMotorcycle() error = %v", err)
+			}
+			if tt.wantStep == "" {
+				if !recorder.deleted || len(recorder.audits) != 0 {
+					t.Errorf("rollback: deleted = %v, audit = %v, want deleted without audit", recorder.deleted, recorder.audits)
+				}
+				return
+			}
+			if len(recorder.audits) != 1 {
+				t.Fatalf("audit steps = %v, want only %q", recorder.audits, tt.wantStep)
+			}
+			entries := recorder.audits[tt.wantStep]
+			if len(entries) != 1 || entries[0].Action != domain.AuditActionCreate || entries[0].ActorID == nil || *entries[0].ActorID != admin.ID {
+				t.Errorf("%s audit = %+v, want one create entry by %s", tt.wantStep, entries, admin.ID)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/utils/slogx"
+)
+
+// recordEvent ставит в очередь рассылки публикацию черновика или изменение цены
+// мотоцикла в каталоге. Изменение уже сохранено, поэтому ошибка только логируется
+func (m *Motorcycle) recordEvent(ctx Context, before, after *domain.Motorcycle) {
+	if after.Status != domain.MotorcycleStatusAvailable || after.ArchivedAt != nil {
+		return
+	}
+
+	event := &domain.CreateMotorcycleEvent{MotorcycleID: after.ID, Price: after.Price}
+	switch {
+	case before.Status == domain.MotorcycleStatusDraft:
+		event.Kind = domain.MotorcycleEventPublished
+	case before.Status == domain.MotorcycleStatusAvailable && after.Price < before.Price:
+		event.Kind = domain.MotorcycleEventPriceDrop
+		event.OldPrice = &before.Price
+	case before.Status == domain.MotorcycleStatusAvailable && after.Price > before.Price:
+		event.Kind = domain.MotorcycleEventPriceRise
+		event.OldPrice = &before.Price
+	default:
+		return
+	}
+
+	if err := m.eventRepo.Create(ctx, event); err != nil {
+		slogx.FromCtxWithErr(ctx, err).Error("failed to record motorcycle event", "motorcycle_id", after.ID, "kind", event.Kind)
+	}
+}
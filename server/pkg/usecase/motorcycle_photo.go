@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/imageproc"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+	"github.com/shampsdev/go-telegram-template/pkg/utils/slogx"
+)
+
+var (
+	// ErrInvalidPhoto - загруженный файл не является изображением
+	ErrInvalidPhoto = errors.New("invalid photo")
+	// ErrInvalidPhotoOrder - новый порядок должен перечислять каждую фотографию ровно один раз
+	ErrInvalidPhotoOrder = errors.New("photo order must list every photo exactly once")
+)
+
+// UploadPhotos добавляет фотографии в конец галереи. Либо сохраняются все файлы, либо ни один
+func (m *Motorcycle) UploadPhotos(ctx Context, id string, files [][]byte) (*domain.Motorcycle, error) {
+	if _, err := m.GetMotorcycle(ctx, id); err != nil {
+		return nil, err
+	}
+
+	photos := make([]*domain.CreateMotorcyclePhoto, 0, len(files))
+	for i, data := range files {
+		photo, err := m.savePhoto(ctx, id, data)
+		if err != nil {
+			m.deletePhotoFiles(ctx, photos...)
+			return nil, fmt.Errorf("failed to save photo %d: %w", i, err)
+		}
+		photos = append(photos, photo)
+	}
+
+	if err := m.motorcycleRepo.AddPhotos(ctx, id, photos, m.auditFunc(ctx, domain.AuditActionPhotoAdd)); err != nil {
+		m.deletePhotoFiles(ctx, photos...)
+		return nil, fmt.Errorf("failed to add photos: %w", err)
+	}
+	return m.GetMotorcycle(ctx, id)
+}
+
+// ReplacePhoto подменяет файл фотографии, не меняя ее место в галерее
+func (m *Motorcycle) ReplacePhoto(ctx Context, id, photoID string, data []byte) (*domain.Motorcycle, error) {
+	old, err := m.getPhoto(ctx, id, photoID)
+	if err != nil {
+		return nil, err
+	}
+
+	photo, err := m.savePhoto(ctx, id, data)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.motorcycleRepo.ReplacePhoto(ctx, id, photoID, photo, m.auditFunc(ctx, domain.AuditActionPhotoReplace)); err != nil {
+		m.deletePhotoFiles(ctx, photo)
+		return nil, fmt.Errorf("failed to replace photo: %w", err)
+	}
+
+	m.deletePhotoFiles(ctx, &domain.CreateMotorcyclePhoto{StorageKey: old.StorageKey})
+	return m.GetMotorcycle(ctx, id)
+}
+
+// DeletePhoto удаляет фотографию из галереи и ее файлы из хранилища
+func (m *Motorcycle) DeletePhoto(ctx Context, id, photoID string) (*domain.Motorcycle, error) {
+	photo, err := m.getPhoto(ctx, id, photoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.motorcycleRepo.DeletePhoto(ctx, id, photoID, m.auditFunc(ctx, domain.AuditActionPhotoDelete)); err != nil {
+		return nil, fmt.Errorf("failed to delete photo: %w", err)
+	}
+
+	m.deletePhotoFiles(ctx, &domain.CreateMotorcyclePhoto{StorageKey: photo.StorageKey})
+	return m.GetMotorcycle(ctx, id)
+}
+
+// ReorderPhotos задает порядок галереи, photoIDs должен содержать все фотографии мотоцикла
+func (m *Motorcycle) ReorderPhotos(ctx Context, id string, photoIDs []string) (*domain.Motorcycle, error) {
+	err := m.motorcycleRepo.ReorderPhotos(ctx, id, func(current []string) ([]string, error) {
+		return photoIDs, checkPhotoOrder(current, photoIDs)
+	}, m.auditFunc(ctx, domain.AuditActionPhotoReorder))
+	if err != nil {
+		return nil, fmt.Errorf("failed to reorder photos: %w", err)
+	}
+	return m.GetMotorcycle(ctx, id)
+}
+
+// SetCoverPhoto делает фотографию первой в галерее, остальные сохраняют порядок
+func (m *Motorcycle) SetCoverPhoto(ctx Context, id, photoID string) (*domain.Motorcycle, error) {
+	err := m.motorcycleRepo.ReorderPhotos(ctx, id, func(current []string) ([]string, error) {
+		return coverFirst(current, photoID)
+	}, m.auditFunc(ctx, domain.AuditActionPhotoReorder))
+	if err != nil {
+		return nil, fmt.Errorf("failed to reorder photos: %w", err)
+	}
+	return m.GetMotorcycle(ctx, id)
+}
+
+// checkPhotoOrder проверяет, что photoIDs перечисляет каждую фотографию из current ровно один раз
+func checkPhotoOrder(current, photoIDs []string) error {
+	if len(photoIDs) != len(current) {
+		return ErrInvalidPhotoOrder
+	}
+	remaining := make(map[string]bool, len(current))
+	for _, photoID := range current {
+		remaining[photoID] = true
+	}
+	for _, photoID := range photoIDs {
+		if !remaining[photoID] {
+			return ErrInvalidPhotoOrder
+		}
+		// Повтор ID тоже ошибка: второй раз его уже не найти
+		delete(remaining, photoID)
+	}
+	return nil
+}
+
+// coverFirst переносит photoID в начало галереи, если такой фотографии нет - repo.ErrNotFound
+func coverFirst(current []string, photoID string) ([]string, error) {
+	photoIDs := []string{photoID}
+	found := false
+	for _, id := range current {
+		if id == photoID {
+			found = true
+			continue
+		}
+		photoIDs = append(photoIDs, id)
+	}
+	if !found {
+		return nil, repo.ErrNotFound
+	}
+	return photoIDs, nil
+}
+
+// getPhoto возвращает фотографию photoID мотоцикла
+func (m *Motorcycle) getPhoto(ctx context.Context, id, photoID string) (*domain.MotorcyclePhoto, error) {
+	motorcycle, err := m.GetMotorcycle(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, photo := range motorcycle.Photos {
+		if photo.ID == photoID {
+			return photo, nil
+		}
+	}
+	return nil, repo.ErrNotFound
+}
+
+// savePhoto сохраняет файл с вариантами размеров под новым ключом мотоцикла
+func (m *Motorcycle) savePhoto(ctx context.Context, id string, data []byte) (*domain.CreateMotorcyclePhoto, error) {
+	key := newPhotoKey(id)
+	saved := m.storage.SaveImageVariants(ctx, data, key)
+	if saved.Err != nil {
+		// Часть вариантов могла успеть сохраниться
+		m.deletePhotoFiles(ctx, &domain.CreateMotorcyclePhoto{StorageKey: key})
+		if errors.Is(saved.Err, imageproc.ErrInvalidImage) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPhoto, saved.Err)
+		}
+		return nil, fmt.Errorf("failed to save photo: %w", saved.Err)
+	}
+	return &domain.CreateMotorcyclePhoto{ObjectKey: saved.Key, StorageKey: key, Variants: saved.Variants}, nil
+}
+
+// deletePhotoFiles удаляет файлы фотографий. Ошибки только логируются:
+// запись в БД уже изменена, а лишние файлы не видны пользователям
+func (m *Motorcycle) deletePhotoFiles(ctx context.Context, photos ...*domain.CreateMotorcyclePhoto) {
+	ctx = context.WithoutCancel(ctx)
+	for _, photo := range photos {
+		if photo.StorageKey == "" {
+			continue
+		}
+		if err := m.storage.DeletePrefix(ctx, photo.StorageKey); err != nil {
+			slogx.FromCtxWithErr(ctx, err).Warn("failed to delete photo files", "key", photo.StorageKey)
+		}
+	}
+}
+
+// newPhotoKey - уникальный префикс файлов одной фотографии: motorcycles/<id>/<uuid>
+func newPhotoKey(motorcycleID string) string {
+	return motorcyclePhotoPrefix(motorcycleID) + uuid.New().String()
+}
@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+)
+
+func TestCheckPhotoOrder(t *testing.T) {
+	current := []string{"a", "b", "c"}
+	tests := []struct {
+		name     string
+		photoIDs []string
+		wantErr  bool
+	}{
+		{name: "same order", photoIDs: []string{"a", "b", "c"}},
+		{name: "permutation", photoIDs: []string{"c", "a", "b"}},
+		{name: "missing photo", photoIDs: []string{"a", "b"}, wantErr: true},
+		{name: "extra photo", photoIDs: []string{"a", "b", "c", "d"}, wantErr: true},
+		{name: "unknown photo", photoIDs: []string{"a", "b", "d"}, wantErr: true},
+		{name: "duplicate", photoIDs: []string{"a", "a", "b"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkPhotoOrder(current, tt.photoIDs)
+			if tt.wantErr != errors.Is(err, ErrInvalidPhotoOrder) {
+				t.Errorf("checkPhotoOrder(%v) = %v, wantErr %v", tt.photoIDs, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCoverFirst(t *testing.T) {
+	got, err := coverFirst([]string{"a", "b", "c"}, "c")
+	if err != nil {
+		t.Fatalf("coverFirst: %v", err)
+	}
+	if want := []string{"c", "a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("coverFirst = %v, want %v", got, want)
+	}
+
+	if _, err := coverFirst([]string{"a"}, "x"); !errors.Is(err, repo.ErrNotFound) {
+		t.Errorf("unknown photo: err = %v, want %v", err, repo.ErrNotFound)
+	}
+}
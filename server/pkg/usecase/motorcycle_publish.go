@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+)
+
+// PublishError - черновик нельзя опубликовать, пока не исправлены Problems
+type PublishError struct {
+	Problems []domain.PublishProblem
+}
+
+func (e *PublishError) Error() string {
+	fields := make([]string, 0, len(e.Problems))
+	for _, problem := range e.Problems {
+		fields = append(fields, problem.Field)
+	}
+	return fmt.Sprintf("motorcycle is not ready to publish, missing: %s", strings.Join(fields, ", "))
+}
+
+// CheckPublish сообщает, чего не хватает мотоциклу для публикации
+func (m *Motorcycle) CheckPublish(ctx Context, id string) (*domain.PublishCheck, error) {
+	motorcycle, err := m.GetMotorcycle(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	problems := m.publishProblems(motorcycle)
+	return &domain.PublishCheck{Ready: len(problems) == 0, Problems: problems}, nil
+}
+
+// PublishMotorcycle переводит черновик в каталог (статус available), если он заполнен
+func (m *Motorcycle) PublishMotorcycle(ctx Context, id string) (*domain.Motorcycle, error) {
+	return m.UpdateMotorcycleStatus(ctx, id, domain.MotorcycleStatusAvailable)
+}
+
+// publishProblems проверяет обязательные для каталога поля
+func (m *Motorcycle) publishProblems(motorcycle *domain.Motorcycle) []domain.PublishProblem {
+	problems := []domain.PublishProblem{}
+	if strings.TrimSpace(motorcycle.Title) == "" {
+		problems = append(problems, domain.PublishProblem{Field: "title", Message: "Не указано название"})
+	}
+	if motorcycle.Price <= 0 {
+		problems = append(problems, domain.PublishProblem{Field: "price", Message: "Не указана цена"})
+	}
+	if len(motorcycle.Photos) < m.photos.MinPublish {
+		problems = append(problems, domain.PublishProblem{
+			Field:   "photos",
+			Message: fmt.Sprintf("Нужно хотя бы %d фото, сейчас %d", m.photos.MinPublish, len(motorcycle.Photos)),
+		})
+	}
+	if motorcycle.Data == nil || strings.TrimSpace(motorcycle.Data.ArrivalDate) == "" {
+		problems = append(problems, domain.PublishProblem{Field: "arrivalDate", Message: "Не указана дата прибытия"})
+	}
+	return problems
+}
@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/shampsdev/go-telegram-template/pkg/config"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+)
+
+// patchRecorder запоминает вызовы Patch; остальные методы репозитория тесту не нужны
+type patchRecorder struct {
+	repo.Motorcycle
+	patched int
+}
+
+func (r *patchRecorder) Patch(context.Context, string, *domain.PatchMotorcycle, int, *string, repo.AuditFunc) error {
+	r.patched++
+	return nil
+}
+
+func TestApplyPatchRejectsIncompleteDraftWithoutWriting(t *testing.T) {
+	recorder := &patchRecorder{}
+	m := &Motorcycle{motorcycleRepo: recorder, photos: config.PhotosConfig{MinPublish: 1}}
+	before := &domain.Motorcycle{
+		ID:     "m1",
+		Title:  "Honda CB400",
+		Status: domain.MotorcycleStatusDraft,
+		Photos: []*domain.MotorcyclePhoto{{ID: "p1"}},
+	}
+
+	price := 450000.0
+	available := domain.MotorcycleStatusAvailable
+	patch := &domain.PatchMotorcycle{Price: &price, Status: &available}
+	_, err := m.applyPatch(Context{Context: context.Background()}, before, patch)
+
+	var publishErr *PublishError
+	if !errors.As(err, &publishErr) {
+		t.Fatalf("applyPatch() = %v, want PublishError", err)
+	}
+	// Цена из патча учтена, не хватает только даты прибытия
+	want := []string{"arrivalDate"}
+	if got := problemFields(publishErr.Problems); !reflect.DeepEqual(got, want) {
+		t.Errorf("problems = %v, want %v", got, want)
+	}
+	if recorder.patched != 0 {
+		t.Errorf("Patch called %d times, want 0", recorder.patched)
+	}
+}
+
+func TestPatchedMotorcycle(t *testing.T) {
+	year := 2004
+	before := &domain.Motorcycle{
+		ID:     "m1",
+		Title:  "Honda CB400",
+		Price:  100,
+		Year:   &year,
+		Status: domain.MotorcycleStatusDraft,
+		Data:   &domain.MotorcycleData{FrameNumber: "NC39-100", ArrivalDate: "2026-01-10"},
+	}
+
+	title := "Honda CB400 Super Four"
+	arrival := "2026-02-01"
+	available := domain.MotorcycleStatusAvailable
+	patched, err := patchedMotorcycle(before, &domain.PatchMotorcycle{
+		Title:  &title,
+		Status: &available,
+		Data:   domain.MotorcycleDataPatch{"arrival_date": &arrival, "frame_number": nil},
+	})
+	if err != nil {
+		t.Fatalf("patchedMotorcycle: %v", err)
+	}
+
+	want := domain.Motorcycle{
+		ID:     "m1",
+		Title:  title,
+		Price:  100,
+		Year:   &year,
+		Status: domain.MotorcycleStatusAvailable,
+		Data:   &domain.MotorcycleData{ArrivalDate: arrival},
+	}
+	if !reflect.DeepEqual(*patched, want) {
+		t.Errorf("patchedMotorcycle() = %+v, want %+v", *patched, want)
+	}
+	// Исходный мотоцикл не меняется
+	if before.Title != "Honda CB400" || before.Data.FrameNumber != "NC39-100" {
+		t.Errorf("before was modified: %+v", *before)
+	}
+}
+
+func problemFields(problems []domain.PublishProblem) []string {
+	fields := make([]string, 0, len(problems))
+	for _, problem := range problems {
+		fields = append(fields, problem.Field)
+	}
+	return fields
+}
@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+)
+
+// ErrInvalidStatus - такого статуса мотоцикла нет
+var ErrInvalidStatus = errors.New("invalid motorcycle status")
+
+// StatusTransitionError - переход между известными статусами, которого нет в графе
+type StatusTransitionError struct {
+	From domain.MotorcycleStatus
+	To   domain.MotorcycleStatus
+}
+
+func (e *StatusTransitionError) Error() string {
+	return fmt.Sprintf("status transition %s -> %s is not allowed, allowed: %v", e.From, e.To, e.From.NextStatuses())
+}
+
+// UpdateMotorcycleStatus переводит мотоцикл в новый статус по графу domain.MotorcycleStatus
+func (m *Motorcycle) UpdateMotorcycleStatus(ctx Context, id string, status domain.MotorcycleStatus) (*domain.Motorcycle, error) {
+	return m.PatchMotorcycle(ctx, id, &domain.PatchMotorcycle{Status: &status})
+}
+
+// StatusHistory возвращает переходы статуса мотоцикла от старых к новым
+func (m *Motorcycle) StatusHistory(ctx Context, id string) ([]*domain.MotorcycleStatusChange, error) {
+	if _, err := m.GetMotorcycle(ctx, id); err != nil {
+		return nil, err
+	}
+	history, err := m.motorcycleRepo.StatusHistory(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status history: %w", err)
+	}
+	return history, nil
+}
+
+// checkStatusTransition проверяет переход; повтор текущего статуса разрешен и ничего не меняет
+func checkStatusTransition(from, to domain.MotorcycleStatus) error {
+	if !to.Valid() {
+		return fmt.Errorf("%w: %q", ErrInvalidStatus, to)
+	}
+	if from == to || from.CanTransitionTo(to) {
+		return nil
+	}
+	return &StatusTransitionError{From: from, To: to}
+}
@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+)
+
+func TestCheckStatusTransition(t *testing.T) {
+	tests := []struct {
+		name           string
+		from, to       domain.MotorcycleStatus
+		wantInvalid    bool
+		wantTransition bool
+	}{
+		{name: "publish draft", from: domain.MotorcycleStatusDraft, to: domain.MotorcycleStatusAvailable},
+		{name: "reserve", from: domain.MotorcycleStatusAvailable, to: domain.MotorcycleStatusReserved},
+		{name: "sell reserved", from: domain.MotorcycleStatusReserved, to: domain.MotorcycleStatusSold},
+		{name: "cancel sale", from: domain.MotorcycleStatusSold, to: domain.MotorcycleStatusAvailable},
+		// Повтор текущего статуса ничего не меняет и ошибкой не считается
+		{name: "same status", from: domain.MotorcycleStatusSold, to: domain.MotorcycleStatusSold},
+		{name: "sell draft", from: domain.MotorcycleStatusDraft, to: domain.MotorcycleStatusSold, wantTransition: true},
+		{name: "reserve sold", from: domain.MotorcycleStatusSold, to: domain.MotorcycleStatusReserved, wantTransition: true},
+		{name: "reserved to draft", from: domain.MotorcycleStatusReserved, to: domain.MotorcycleStatusDraft, wantTransition: true},
+		{name: "unknown status", from: domain.MotorcycleStatusDraft, to: "deleted", wantInvalid: true},
+		{name: "empty status", from: domain.MotorcycleStatusAvailable, to: "", wantInvalid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkStatusTransition(tt.from, tt.to)
+			if got := errors.Is(err, ErrInvalidStatus); got != tt.wantInvalid {
+				t.Errorf("checkStatusTransition(%s, %s) = %v, want invalid status %v", tt.from, tt.to, err, tt.wantInvalid)
+			}
+			var transitionErr *StatusTransitionError
+			if got := errors.As(err, &transitionErr); got != tt.wantTransition {
+				t.Errorf("checkStatusTransition(%s, %s) = %v, want transition error %v", tt.from, tt.to, err, tt.wantTransition)
+			}
+			if !tt.wantInvalid && !tt.wantTransition && err != nil {
+				t.Errorf("checkStatusTransition(%s, %s) = %v, want nil", tt.from, tt.to, err)
+			}
+		})
+	}
+}
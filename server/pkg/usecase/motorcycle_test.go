@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+)
+
+// pageRecorder отдает пустые страницы и считает запросы фасетов
+type pageRecorder struct {
+	repo.Motorcycle
+	facetCalls int
+}
+
+func (r *pageRecorder) FilterPage(_ context.Context, _ *domain.FilterMotorcycle) ([]*domain.Motorcycle, string, error) {
+	return []*domain.Motorcycle{}, "next", nil
+}
+
+func (r *pageRecorder) Facets(_ context.Context, _ *domain.FilterMotorcycle) (*domain.MotorcycleFacets, error) {
+	r.facetCalls++
+	return &domain.MotorcycleFacets{}, nil
+}
+
+func TestListMotorcyclesFacetsOnFirstPage(t *testing.T) {
+	recorder := &pageRecorder{}
+	m := &Motorcycle{motorcycleRepo: recorder}
+
+	first, err := m.ListMotorcycles(context.Background(), &domain.FilterMotorcycle{})
+	if err != nil {
+		t.Fatalf("ListMotorcycles() error = %v", err)
+	}
+	if first.Facets == nil || recorder.facetCalls != 1 {
+		t.Fatalf("first page: facets = %v, facet queries = %d, want facets from 1 query", first.Facets, recorder.facetCalls)
+	}
+
+	next, err := m.ListMotorcycles(context.Background(), &domain.FilterMotorcycle{Cursor: first.NextCursor})
+	if err != nil {
+		t.Fatalf("ListMotorcycles() with cursor error = %v", err)
+	}
+	if next.Facets != nil || recorder.facetCalls != 1 {
+		t.Errorf("next page: facets = %v, facet queries = %d, want no facets and no new query", next.Facets, recorder.facetCalls)
+	}
+}
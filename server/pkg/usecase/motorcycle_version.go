@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"errors"
+
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+)
+
+// maxUpdateAttempts - сколько раз UpdateMotorcycle перечитывает мотоцикл, если его меняют параллельно
+const maxUpdateAttempts = 3
+
+// ErrVersionMismatch - мотоцикл изменился с тех пор, как его прочитали
+var ErrVersionMismatch = errors.New("motorcycle was changed concurrently")
+
+// UpdateMotorcycle читает мотоцикл, строит изменения функцией update и применяет их
+// к прочитанной версии. Если мотоцикл успели изменить, update вызывается заново
+// для свежей версии; после maxUpdateAttempts попыток возвращается ErrVersionMismatch
+func (m *Motorcycle) UpdateMotorcycle(ctx Context, id string, update func(current *domain.Motorcycle) (*domain.PatchMotorcycle, error)) (*domain.Motorcycle, error) {
+	for attempt := 1; ; attempt++ {
+		current, err := m.GetMotorcycle(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		patch, err := update(current)
+		if err != nil {
+			return nil, err
+		}
+
+		updated, err := m.applyPatch(ctx, current, patch)
+		if errors.Is(err, ErrVersionMismatch) && attempt < maxUpdateAttempts {
+			continue
+		}
+		return updated, err
+	}
+}
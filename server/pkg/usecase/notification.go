@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shampsdev/go-telegram-template/pkg/config"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+	"github.com/shampsdev/go-telegram-template/pkg/utils/slogx"
+)
+
+const (
+	// notificationBatch - сколько сообщений отправитель берет за раз
+	notificationBatch = 20
+	// notificationLease - через сколько неотправленное сообщение снова станет доступно
+	notificationLease = time.Minute
+)
+
+// ErrUndeliverable - сообщение доставить нельзя и повторять бессмысленно,
+// например пользователь заблокировал бота
+var ErrUndeliverable = errors.New("notification is undeliverable")
+
+// Notification - очередь сообщений в Telegram. Сообщения ставят API и бот,
+// а отправляет только бот: у API нет клиента Telegram
+type Notification struct {
+	notificationRepo repo.Notification
+	userRepo         repo.User
+	cfg              config.NotificationConfig
+}
+
+func NewNotification(notificationRepo repo.Notification, userRepo repo.User, cfg config.NotificationConfig) *Notification {
+	return &Notification{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		cfg:              cfg,
+	}
+}
+
+// Enqueue ставит сообщения в очередь
+func (n *Notification) Enqueue(ctx context.Context, notifications ...*domain.CreateNotification) error {
+	if err := n.notificationRepo.Create(ctx, notifications); err != nil {
+		return fmt.Errorf("failed to enqueue notifications: %w", err)
+	}
+	return nil
+}
+
+// EnqueueAdmins ставит в очередь копию сообщения для каждого админа, ChatID заполняется сам
+func (n *Notification) EnqueueAdmins(ctx context.Context, notification domain.CreateNotification) error {
+	notifications, err := n.ForAdmins(ctx, notification)
+	if err != nil {
+		return err
+	}
+	return n.Enqueue(ctx, notifications...)
+}
+
+// ForAdmins возвращает копию сообщения для каждого админа, не ставя их в очередь
+func (n *Notification) ForAdmins(ctx context.Context, notification domain.CreateNotification) ([]*domain.CreateNotification, error) {
+	isAdmin := true
+	admins, err := n.userRepo.Filter(ctx, &domain.FilterUser{IsAdmin: &isAdmin})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get admins: %w", err)
+	}
+
+	notifications := make([]*domain.CreateNotification, 0, len(admins))
+	for _, admin := range admins {
+		adminNotification := notification
+		adminNotification.ChatID = admin.TelegramID
+		notifications = append(notifications, &adminNotification)
+	}
+	return notifications, nil
+}
+
+// Deliver отправляет сообщения из очереди через send, пока не отменен ctx
+func (n *Notification) Deliver(ctx context.Context, send func(ctx context.Context, notification *domain.Notification) error) {
+	log := slogx.FromCtx(ctx)
+	log.Info("notification sender started")
+	for {
+		notifications, err := n.notificationRepo.Claim(ctx, notificationBatch, notificationLease)
+		if err != nil && ctx.Err() == nil {
+			log.Error("failed to claim notifications", slogx.Err(err))
+		}
+		for _, notification := range notifications {
+			n.deliver(ctx, notification, send)
+		}
+		// Полная пачка - скорее всего, в очереди есть еще
+		if len(notifications) == notificationBatch {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(n.cfg.PollInterval):
+		}
+	}
+}
+
+func (n *Notification) deliver(ctx context.Context, notification *domain.Notification, send func(ctx context.Context, notification *domain.Notification) error) {
+	log := slogx.FromCtx(ctx).With("notification_id", notification.ID, "chat_id", notification.ChatID, "attempt", notification.Attempts)
+
+	err := send(ctx, notification)
+	if err == nil {
+		if err := n.notificationRepo.Sent(ctx, notification.ID); err != nil {
+			log.Error("failed to mark notification sent", slogx.Err(err))
+		}
+		return
+	}
+
+	if errors.Is(err, ErrUndeliverable) || notification.Attempts >= n.cfg.MaxAttempts {
+		log.Warn("notification failed", slogx.Err(err))
+		if err := n.notificationRepo.Fail(ctx, notification.ID, err.Error()); err != nil {
+			log.Error("failed to mark notification failed", slogx.Err(err))
+		}
+		return
+	}
+
+	delay := n.cfg.RetryBackoff * time.Duration(notification.Attempts)
+	log.Warn("failed to send notification, will retry", slogx.Err(err), "delay", delay)
+	if err := n.notificationRepo.Retry(ctx, notification.ID, err.Error(), delay); err != nil {
+		log.Error("failed to reschedule notification", slogx.Err(err))
+	}
+}
+
+// customerName - как показать покупателя админу: имя и @username, если есть
+func customerName(user *domain.User) string {
+	name := strings.TrimSpace(user.FirstName + " " + user.LastName)
+	if user.TelegramUsername != "" {
+		name += " @" + user.TelegramUsername
+	}
+	return strings.TrimSpace(name)
+}
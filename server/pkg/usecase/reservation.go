@@ -0,0 +1,243 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shampsdev/go-telegram-template/pkg/config"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+	"github.com/shampsdev/go-telegram-template/pkg/utils/slogx"
+)
+
+// Префиксы данных inline-кнопок заявки, за ними следует ID заявки
+const (
+	ReservationApproveData = "resv:approve:"
+	ReservationDeclineData = "resv:decline:"
+)
+
+var (
+	// ErrNotAvailable - забронировать можно только опубликованный мотоцикл в статусе available
+	ErrNotAvailable = errors.New("motorcycle is not available")
+	// ErrReservationDecided - заявку уже подтвердили, отклонили или она истекла
+	ErrReservationDecided = errors.New("reservation is already decided")
+)
+
+// Reservation - заявки покупателей на бронь. Админы получают заявку в боте,
+// подтверждение переводит мотоцикл в reserved на cfg.TTL
+type Reservation struct {
+	reservationRepo repo.Reservation
+	userRepo        repo.User
+	motorcycles     *Motorcycle
+	notifications   *Notification
+	cfg             config.ReservationConfig
+}
+
+func NewReservation(reservationRepo repo.Reservation, userRepo repo.User, motorcycles *Motorcycle, notifications *Notification, cfg config.ReservationConfig) *Reservation {
+	return &Reservation{
+		reservationRepo: reservationRepo,
+		userRepo:        userRepo,
+		motorcycles:     motorcycles,
+		notifications:   notifications,
+		cfg:             cfg,
+	}
+}
+
+// RequestReservation создает заявку пользователя из ctx и рассылает ее админам.
+// Повторная заявка, пока первая ждет решения, - repo.ErrAlreadyExists
+func (r *Reservation) RequestReservation(ctx Context, motorcycleID, comment string) (*domain.Reservation, error) {
+	motorcycle, err := r.motorcycles.GetMotorcycle(ctx, motorcycleID)
+	if err != nil {
+		return nil, err
+	}
+	if motorcycle.ArchivedAt != nil {
+		return nil, repo.ErrNotFound
+	}
+	if motorcycle.Status != domain.MotorcycleStatusAvailable {
+		return nil, ErrNotAvailable
+	}
+
+	id, err := r.reservationRepo.Create(ctx, &domain.CreateReservation{
+		MotorcycleID: motorcycleID,
+		UserID:       ctx.User.ID,
+		Comment:      comment,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reservation: %w", err)
+	}
+	reservation, err := r.GetReservation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Заявка уже сохранена, поэтому ошибку рассылки не возвращаем:
+	// админ увидит заявку в /reservations и в админке
+	if err := r.notifications.EnqueueAdmins(ctx, adminReservationNotification(reservation, motorcycle, ctx.User)); err != nil {
+		slogx.FromCtxWithErr(ctx, err).Error("failed to notify admins about reservation", "reservation_id", id)
+	}
+	return reservation, nil
+}
+
+func (r *Reservation) GetReservation(ctx context.Context, id string) (*domain.Reservation, error) {
+	return repo.First(r.reservationRepo.Filter)(ctx, &domain.FilterReservation{ID: &id})
+}
+
+func (r *Reservation) ListReservations(ctx context.Context, filter *domain.FilterReservation) ([]*domain.Reservation, error) {
+	return r.reservationRepo.Filter(ctx, filter)
+}
+
+// Approve подтверждает заявку от имени админа из ctx: мотоцикл атомарно
+// переходит из available в reserved до now+TTL
+func (r *Reservation) Approve(ctx Context, id string) (*domain.Reservation, error) {
+	reservation, err := r.GetReservation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before, err := r.motorcycles.GetMotorcycle(ctx, reservation.MotorcycleID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.reservationRepo.Approve(ctx, id, ctx.User.ID, time.Now().Add(r.cfg.TTL), r.motorcycles.auditFunc(ctx, domain.AuditActionStatus))
+	if errors.Is(err, repo.ErrConflict) {
+		return nil, r.conflictReason(ctx, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to approve reservation: %w", err)
+	}
+
+	reservation, err = r.GetReservation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	r.notifyCustomer(ctx, reservation, before, fmt.Sprintf(
+		"✅ Бронь подтверждена: %s\nМотоцикл закреплен за вами до %s.",
+		before.Title, reservation.ExpiresAt.Format("02.01.2006 15:04"),
+	))
+	return reservation, nil
+}
+
+// Decline отклоняет заявку от имени админа из ctx
+func (r *Reservation) Decline(ctx Context, id string) (*domain.Reservation, error) {
+	err := r.reservationRepo.Decline(ctx, id, ctx.User.ID)
+	if errors.Is(err, repo.ErrConflict) {
+		return nil, ErrReservationDecided
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decline reservation: %w", err)
+	}
+
+	reservation, err := r.GetReservation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if motorcycle, err := r.motorcycles.GetMotorcycle(ctx, reservation.MotorcycleID); err == nil {
+		r.notifyCustomer(ctx, reservation, motorcycle, fmt.Sprintf("❌ Заявка на бронь отклонена: %s", motorcycle.Title))
+	}
+	return reservation, nil
+}
+
+// NotifyPending заново отправляет админу admin все ожидающие заявки с кнопками.
+// Нужно, если сообщение о заявке не дошло или потерялось в чате
+func (r *Reservation) NotifyPending(ctx context.Context, admin *domain.User) (int, error) {
+	status := domain.ReservationStatusPending
+	reservations, err := r.reservationRepo.Filter(ctx, &domain.FilterReservation{Status: &status})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get pending reservations: %w", err)
+	}
+
+	notifications := make([]*domain.CreateNotification, 0, len(reservations))
+	for _, reservation := range reservations {
+		motorcycle, err := r.motorcycles.GetMotorcycle(ctx, reservation.MotorcycleID)
+		if err != nil {
+			return 0, err
+		}
+		customer, err := repo.First(r.userRepo.Filter)(ctx, &domain.FilterUser{ID: &reservation.UserID})
+		if err != nil {
+			return 0, fmt.Errorf("failed to get customer: %w", err)
+		}
+		notification := adminReservationNotification(reservation, motorcycle, customer)
+		notification.ChatID = admin.TelegramID
+		notifications = append(notifications, &notification)
+	}
+	if err := r.notifications.Enqueue(ctx, notifications...); err != nil {
+		return 0, err
+	}
+	return len(notifications), nil
+}
+
+// conflictReason уточняет, почему репозиторий вернул ErrConflict
+func (r *Reservation) conflictReason(ctx context.Context, id string) error {
+	reservation, err := r.GetReservation(ctx, id)
+	if err != nil {
+		return err
+	}
+	if reservation.Status != domain.ReservationStatusPending {
+		return ErrReservationDecided
+	}
+	return ErrNotAvailable
+}
+
+// notifyCustomer сообщает покупателю о решении. Ошибка только логируется: решение уже принято
+func (r *Reservation) notifyCustomer(ctx context.Context, reservation *domain.Reservation, motorcycle *domain.Motorcycle, text string) {
+	log := slogx.FromCtx(ctx).With("reservation_id", reservation.ID)
+	customer, err := repo.First(r.userRepo.Filter)(ctx, &domain.FilterUser{ID: &reservation.UserID})
+	if err != nil {
+		log.Error("failed to get customer", slogx.Err(err))
+		return
+	}
+	if err := r.notifications.Enqueue(ctx, &domain.CreateNotification{ChatID: customer.TelegramID, Text: text}); err != nil {
+		log.Error("failed to notify customer", slogx.Err(err))
+	}
+}
+
+// RunExpiry снимает истекшие брони каждые cfg.ExpiryInterval до отмены ctx; при 0 сразу выходит.
+// Запускается только в REST-сервере (cmd/server). Покупатель получает сообщение, только если
+// мотоцикл действительно вернулся в каталог
+func (r *Reservation) RunExpiry(ctx context.Context) {
+	if r.cfg.ExpiryInterval <= 0 {
+		return
+	}
+	log := slogx.FromCtx(ctx)
+	log.Info("reservation expiry started", "interval", r.cfg.ExpiryInterval)
+	ticker := time.NewTicker(r.cfg.ExpiryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// Бронь снимает система, поэтому запись в журнале без автора
+		audit := r.motorcycles.auditFunc(Context{Context: ctx}, domain.AuditActionStatus)
+		expired, err := r.reservationRepo.Expire(ctx, time.Now(), audit)
+		if err != nil {
+			log.Error("failed to expire reservations", slogx.Err(err))
+			continue
+		}
+		for _, reservation := range expired {
+			log.Info("reservation expired", "reservation_id", reservation.ID, "motorcycle_id", reservation.MotorcycleID)
+			if motorcycle, err := r.motorcycles.GetMotorcycle(ctx, reservation.MotorcycleID); err == nil {
+				r.notifyCustomer(ctx, reservation, motorcycle, fmt.Sprintf("⌛️ Срок брони истек: %s", motorcycle.Title))
+			}
+		}
+	}
+}
+
+func adminReservationNotification(reservation *domain.Reservation, motorcycle *domain.Motorcycle, customer *domain.User) domain.CreateNotification {
+	text := fmt.Sprintf("📝 Заявка на бронь\n\n%s\nЦена: %.0f %s\nПокупатель: %s", motorcycle.Title, motorcycle.Price, motorcycle.Currency, customerName(customer))
+	if reservation.Comment != "" {
+		text += "\nКомментарий: " + reservation.Comment
+	}
+
+	return domain.CreateNotification{
+		Text: text,
+		Buttons: []domain.NotificationButton{
+			{Text: "✅ Подтвердить", Data: ReservationApproveData + reservation.ID},
+			{Text: "❌ Отклонить", Data: ReservationDeclineData + reservation.ID},
+		},
+	}
+}
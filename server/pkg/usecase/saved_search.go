@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+)
+
+// maxSavedSearches - сколько поисков может сохранить один пользователь
+const maxSavedSearches = 20
+
+// ErrInvalidSavedSearch - пустое название или слишком много поисков
+var ErrInvalidSavedSearch = errors.New("invalid saved search")
+
+// ErrInvalidFilter - неизвестный статус или перепутанные границы диапазона в фильтре поиска или подписки
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// SavedSearch - сохраненные поиски пользователя из ctx. Совпадения с ними
+// присылает матчер подписок, см. Subscription
+type SavedSearch struct {
+	savedSearchRepo repo.SavedSearch
+}
+
+func NewSavedSearch(savedSearchRepo repo.SavedSearch) *SavedSearch {
+	return &SavedSearch{
+		savedSearchRepo: savedSearchRepo,
+	}
+}
+
+func (s *SavedSearch) ListSavedSearches(ctx Context) ([]*domain.SavedSearch, error) {
+	searches, err := s.savedSearchRepo.Filter(ctx, &domain.FilterSavedSearch{UserID: &ctx.User.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get saved searches: %w", err)
+	}
+	return searches, nil
+}
+
+// CreateSavedSearch сохраняет поиск. Название уникально в пределах пользователя,
+// повтор - repo.ErrAlreadyExists
+func (s *SavedSearch) CreateSavedSearch(ctx Context, name string, filter domain.SavedSearchFilter) (*domain.SavedSearch, error) {
+	name, err := validateSavedSearch(name, &filter)
+	if err != nil {
+		return nil, err
+	}
+	searches, err := s.ListSavedSearches(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(searches) >= maxSavedSearches {
+		return nil, fmt.Errorf("%w: at most %d saved searches", ErrInvalidSavedSearch, maxSavedSearches)
+	}
+
+	id, err := s.savedSearchRepo.Create(ctx, &domain.CreateSavedSearch{
+		UserID: ctx.User.ID,
+		Name:   name,
+		Filter: filter,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create saved search: %w", err)
+	}
+	return s.getSavedSearch(ctx, id)
+}
+
+// UpdateSavedSearch заменяет название и фильтр поиска
+func (s *SavedSearch) UpdateSavedSearch(ctx Context, id, name string, filter domain.SavedSearchFilter) (*domain.SavedSearch, error) {
+	name, err := validateSavedSearch(name, &filter)
+	if err != nil {
+		return nil, err
+	}
+	err = s.savedSearchRepo.Update(ctx, id, ctx.User.ID, &domain.UpdateSavedSearch{Name: name, Filter: filter})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update saved search: %w", err)
+	}
+	return s.getSavedSearch(ctx, id)
+}
+
+func (s *SavedSearch) DeleteSavedSearch(ctx Context, id string) error {
+	if err := s.savedSearchRepo.Delete(ctx, id, ctx.User.ID); err != nil {
+		return fmt.Errorf("failed to delete saved search: %w", err)
+	}
+	return nil
+}
+
+func (s *SavedSearch) getSavedSearch(ctx Context, id string) (*domain.SavedSearch, error) {
+	return repo.First(s.savedSearchRepo.Filter)(ctx, &domain.FilterSavedSearch{ID: &id, UserID: &ctx.User.ID})
+}
+
+// validateSavedSearch проверяет поиск и возвращает название без пробелов по краям
+func validateSavedSearch(name string, filter *domain.SavedSearchFilter) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("%w: name is empty", ErrInvalidSavedSearch)
+	}
+	if err := validateFilter(filter); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+func validateFilter(filter *domain.SavedSearchFilter) error {
+	if filter.Status != nil && !filter.Status.Valid() {
+		return fmt.Errorf("%w: unknown status %q", ErrInvalidFilter, *filter.Status)
+	}
+	if filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
+		return fmt.Errorf("%w: minPrice is greater than maxPrice", ErrInvalidFilter)
+	}
+	if filter.MinVolume != nil && filter.MaxVolume != nil && *filter.MinVolume > *filter.MaxVolume {
+		return fmt.Errorf("%w: minVolume is greater than maxVolume", ErrInvalidFilter)
+	}
+	return nil
+}
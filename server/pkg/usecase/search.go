@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+)
+
+var (
+	// "до 50к км", "under 50k km", "<50000км"
+	searchMaxMileageRe = regexp.MustCompile(`(?i)(?:до|меньше|under|below|<)\s*(\d+)\s*(k|к|тыс\.?)?\s*(?:km|км)`)
+	// "400cc", "400 куб", "1300сс"
+	searchVolumeRe = regexp.MustCompile(`(?i)\b(\d{2,4})\s*(?:cc|сс|куб\.?)`)
+	// Отдельное число, похожее на объем двигателя: "Honda 400"
+	searchBareNumberRe = regexp.MustCompile(`(?:^|\s)(\d{2,4})(?:\s|$)`)
+)
+
+const (
+	minSearchVolume = 50
+	maxSearchVolume = 2500
+	minSearchYear   = 1950
+	maxSearchYear   = 2100
+)
+
+// applySearchQuery вытаскивает из строки поиска пробег и объем
+// ("Honda 400 до 50к км") в структурированные фильтры, а остаток
+// оставляет для полнотекстового поиска. Явно заданные фильтры не перезаписываются.
+func applySearchQuery(filter *domain.FilterMotorcycle, query string) {
+	if m := searchMaxMileageRe.FindStringSubmatch(query); m != nil {
+		if mileage, err := strconv.Atoi(m[1]); err == nil {
+			if m[2] != "" {
+				mileage *= 1000
+			}
+			if filter.MaxMileage == nil {
+				filter.MaxMileage = &mileage
+			}
+		}
+		query = strings.Replace(query, m[0], " ", 1)
+	}
+
+	volume := 0
+	if m := searchVolumeRe.FindStringSubmatch(query); m != nil {
+		volume, _ = strconv.Atoi(m[1])
+		query = strings.Replace(query, m[0], " ", 1)
+	} else if m := searchBareNumberRe.FindStringSubmatch(query); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		// Четырехзначные числа из диапазона годов оставляем полнотекстовому поиску
+		isYear := n >= minSearchYear && n <= maxSearchYear
+		if n >= minSearchVolume && n <= maxSearchVolume && !isYear {
+			volume = n
+			query = strings.Replace(query, m[0], " ", 1)
+		}
+	}
+	if volume > 0 && filter.MinVolume == nil && filter.MaxVolume == nil {
+		// Объемы классов указываются округленно: "400" - это и 399, и 398 сс
+		minVolume, maxVolume := volume-volume/10, volume+volume/10
+		filter.MinVolume = &minVolume
+		filter.MaxVolume = &maxVolume
+	}
+
+	query = strings.Join(strings.Fields(query), " ")
+	if query == "" {
+		filter.Query = nil
+		return
+	}
+	filter.Query = &query
+}
@@ -0,0 +1,221 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shampsdev/go-telegram-template/pkg/config"
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+	"github.com/shampsdev/go-telegram-template/pkg/utils/slogx"
+)
+
+const (
+	// MotorcycleStartAppPrefix - параметр запуска мини-приложения, за ним следует ID мотоцикла
+	MotorcycleStartAppPrefix = "moto_"
+
+	// eventBatch - сколько событий каталога матчер берет за раз
+	eventBatch = 20
+	// eventLease - через сколько событие вернется в очередь, если матчер не закончил работу
+	eventLease = 5 * time.Minute
+	// eventMaxAttempts - после стольких неудачных попыток событие больше не разбирается
+	eventMaxAttempts = 5
+)
+
+// Subscription - подписки покупателей и рассылка по ним. Матчер разбирает события
+// каталога (публикация, изменение цены), сверяет мотоцикл с подписками и сохраненными
+// поисками и ставит уведомления в очередь бота вместе с отметкой о событии
+type Subscription struct {
+	subscriptionRepo repo.Subscription
+	savedSearchRepo  repo.SavedSearch
+	eventRepo        repo.MotorcycleEvent
+	motorcycles      *Motorcycle
+	cfg              config.SubscriptionConfig
+}
+
+func NewSubscription(subscriptionRepo repo.Subscription, savedSearchRepo repo.SavedSearch, eventRepo repo.MotorcycleEvent, motorcycles *Motorcycle, cfg config.SubscriptionConfig) *Subscription {
+	return &Subscription{
+		subscriptionRepo: subscriptionRepo,
+		savedSearchRepo:  savedSearchRepo,
+		eventRepo:        eventRepo,
+		motorcycles:      motorcycles,
+		cfg:              cfg,
+	}
+}
+
+// GetSubscription возвращает подписку пользователя из ctx, если ее нет - repo.ErrNotFound
+func (s *Subscription) GetSubscription(ctx Context) (*domain.Subscription, error) {
+	return repo.First(s.subscriptionRepo.Filter)(ctx, &domain.FilterSubscription{UserID: &ctx.User.ID})
+}
+
+// Subscribe подписывает пользователя из ctx или меняет фильтр его подписки
+func (s *Subscription) Subscribe(ctx Context, subscription *domain.SetSubscription) (*domain.Subscription, error) {
+	if err := validateFilter(&subscription.SavedSearchFilter); err != nil {
+		return nil, err
+	}
+	if err := s.subscriptionRepo.Set(ctx, ctx.User.ID, subscription); err != nil {
+		return nil, fmt.Errorf("failed to subscribe: %w", err)
+	}
+	return s.GetSubscription(ctx)
+}
+
+// Unsubscribe отписывает пользователя из ctx; если подписки не было, ничего не делает
+func (s *Subscription) Unsubscribe(ctx Context) error {
+	if err := s.subscriptionRepo.Delete(ctx, ctx.User.ID); err != nil {
+		return fmt.Errorf("failed to unsubscribe: %w", err)
+	}
+	return nil
+}
+
+// RunMatcher разбирает события каталога каждые cfg.MatchInterval до отмены ctx; при 0 сразу выходит.
+// Запускается только в REST-сервере (cmd/server)
+func (s *Subscription) RunMatcher(ctx context.Context) {
+	if s.cfg.MatchInterval <= 0 {
+		return
+	}
+	log := slogx.FromCtx(ctx)
+	log.Info("subscription matcher started", "interval", s.cfg.MatchInterval)
+	for {
+		events, err := s.eventRepo.Claim(ctx, eventBatch, eventMaxAttempts, eventLease)
+		if err != nil && ctx.Err() == nil {
+			log.Error("failed to claim motorcycle events", slogx.Err(err))
+		}
+		for _, event := range events {
+			notifications, err := s.match(ctx, event)
+			if err != nil {
+				// Событие вернется в очередь после окончания аренды
+				log.Error("failed to match motorcycle event", slogx.Err(err), "event_id", event.ID, "attempt", event.Attempts)
+				continue
+			}
+			// Уведомления ставятся вместе с отметкой о событии, поэтому повтор события их не дублирует
+			if err := s.eventRepo.Done(ctx, event.ID, event.Attempts, notifications); err != nil {
+				log.Error("failed to complete motorcycle event", slogx.Err(err), "event_id", event.ID)
+			}
+		}
+		if len(events) == eventBatch {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.cfg.MatchInterval):
+		}
+	}
+}
+
+// match строит уведомления подписчикам и владельцам сохраненных поисков,
+// под которые подходит мотоцикл события. В каждый чат уходит не больше одного сообщения
+func (s *Subscription) match(ctx context.Context, event *domain.MotorcycleEvent) ([]*domain.CreateNotification, error) {
+	motorcycle, err := s.motorcycles.GetMotorcycle(ctx, event.MotorcycleID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Пока событие ждало, мотоцикл могли забронировать, снять или снова поднять цену
+	if motorcycle.Status != domain.MotorcycleStatusAvailable || motorcycle.ArchivedAt != nil || motorcycle.Price > event.Price {
+		return nil, nil
+	}
+
+	// Порядок чатов, подписанные чаты и названия совпавших поисков по чатам
+	chats := []int64{}
+	subscribed := map[int64]bool{}
+	searchNames := map[int64][]string{}
+
+	// О повышении цены подписчикам не сообщаем
+	if event.Kind != domain.MotorcycleEventPriceRise {
+		subscriptions, err := s.subscriptionRepo.Filter(ctx, &domain.FilterSubscription{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get subscriptions: %w", err)
+		}
+		for _, subscription := range subscriptions {
+			if subscription.Matches(motorcycle) {
+				chats = append(chats, subscription.TelegramID)
+				subscribed[subscription.TelegramID] = true
+			}
+		}
+	}
+
+	searches, err := s.savedSearchRepo.Filter(ctx, &domain.FilterSavedSearch{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get saved searches: %w", err)
+	}
+	before := priorMotorcycle(event, motorcycle)
+	for _, search := range searches {
+		if !search.Filter.Matches(motorcycle) || (before != nil && search.Filter.Matches(before)) {
+			continue
+		}
+		if !subscribed[search.TelegramID] && len(searchNames[search.TelegramID]) == 0 {
+			chats = append(chats, search.TelegramID)
+		}
+		searchNames[search.TelegramID] = append(searchNames[search.TelegramID], search.Name)
+	}
+
+	text := eventText(event, motorcycle)
+	notifications := make([]*domain.CreateNotification, 0, len(chats))
+	for _, chatID := range chats {
+		chatText := text
+		if names := searchNames[chatID]; len(names) > 0 {
+			chatText += "\n\n🔎 Сохраненный поиск: " + strings.Join(names, ", ")
+		}
+		if subscribed[chatID] {
+			chatText += "\n\nОтписаться: /unsubscribe"
+		} else {
+			chatText += "\n\nСохраненные поиски можно изменить в каталоге"
+		}
+		notifications = append(notifications, motorcycleNotification(chatID, chatText, motorcycle))
+	}
+	return notifications, nil
+}
+
+// priorMotorcycle - мотоцикл со старой ценой для события повышения цены. Сохраненный
+// поиск срабатывает, только если мотоцикл начал подходить под него после повышения
+func priorMotorcycle(event *domain.MotorcycleEvent, motorcycle *domain.Motorcycle) *domain.Motorcycle {
+	if event.Kind != domain.MotorcycleEventPriceRise || event.OldPrice == nil {
+		return nil
+	}
+	before := *motorcycle
+	before.Price = *event.OldPrice
+	return &before
+}
+
+func eventText(event *domain.MotorcycleEvent, motorcycle *domain.Motorcycle) string {
+	if event.Kind == domain.MotorcycleEventPriceDrop && event.OldPrice != nil {
+		return fmt.Sprintf("📉 Цена снижена\n\n🏍️ %s\n💰 %.0f → %.0f %s", motorcycle.Title, *event.OldPrice, motorcycle.Price, motorcycle.Currency)
+	}
+	if event.Kind == domain.MotorcycleEventPriceRise && event.OldPrice != nil {
+		return fmt.Sprintf("📈 Цена изменена\n\n🏍️ %s\n💰 %.0f → %.0f %s", motorcycle.Title, *event.OldPrice, motorcycle.Price, motorcycle.Currency)
+	}
+	return fmt.Sprintf("🆕 Новый мотоцикл в каталоге\n\n🏍️ %s\n💰 %.0f %s", motorcycle.Title, motorcycle.Price, motorcycle.Currency)
+}
+
+// motorcycleNotification - сообщение о мотоцикле с обложкой и кнопкой, открывающей его в мини-приложении
+func motorcycleNotification(chatID int64, text string, motorcycle *domain.Motorcycle) *domain.CreateNotification {
+	return &domain.CreateNotification{
+		ChatID:   chatID,
+		Text:     text,
+		PhotoURL: coverPhotoURL(motorcycle),
+		Buttons: []domain.NotificationButton{
+			{Text: "Открыть в каталоге", StartApp: MotorcycleStartAppPrefix + motorcycle.ID},
+		},
+	}
+}
+
+// coverPhotoURL выбирает для Telegram JPEG-вариант обложки среднего размера
+func coverPhotoURL(motorcycle *domain.Motorcycle) string {
+	if len(motorcycle.Photos) == 0 {
+		return ""
+	}
+	cover := motorcycle.Photos[0]
+	for _, variant := range cover.Variants {
+		if variant.Name == domain.PhotoVariantMedium && variant.Format == domain.PhotoFormatJPEG {
+			return variant.URL
+		}
+	}
+	return cover.S3URL
+}
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/shampsdev/go-telegram-template/pkg/domain"
+)
+
+func TestPriorMotorcycle(t *testing.T) {
+	oldPrice := 400000.0
+	tests := []struct {
+		name      string
+		event     domain.MotorcycleEvent
+		wantPrice *float64
+	}{
+		{name: "price rise", event: domain.MotorcycleEvent{Kind: domain.MotorcycleEventPriceRise, OldPrice: &oldPrice}, wantPrice: &oldPrice},
+		{name: "price rise without old price", event: domain.MotorcycleEvent{Kind: domain.MotorcycleEventPriceRise}},
+		{name: "price drop", event: domain.MotorcycleEvent{Kind: domain.MotorcycleEventPriceDrop, OldPrice: &oldPrice}},
+		{name: "published", event: domain.MotorcycleEvent{Kind: domain.MotorcycleEventPublished}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			motorcycle := &domain.Motorcycle{ID: "m1", Brand: "Honda", Price: 500000}
+			got := priorMotorcycle(&tt.event, motorcycle)
+			if tt.wantPrice == nil {
+				if got != nil {
+					t.Fatalf("priorMotorcycle() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatalf("priorMotorcycle() = nil, want price %v", *tt.wantPrice)
+			}
+			if got.Price != *tt.wantPrice || got.ID != motorcycle.ID || got.Brand != motorcycle.Brand {
+				t.Errorf("priorMotorcycle() = %+v, want a copy with price %v", got, *tt.wantPrice)
+			}
+			if motorcycle.Price != 500000 {
+				t.Errorf("priorMotorcycle() changed the motorcycle price to %v", motorcycle.Price)
+			}
+		})
+	}
+}
+
+// Поиск с минимальной ценой срабатывает на повышение, только если мотоцикл
+// до повышения под него не подходил
+func TestPriorMotorcycleSavedSearch(t *testing.T) {
+	minPrice := 450000.0
+	filter := domain.SavedSearchFilter{MinPrice: &minPrice}
+	motorcycle := &domain.Motorcycle{Price: 500000}
+	for _, old := range []struct {
+		price float64
+		want  bool
+	}{{price: 400000, want: true}, {price: 460000, want: false}} {
+		event := &domain.MotorcycleEvent{Kind: domain.MotorcycleEventPriceRise, OldPrice: &old.price, Price: motorcycle.Price}
+		before := priorMotorcycle(event, motorcycle)
+		got := filter.Matches(motorcycle) && !filter.Matches(before)
+		if got != old.want {
+			t.Errorf("rise from %v: notify = %v, want %v", old.price, got, old.want)
+		}
+	}
+}
@@ -2,10 +2,15 @@ package usecase
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/shampsdev/go-telegram-template/pkg/config"
+	"github.com/shampsdev/go-telegram-template/pkg/imageproc"
 	"github.com/shampsdev/go-telegram-template/pkg/parser"
+	"github.com/shampsdev/go-telegram-template/pkg/repo"
+	"github.com/shampsdev/go-telegram-template/pkg/repo/disk"
+	"github.com/shampsdev/go-telegram-template/pkg/repo/ingest"
 	"github.com/shampsdev/go-telegram-template/pkg/repo/pg"
 	"github.com/shampsdev/go-telegram-template/pkg/repo/s3"
 )
@@ -13,28 +18,91 @@ import (
 type Cases struct {
 	User       *User
 	Motorcycle *Motorcycle
+	Brand      *Brand
+	Import     *Import
 	Analytics  *Analytics
+	GC         *GC
+	Favorite   *Favorite
+
+	Reservation  *Reservation
+	Notification *Notification
+	Inquiry      *Inquiry
+	Subscription *Subscription
+	SavedSearch  *SavedSearch
 }
 
-func Setup(ctx context.Context, cfg *config.Config, db *pgxpool.Pool) Cases {
+func Setup(ctx context.Context, cfg *config.Config, db *pgxpool.Pool) (Cases, error) {
 	userRepo := pg.NewUserRepo(db)
 	motorcycleRepo := pg.NewMotorcycleRepo(db)
+	brandRepo := pg.NewBrandRepo(db)
+	importJobRepo := pg.NewImportJobRepo(db)
 	analyticsRepo := pg.NewAnalyticsRepo(db)
+	eventRepo := pg.NewMotorcycleEventRepo(db)
 
-	storage, err := s3.NewStorage(cfg.S3)
+	storage, err := newImageStorage(cfg, ingest.NewFetcher(cfg.Ingest, imageproc.NewProcessor(ctx, cfg.Images)))
 	if err != nil {
-		panic(err)
+		return Cases{}, fmt.Errorf("failed to setup image storage: %w", err)
 	}
 
-	motorcycleParser := parser.NewMotorcycleParser()
+	motorcycleParser := parser.NewDefaultRegistry()
 
 	userCase := NewUser(ctx, userRepo, storage)
-	motorcycleCase := NewMotorcycle(motorcycleRepo, storage, motorcycleParser)
+	motorcycleCase := NewMotorcycle(motorcycleRepo, pg.NewAuditRepo(db), eventRepo, storage, motorcycleParser, cfg.Photos)
+	brandCase, err := NewBrand(ctx, brandRepo, motorcycleParser)
+	if err != nil {
+		return Cases{}, err
+	}
+	importCase := NewImport(importJobRepo, userRepo, motorcycleCase, cfg.Import)
 	analyticsCase := NewAnalytics(analyticsRepo)
+	gcCase := NewGC(pg.NewStorageRefRepo(db), storage, cfg.GC)
+	favoriteCase := NewFavorite(pg.NewFavoriteRepo(db), motorcycleCase)
+	notificationCase := NewNotification(pg.NewNotificationRepo(db), userRepo, cfg.Notification)
+	inquiryCase := NewInquiry(pg.NewInquiryRepo(db), userRepo, motorcycleCase, notificationCase)
+	reservationCase := NewReservation(pg.NewReservationRepo(db), userRepo, motorcycleCase, notificationCase, cfg.Reservation)
+	savedSearchRepo := pg.NewSavedSearchRepo(db)
+	savedSearchCase := NewSavedSearch(savedSearchRepo)
+	subscriptionCase := NewSubscription(pg.NewSubscriptionRepo(db), savedSearchRepo, eventRepo, motorcycleCase, cfg.Subscription)
 
 	return Cases{
 		User:       userCase,
 		Motorcycle: motorcycleCase,
+		Brand:      brandCase,
+		Import:     importCase,
 		Analytics:  analyticsCase,
+		GC:         gcCase,
+		Favorite:   favoriteCase,
+
+		Reservation:  reservationCase,
+		Notification: notificationCase,
+		Inquiry:      inquiryCase,
+		Subscription: subscriptionCase,
+		SavedSearch:  savedSearchCase,
+	}, nil
+}
+
+// SetupGC собирает только сборщик мусора, без фоновых воркеров остальных сценариев. Для cmd/gc
+func SetupGC(ctx context.Context, cfg *config.Config, db *pgxpool.Pool) (*GC, error) {
+	storage, err := newImageStorage(cfg, ingest.NewFetcher(cfg.Ingest, nil))
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup image storage: %w", err)
+	}
+
+	gcConfig := cfg.GC
+	gcConfig.Interval = 0
+	return NewGC(pg.NewStorageRefRepo(db), storage, gcConfig), nil
+}
+
+// newImageStorage выбирает хранилище изображений по STORAGE_BACKEND
+func newImageStorage(cfg *config.Config, fetcher *ingest.Fetcher) (repo.ImageStorage, error) {
+	switch cfg.Storage.Backend {
+	case config.StorageBackendS3:
+		if cfg.S3.Bucket == "" || cfg.S3.EndpointUrl == "" {
+			return nil, fmt.Errorf("S3_BUCKET and S3_ENDPOINT_URL are required for s3 storage, or set STORAGE_BACKEND=%s", config.StorageBackendDisk)
+		}
+		return s3.NewStorage(cfg.S3, cfg.Storage, fetcher)
+	case config.StorageBackendDisk:
+		return disk.NewStorage(cfg.Storage, fetcher)
+	default:
+		return nil, fmt.Errorf("unknown storage backend: %q", cfg.Storage.Backend)
 	}
 }
@@ -78,11 +78,30 @@ func (u *User) CreateMe(ctx ContextWithTGData, createUser *domain.CreateUser) (*
 	if err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
-	return repo.First(u.userRepo.Filter)(ctx, &domain.FilterUser{ID: &id})
+	user, err := repo.First(u.userRepo.Filter)(ctx, &domain.FilterUser{ID: &id})
+	if err != nil {
+		return nil, err
+	}
+	return u.withAvatarURL(user)
 }
 
 func (u *User) GetMe(ctx Context) (*domain.User, error) {
-	return ctx.User, nil
+	return u.withAvatarURL(ctx.User)
+}
+
+// withAvatarURL возвращает копию пользователя со ссылкой на аватар вместо ключа.
+// Сам пользователь не меняется: он лежит в tgDataCache, и там должен остаться ключ
+func (u *User) withAvatarURL(user *domain.User) (*domain.User, error) {
+	if user.Avatar == "" {
+		return user, nil
+	}
+	avatarURL, err := u.storage.URL(user.Avatar)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve avatar url: %w", err)
+	}
+	resolved := *user
+	resolved.Avatar = avatarURL
+	return &resolved, nil
 }
 
 func (u *User) GetByTGData(ctx context.Context, tgData *domain.UserTGData) (*domain.User, error) {